@@ -4,54 +4,61 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
+	"os"
 
-	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
 	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/alerts"
 	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
 	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/metrics"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/profile"
 	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/services"
 )
 
-var client http.Client
-
-const (
-	endpointURL string = "http://10.200.134.26:19091"
-	elementName string = "ufi09.q2c.u19.r4.nbg.rtbrick.net"
-	accessToken string = ""
-)
-
+// The integration test reads the CTRLD endpoint, the element name and the credentials from the profile file
+// (see package profile), e.g. by exporting RBFS_ENDPOINT, RBFS_ELEMENT and RBFS_ACCESS_TOKEN.
+//
 //nolint:forbidigo  // this is an integration test
 func main() {
-	m := metrics.NewClient(&client)
-	endpoint, _ := url.Parse(endpointURL)
-	ctx, _ := rbfs.NewRbfsContext(context.Background(), endpoint, elementName, rbfs.RbfsAccessToken(accessToken))
+	config, err := profile.LoadDefault()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	p, err := config.Profile("")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	client, ctx, err := p.Connect(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 
+	m := metrics.NewClient(client)
 	metric, err := m.QueryMetric(ctx, "chassis_temperature_millicelsius")
 	fmt.Println(err)
 	b, _ := json.MarshalIndent(metric, " ", " ")
 	fmt.Println(string(b))
 
-	a := alerts.NewClient(&client)
+	a := alerts.NewClient(client)
 	alerts, err := a.QueryAlerts(ctx)
 	fmt.Println(err)
 	b, _ = json.MarshalIndent(alerts, " ", " ")
 	fmt.Println(string(b))
 
-	s := services.NewClient(&client)
+	s := services.NewClient(client)
 	services, err := s.ListServices(ctx)
 	fmt.Println(err)
 	b, _ = json.MarshalIndent(services, " ", " ")
 	fmt.Println(string(b))
 
-	e := elements.NewClient(&client)
+	e := elements.NewClient(client)
 	elements, err := e.ListElements(ctx)
 	fmt.Println(err)
 	b, _ = json.MarshalIndent(elements, " ", " ")
 	fmt.Println(string(b))
 
-	element, err := e.GetElement(ctx, "ufi09.q2c.u19.r4.nbg.rtbrick.net")
+	element, err := e.GetElement(ctx, p.DefaultElement())
 	fmt.Println(err)
 	b, _ = json.MarshalIndent(element, " ", " ")
 	fmt.Println(string(b))
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/sla"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/profile"
+)
+
+// The SLA probe runs the probes configured in the SLA configuration file (see package sla) on the elements managed
+// by the CTRLD instance of the selected profile (see package profile) and serves the results as Prometheus metrics.
+//
+//nolint:forbidigo  // this is a command line tool
+func main() {
+	configPath := flag.String("config", "sla.yaml", "SLA configuration file")
+	profileName := flag.String("profile", "", "configuration profile name")
+	listen := flag.String("listen", ":9115", "metrics listen address")
+	flag.Parse()
+
+	if err := run(*configPath, *profileName, *listen); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath, profileName, listen string) error {
+	config, err := sla.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	if len(config.Probes) == 0 {
+		return fmt.Errorf("no probes configured in %s", configPath)
+	}
+	profiles, err := profile.LoadDefault()
+	if err != nil {
+		return err
+	}
+	p, err := profiles.Profile(profileName)
+	if err != nil {
+		return err
+	}
+	client, err := p.HTTPClient()
+	if err != nil {
+		return err
+	}
+	scheduler, err := sla.NewScheduler(ping.NewPingService(client), config)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// The probes address their elements themselves, hence the profile does not need to specify a default element.
+	rbfsCtx, err := p.NewRbfsContext(ctx, config.Probes[0].Element)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", scheduler.Handler())
+	server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.Run(rbfsCtx)
+	}()
+
+	select {
+	case err = <-serverErr:
+		stop()
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = server.Shutdown(shutdownCtx)
+	}
+	<-done
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -5,7 +5,7 @@
 # SPDX-License-Identifier: BSD-3-Clause
 #
 
-# curl https://documents.rtbrick.com/techdocs/current/api/_attachments/rbfs/swagger_opsd.yaml --output opsd-openapi.yaml
+# curl https://documents.rtbrick.com/techdocs/current/api/_attachments/rbfs/swagger_opsd.yaml --output internal/openapi/opsd-openapi.yaml
 
 # Generate code from scratch to avoid preserving previsouly generated code the recent API specification does not generate anymore
 # rm -r ./pkg/rbfs/state
@@ -16,7 +16,7 @@ docker run --rm -v ${PWD}:/local swaggerapi/swagger-codegen-cli-v3 generate \
     -l go                         \
     -c "/local/opsd-config.json"  \
     -o "/local/pkg/rbfs/state"    \
-    -i "/local/opsd-openapi.yaml"
+    -i "/local/internal/openapi/opsd-openapi.yaml"
 
 # Remove unneeded resources
 rm ./pkg/rbfs/state/.travis.yml
@@ -27,7 +27,12 @@ rm ./pkg/rbfs/state/git_push.sh
 rm ./pkg/rbfs/state/README.md
 rm -rf ./pkg/rbfs/state/docs
 rm -rf ./pkg/rbfs/state/api
-#rm opsd-openapi.yaml
+#rm internal/openapi/opsd-openapi.yaml
+
+# Generate API service interfaces and mocks
+go generate ./pkg/rbfs/state
+# Generate model and operation samples
+go generate ./pkg/rbfstest/fixtures
 
 # Format generated go code
 make fumpt
\ No newline at end of file
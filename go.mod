@@ -6,11 +6,11 @@ require (
 	github.com/antihax/optional v1.0.0
 	github.com/stretchr/testify v1.10.0
 	golang.org/x/oauth2 v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
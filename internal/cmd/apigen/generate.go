@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"path"
+	"sort"
+	"strings"
+)
+
+const header = `/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Code generated by apigen. DO NOT EDIT.
+
+`
+
+// typePrinter renders type expressions of the generated opsd client package.
+type typePrinter struct {
+	// qualifier holds the package name used to qualify the types of the generated package, if any.
+	qualifier string
+	imports   map[string]string
+	used      map[string]bool
+}
+
+func newTypePrinter(qualifier string, imports map[string]string) *typePrinter {
+	return &typePrinter{qualifier: qualifier, imports: imports, used: make(map[string]bool)}
+}
+
+func (p *typePrinter) print(expr ast.Expr) (string, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if p.qualifier != "" && t.IsExported() {
+			return p.qualifier + "." + t.Name, nil
+		}
+		return t.Name, nil
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		if !ok {
+			return "", fmt.Errorf("unsupported selector %T", t.X)
+		}
+		if _, ok := p.imports[pkg.Name]; !ok {
+			return "", fmt.Errorf("unknown package %s", pkg.Name)
+		}
+		p.used[pkg.Name] = true
+		return pkg.Name + "." + t.Sel.Name, nil
+	case *ast.StarExpr:
+		elem, err := p.print(t.X)
+		return "*" + elem, err
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return "", fmt.Errorf("unsupported array type")
+		}
+		elem, err := p.print(t.Elt)
+		return "[]" + elem, err
+	case *ast.MapType:
+		key, err := p.print(t.Key)
+		if err != nil {
+			return "", err
+		}
+		value, err := p.print(t.Value)
+		return "map[" + key + "]" + value, err
+	case *ast.InterfaceType:
+		if t.Methods != nil && len(t.Methods.List) > 0 {
+			return "", fmt.Errorf("unsupported interface type")
+		}
+		return "interface{}", nil
+	case *ast.Ellipsis:
+		elem, err := p.print(t.Elt)
+		return "..." + elem, err
+	default:
+		return "", fmt.Errorf("unsupported type %T", expr)
+	}
+}
+
+// signature renders the parameters and results of the given method.
+func (p *typePrinter) signature(m method) (params, results string, err error) {
+	var list []string
+	for _, param := range m.Params {
+		t, err := p.print(param.Type)
+		if err != nil {
+			return "", "", err
+		}
+		list = append(list, param.Name+" "+t)
+	}
+	params = strings.Join(list, ", ")
+
+	list = nil
+	for _, result := range m.Results {
+		t, err := p.print(result)
+		if err != nil {
+			return "", "", err
+		}
+		list = append(list, t)
+	}
+	results = strings.Join(list, ", ")
+	if len(list) > 1 {
+		results = "(" + results + ")"
+	}
+	return params, results, nil
+}
+
+// importBlock renders the import declaration of the used and the additional packages.
+func (p *typePrinter) importBlock(additional ...string) string {
+	var paths []string
+	for name := range p.used {
+		paths = append(paths, p.imports[name])
+	}
+	paths = append(paths, additional...)
+	sort.Strings(paths)
+
+	var std, other []string
+	for _, importPath := range paths {
+		if strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".") {
+			other = append(other, fmt.Sprintf("%q", importPath))
+		} else {
+			std = append(std, fmt.Sprintf("%q", importPath))
+		}
+	}
+	block := "import (\n" + strings.Join(std, "\n")
+	if len(std) > 0 && len(other) > 0 {
+		block += "\n\n"
+	}
+	return block + strings.Join(other, "\n") + "\n)\n\n"
+}
+
+// generateInterfaces generates the interfaces of the API services in the generated package.
+func generateInterfaces(s *services) ([]byte, error) {
+	p := newTypePrinter("", s.Imports)
+	var body bytes.Buffer
+	for _, svc := range s.List {
+		fmt.Fprintf(&body, "// %s describes the operations of the %s.\n", svc.Name, svc.Type)
+		fmt.Fprintf(&body, "type %s interface {\n", svc.Name)
+		for _, m := range svc.Methods {
+			params, results, err := p.signature(m)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", svc.Type, m.Name, err)
+			}
+			if m.Summary != "" {
+				fmt.Fprintf(&body, "// %s %s\n", m.Name, m.Summary)
+			}
+			fmt.Fprintf(&body, "%s(%s) %s\n", m.Name, params, results)
+		}
+		fmt.Fprintf(&body, "}\n\n")
+		fmt.Fprintf(&body, "// ensure, that %s does implement %s.\n", svc.Type, svc.Name)
+		fmt.Fprintf(&body, "var _ %s = &%s{}\n\n", svc.Name, svc.Type)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(header)
+	out.WriteString("package state\n\n")
+	out.WriteString(p.importBlock())
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// generateMocks generates testify mocks of the API service interfaces in the package statemock.
+func generateMocks(s *services, pkgPath string) ([]byte, error) {
+	qualifier := path.Base(pkgPath)
+	p := newTypePrinter(qualifier, s.Imports)
+	var body bytes.Buffer
+	for _, svc := range s.List {
+		fmt.Fprintf(&body, "// ensure, that %s does implement %s.%s.\n", svc.Name, qualifier, svc.Name)
+		fmt.Fprintf(&body, "var _ %s.%s = &%s{}\n\n", qualifier, svc.Name, svc.Name)
+		fmt.Fprintf(&body, "// %s is a mock of %s.%s.\n", svc.Name, qualifier, svc.Name)
+		fmt.Fprintf(&body, "type %s struct {\nmock.Mock\n}\n\n", svc.Name)
+		for _, m := range svc.Methods {
+			if err := writeMockMethod(&body, p, svc, m); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", svc.Type, m.Name, err)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString(header)
+	out.WriteString("// Package statemock provides testify mocks of the opsd API services.\n")
+	out.WriteString("package statemock\n\n")
+	out.WriteString(p.importBlock(pkgPath, "github.com/stretchr/testify/mock"))
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+func writeMockMethod(w *bytes.Buffer, p *typePrinter, svc service, m method) error {
+	params, results, err := p.signature(m)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, param := range m.Params {
+		names = append(names, param.Name)
+	}
+
+	fmt.Fprintf(w, "// %s mocks %s.%s.%s.\n", m.Name, p.qualifier, svc.Name, m.Name)
+	fmt.Fprintf(w, "func (m *%s) %s(%s) %s {\n", svc.Name, m.Name, params, results)
+	fmt.Fprintf(w, "args := m.Called(%s)\n", strings.Join(names, ", "))
+	var returns []string
+	for i, result := range m.Results[:len(m.Results)-1] {
+		t, err := p.print(result)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "r%d, _ := args.Get(%d).(%s)\n", i, i, t)
+		returns = append(returns, fmt.Sprintf("r%d", i))
+	}
+	returns = append(returns, fmt.Sprintf("args.Error(%d)", len(m.Results)-1))
+	fmt.Fprintf(w, "return %s\n}\n\n", strings.Join(returns, ", "))
+	return nil
+}
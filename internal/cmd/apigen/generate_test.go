@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const stateDir = "../../../pkg/rbfs/state"
+
+// TestGenerate verifies that the generated interfaces and mocks are up to date with the generated opsd client.
+func TestGenerate(t *testing.T) {
+	services, err := parseServices(stateDir, "service_interfaces.go")
+	require.NoError(t, err)
+	require.NotEmpty(t, services.List)
+
+	tests := []struct {
+		name     string
+		file     string
+		generate func() ([]byte, error)
+	}{
+		{
+			name: "interfaces",
+			file: "service_interfaces.go",
+			generate: func() ([]byte, error) {
+				return generateInterfaces(services)
+			},
+		},
+		{
+			name: "mocks",
+			file: "statemock/statemock.go",
+			generate: func() ([]byte, error) {
+				return generateMocks(services, "github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generated, err := tt.generate()
+			require.NoError(t, err)
+			existing, err := os.ReadFile(filepath.Join(stateDir, tt.file))
+			require.NoError(t, err)
+			require.Equal(t, string(existing), string(generated), "%s is outdated, run go generate ./pkg/rbfs/state", tt.file)
+		})
+	}
+}
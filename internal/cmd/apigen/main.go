@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Command apigen generates Go interfaces for the API services of the generated opsd client and testify mocks
+// implementing these interfaces.
+//
+// apigen is invoked by go generate in the state package after the opsd client has been regenerated from the
+// OpenAPI specification.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the generated opsd client package")
+	pkgPath := flag.String("pkg", "github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state", "import path of the generated opsd client package")
+	interfacesFile := flag.String("interfaces", "service_interfaces.go", "output file of the API interfaces, relative to dir")
+	mocksFile := flag.String("mocks", "statemock/statemock.go", "output file of the API mocks, relative to dir")
+	flag.Parse()
+
+	services, err := parseServices(*dir, filepath.Base(*interfacesFile))
+	if err != nil {
+		log.Fatalf("cannot parse API services: %v", err)
+	}
+
+	interfaces, err := generateInterfaces(services)
+	if err != nil {
+		log.Fatalf("cannot generate API interfaces: %v", err)
+	}
+	mocks, err := generateMocks(services, *pkgPath)
+	if err != nil {
+		log.Fatalf("cannot generate API mocks: %v", err)
+	}
+
+	if err := writeFile(filepath.Join(*dir, *interfacesFile), interfaces); err != nil {
+		log.Fatal(err)
+	}
+	if err := writeFile(filepath.Join(*dir, *mocksFile), mocks); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeFile(name string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, content, 0o644)
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const serviceSuffix = "ApiService"
+
+type (
+	// service describes a generated API service.
+	service struct {
+		// Name holds the interface name (e.g. BGPApi).
+		Name string
+		// Type holds the service type name (e.g. BGPApiService).
+		Type string
+		// Methods holds the service methods sorted by name.
+		Methods []method
+	}
+
+	// method describes a method of a generated API service.
+	method struct {
+		Name    string
+		Summary string
+		Params  []param
+		Results []ast.Expr
+	}
+
+	param struct {
+		Name string
+		Type ast.Expr
+	}
+
+	// services holds the parsed API services and the imports of the files declaring them.
+	services struct {
+		List []service
+		// Imports maps the package names used by the service methods to their import paths.
+		Imports map[string]string
+	}
+)
+
+// parseServices parses the API services of the generated opsd client in the given directory.
+// The file with the given name is skipped, as it holds the generated interfaces.
+func parseServices(dir, skip string) (*services, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	byType := make(map[string]*service)
+	imports := make(map[string]string)
+	for _, file := range files {
+		if filepath.Base(file) == skip || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range f.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			name := path[strings.LastIndex(path, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports[name] = path
+		}
+		var previous token.Pos
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			start := previous
+			previous = fn.End()
+			if fn.Recv == nil || !fn.Name.IsExported() {
+				continue
+			}
+			typeName := receiverType(fn.Recv.List[0].Type)
+			if !strings.HasSuffix(typeName, serviceSuffix) {
+				continue
+			}
+			s, ok := byType[typeName]
+			if !ok {
+				s = &service{Name: strings.TrimSuffix(typeName, "Service"), Type: typeName}
+				byType[typeName] = s
+			}
+			m, err := parseMethod(typeName, fn, methodDoc(f, typeName, start, fn))
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", typeName, fn.Name.Name, err)
+			}
+			s.Methods = append(s.Methods, m)
+		}
+	}
+
+	result := &services{Imports: imports}
+	for _, s := range byType {
+		sort.Slice(s.Methods, func(i, j int) bool {
+			return s.Methods[i].Name < s.Methods[j].Name
+		})
+		result.List = append(result.List, *s)
+	}
+	sort.Slice(result.List, func(i, j int) bool {
+		return result.List[i].Name < result.List[j].Name
+	})
+	return result, nil
+}
+
+func receiverType(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// methodDoc returns the documentation of the given method. The generated documentation precedes the options
+// type of the method, if the method has optional parameters.
+func methodDoc(f *ast.File, typeName string, start token.Pos, fn *ast.FuncDecl) *ast.CommentGroup {
+	if fn.Doc != nil {
+		return fn.Doc
+	}
+	var doc *ast.CommentGroup
+	for _, group := range f.Comments {
+		if group.Pos() > start && group.End() < fn.Pos() && strings.HasPrefix(group.Text(), typeName+" ") {
+			doc = group
+		}
+	}
+	return doc
+}
+
+func parseMethod(typeName string, fn *ast.FuncDecl, doc *ast.CommentGroup) (method, error) {
+	m := method{
+		Name:    fn.Name.Name,
+		Summary: summary(typeName, doc),
+	}
+	for i, field := range fn.Type.Params.List {
+		if len(field.Names) == 0 {
+			m.Params = append(m.Params, param{Name: fmt.Sprintf("arg%d", i), Type: field.Type})
+		}
+		for _, name := range field.Names {
+			m.Params = append(m.Params, param{Name: name.Name, Type: field.Type})
+		}
+	}
+	if fn.Type.Results == nil {
+		return m, fmt.Errorf("method without results")
+	}
+	for _, field := range fn.Type.Results.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			m.Results = append(m.Results, field.Type)
+		}
+	}
+	if last, ok := m.Results[len(m.Results)-1].(*ast.Ident); !ok || last.Name != "error" {
+		return m, fmt.Errorf("last result is not an error")
+	}
+	return m, nil
+}
+
+// summary extracts the operation summary from the method documentation, which starts with the service type name
+// followed by the summary.
+func summary(typeName string, doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	line := strings.TrimSpace(strings.SplitN(doc.Text(), "\n", 2)[0])
+	line = strings.TrimSpace(strings.TrimPrefix(line, typeName))
+	if line == "" {
+		return ""
+	}
+	// Lower the first letter unless the summary starts with an abbreviation
+	first, size := utf8.DecodeRuneInString(line)
+	if next, _ := utf8.DecodeRuneInString(line[size:]); !unicode.IsUpper(next) {
+		line = string(unicode.ToLower(first)) + line[size:]
+	}
+	if !strings.HasSuffix(line, ".") {
+		line += "."
+	}
+	return line
+}
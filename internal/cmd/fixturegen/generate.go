@@ -0,0 +1,171 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/openapi"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfstest/fixtures"
+)
+
+const header = `/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Code generated by fixturegen. DO NOT EDIT.
+
+`
+
+// parseTypes returns the names of the types declared by the generated opsd client package.
+func parseTypes(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "model_*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var types []string
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				types = append(types, spec.(*ast.TypeSpec).Name.Name)
+			}
+		}
+	}
+	return types, nil
+}
+
+// typeKey returns the key to match schema names with the type names of the generated opsd client, which
+// camel-cases abbreviations (e.g. BgpInstance for BGPInstance) and removes underscores.
+func typeKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// generate generates the model samples, the operation samples and the model type registry.
+func generate(spec *openapi.Spec, types []string) (map[string][]byte, error) {
+	models, err := generateModels(spec)
+	if err != nil {
+		return nil, err
+	}
+	operations, err := generateOperations(spec)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := generateTypes(spec, types)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"models.json":     models,
+		"operations.json": operations,
+		"types.go":        registry,
+	}, nil
+}
+
+func generateModels(spec *openapi.Spec) ([]byte, error) {
+	models := make(map[string]interface{})
+	for _, name := range spec.SchemaNames() {
+		sample, err := spec.Sample(&openapi.Schema{Ref: "#/components/schemas/" + name})
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		models[name] = sample
+	}
+	return marshal(models)
+}
+
+func generateOperations(spec *openapi.Spec) ([]byte, error) {
+	var operations []fixtures.Operation
+	for _, op := range spec.Operations() {
+		id := op.ID()
+		code, response, err := spec.SuccessResponse(op.Operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %s: %w", id, err)
+		}
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, fmt.Errorf("operation %s: invalid status %s", id, code)
+		}
+		operation := fixtures.Operation{
+			ID:     id,
+			Method: op.Method,
+			Path:   op.Path,
+			Status: status,
+		}
+		if op.RequestBody != nil {
+			if operation.Request, err = sample(spec, openapi.JSONSchema(op.RequestBody.Content)); err != nil {
+				return nil, fmt.Errorf("operation %s: request: %w", id, err)
+			}
+		}
+		if operation.Response, err = sample(spec, openapi.JSONSchema(response.Content)); err != nil {
+			return nil, fmt.Errorf("operation %s: response: %w", id, err)
+		}
+		operations = append(operations, operation)
+	}
+	return marshal(operations)
+}
+
+func sample(spec *openapi.Spec, schema *openapi.Schema) (json.RawMessage, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	value, err := spec.Sample(schema)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func generateTypes(spec *openapi.Spec, types []string) ([]byte, error) {
+	byKey := make(map[string]string, len(types))
+	for _, name := range types {
+		byKey[typeKey(name)] = name
+	}
+
+	var out bytes.Buffer
+	out.WriteString(header)
+	out.WriteString("package fixtures\n\n")
+	out.WriteString("import (\n\"reflect\"\n\n\"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state\"\n)\n\n")
+	out.WriteString("// modelTypes maps the model names to the types of the state package.\n")
+	out.WriteString("var modelTypes = map[string]reflect.Type{\n")
+	for _, name := range spec.SchemaNames() {
+		if typeName, ok := byKey[typeKey(name)]; ok {
+			fmt.Fprintf(&out, "%q: reflect.TypeOf((*state.%s)(nil)).Elem(),\n", name, typeName)
+		}
+	}
+	out.WriteString("}\n")
+	return format.Source(out.Bytes())
+}
+
+// marshal encodes the given value as indented JSON without escaping HTML characters used by examples.
+func marshal(v interface{}) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
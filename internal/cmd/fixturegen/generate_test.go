@@ -0,0 +1,35 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/openapi"
+)
+
+const fixturesDir = "../../../pkg/rbfstest/fixtures"
+
+// TestGenerate verifies that the fixtures are up to date with the OpenAPI specification.
+func TestGenerate(t *testing.T) {
+	spec, err := openapi.Opsd()
+	require.NoError(t, err)
+	types, err := parseTypes("../../../pkg/rbfs/state")
+	require.NoError(t, err)
+	require.NotEmpty(t, types)
+
+	files, err := generate(spec, types)
+	require.NoError(t, err)
+	for name, generated := range files {
+		existing, err := os.ReadFile(filepath.Join(fixturesDir, name))
+		require.NoError(t, err)
+		require.Equal(t, string(existing), string(generated), "%s is outdated, run go generate ./pkg/rbfstest/fixtures", name)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Command fixturegen generates sample instances of the opsd models and sample responses of the opsd operations
+// from the embedded opsd OpenAPI specification.
+//
+// fixturegen is invoked by go generate in the fixtures package after the opsd client has been regenerated from
+// the OpenAPI specification.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/openapi"
+)
+
+func main() {
+	stateDir := flag.String("state", "../../rbfs/state", "directory of the generated opsd client package")
+	dir := flag.String("dir", ".", "output directory")
+	flag.Parse()
+
+	spec, err := openapi.Opsd()
+	if err != nil {
+		log.Fatal(err)
+	}
+	types, err := parseTypes(*stateDir)
+	if err != nil {
+		log.Fatalf("cannot parse state types: %v", err)
+	}
+
+	files, err := generate(spec, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(*dir, name), content, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
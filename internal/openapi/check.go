@@ -0,0 +1,362 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// UnknownField reports an object attribute not defined by the schema.
+	UnknownField = ViolationKind("unknown_field")
+	// TypeMismatch reports a value of another type than defined by the schema.
+	TypeMismatch = ViolationKind("type_mismatch")
+	// MissingField reports a required object attribute or parameter, which is not present.
+	MissingField = ViolationKind("missing_field")
+	// InvalidValue reports a value not listed by the enum of the schema, a number out of the range of the schema or
+	// a string not matching the format of the schema.
+	InvalidValue = ViolationKind("invalid_value")
+)
+
+// formats holds the validators of the string formats used by the specification.
+var formats = map[string]func(string) bool{
+	"date":         validTime("2006-01-02"),
+	"date-time":    validTime(time.RFC3339),
+	"ipv4":         validIP(true),
+	"ipv4_address": validIP(true),
+	"ipv4_string":  validIP(true),
+	"ipv6":         validIP(false),
+	"ipv4_prefix":  validPrefix(true),
+	"ipv6_prefix":  validPrefix(false),
+}
+
+type (
+	// ViolationKind describes the kind of a schema violation.
+	ViolationKind string
+
+	// Violation describes a value not matching its schema.
+	Violation struct {
+		// In holds the location of the value in a request (path, query or body). The location is empty for
+		// values checked by Check and Validate.
+		In string
+		// Field holds the path of the value (e.g. peerings[0].peer.ipv4_address). The field is empty for the
+		// root value.
+		Field   string
+		Kind    ViolationKind
+		Message string
+	}
+
+	// checker collects the violations of a value.
+	checker struct {
+		spec *Spec
+		// constraints enables the checks of required attributes, enum values, ranges and formats.
+		constraints bool
+		violations  []Violation
+	}
+)
+
+func (v Violation) String() string {
+	if v.Field == "" {
+		return v.Message
+	}
+	return v.Field + ": " + v.Message
+}
+
+// Check reports the object attributes of the given JSON value, which are not defined by the schema, and the
+// values of another type than defined by the schema. The value must be decoded with json.Decoder.UseNumber.
+// Null values are accepted for all schemas. The violations are sorted by field.
+func (s *Spec) Check(schema *Schema, value interface{}) ([]Violation, error) {
+	return s.run(schema, value, false)
+}
+
+// Validate reports the violations reported by Check and, in addition, missing required attributes, values not
+// listed by the enum of the schema, numbers out of the range of the schema and strings not matching the format of
+// the schema. Use Validate for values sent to the API and Check for values received from the API.
+func (s *Spec) Validate(schema *Schema, value interface{}) ([]Violation, error) {
+	return s.run(schema, value, true)
+}
+
+func (s *Spec) run(schema *Schema, value interface{}, constraints bool) ([]Violation, error) {
+	c := &checker{spec: s, constraints: constraints}
+	err := c.check(schema, value, "")
+	sortViolations(c.violations)
+	return c.violations, err
+}
+
+// sortViolations sorts the violations by field.
+func sortViolations(violations []Violation) {
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+}
+
+func (c *checker) check(schema *Schema, value interface{}, field string) error {
+	schema, _, err := c.spec.Resolve(schema)
+	if err != nil || schema == nil || value == nil {
+		return err
+	}
+
+	switch {
+	case len(schema.AllOf) > 0:
+		merged, err := c.spec.mergeAllOf(schema)
+		if err != nil {
+			return err
+		}
+		return c.check(merged, value, field)
+	case len(schema.OneOf) > 0:
+		return c.checkAlternatives(schema.OneOf, value, field)
+	case len(schema.AnyOf) > 0:
+		return c.checkAlternatives(schema.AnyOf, value, field)
+	}
+
+	switch schema.Type {
+	case "object", "":
+		if schema.Type == "" && schema.Properties == nil && schema.AdditionalProperties == nil {
+			// Free-form value
+			return nil
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			c.addTypeMismatch(field, "object", value)
+			return nil
+		}
+		if c.constraints {
+			for _, name := range schema.Required {
+				if _, ok := object[name]; !ok {
+					c.add(joinField(field, name), MissingField, "missing required attribute")
+				}
+			}
+		}
+		for name, attribute := range object {
+			propertySchema, ok := schema.Properties[name]
+			if !ok {
+				propertySchema = schema.AdditionalProperties
+			}
+			if propertySchema == nil {
+				c.add(joinField(field, name), UnknownField, "unknown attribute")
+				continue
+			}
+			if err := c.check(propertySchema, attribute, joinField(field, name)); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			c.addTypeMismatch(field, "array", value)
+			return nil
+		}
+		for i, item := range array {
+			if err := c.check(schema.Items, item, fmt.Sprintf("%s[%d]", field, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			c.addTypeMismatch(field, "string", value)
+			return nil
+		}
+		if c.constraints && !c.checkEnum(schema, value, field) {
+			if valid, ok := formats[schema.Format]; ok && !valid(str) {
+				c.add(field, InvalidValue, fmt.Sprintf("invalid %s %q", schema.Format, str))
+			}
+		}
+	case "integer":
+		n, ok := integer(value)
+		if !ok {
+			c.addTypeMismatch(field, "integer", value)
+			return nil
+		}
+		if c.constraints && !c.checkEnum(schema, value, field) {
+			c.checkRange(schema, float64(n), field)
+		}
+	case "number":
+		n, ok := number(value)
+		if !ok {
+			c.addTypeMismatch(field, "number", value)
+			return nil
+		}
+		if c.constraints && !c.checkEnum(schema, value, field) {
+			c.checkRange(schema, n, field)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			c.addTypeMismatch(field, "boolean", value)
+		}
+	default:
+		return fmt.Errorf("unsupported schema type %s", schema.Type)
+	}
+	return nil
+}
+
+// checkAlternatives accepts the value if it matches any of the given schemas. Otherwise, the violations of the
+// first schema are reported.
+func (c *checker) checkAlternatives(schemas []*Schema, value interface{}, field string) error {
+	var first []Violation
+	for i, schema := range schemas {
+		alternative := &checker{spec: c.spec, constraints: c.constraints}
+		if err := alternative.check(schema, value, field); err != nil {
+			return err
+		}
+		if len(alternative.violations) == 0 {
+			return nil
+		}
+		if i == 0 {
+			first = alternative.violations
+		}
+	}
+	c.violations = append(c.violations, first...)
+	return nil
+}
+
+// checkEnum reports a value not listed by the enum of the schema and returns whether the schema defines an enum.
+func (c *checker) checkEnum(schema *Schema, value interface{}, field string) bool {
+	if len(schema.Enum) == 0 {
+		return false
+	}
+	values := make([]string, 0, len(schema.Enum))
+	for _, e := range schema.Enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+		values = append(values, fmt.Sprint(e))
+	}
+	c.add(field, InvalidValue, fmt.Sprintf("invalid value %v, expected one of %s", value, strings.Join(values, ", ")))
+	return true
+}
+
+// checkRange reports a number out of the range of the schema.
+func (c *checker) checkRange(schema *Schema, n float64, field string) {
+	switch {
+	case schema.Minimum != nil && schema.Maximum != nil && (n < *schema.Minimum || n > *schema.Maximum):
+		c.add(field, InvalidValue, fmt.Sprintf("value %v out of range %v..%v", n, *schema.Minimum, *schema.Maximum))
+	case schema.Minimum != nil && n < *schema.Minimum:
+		c.add(field, InvalidValue, fmt.Sprintf("value %v less than minimum %v", n, *schema.Minimum))
+	case schema.Maximum != nil && n > *schema.Maximum:
+		c.add(field, InvalidValue, fmt.Sprintf("value %v greater than maximum %v", n, *schema.Maximum))
+	}
+}
+
+// mergeAllOf merges the properties of all schemas of an allOf schema into a single object schema.
+func (s *Spec) mergeAllOf(schema *Schema) (*Schema, error) {
+	merged := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, part := range schema.AllOf {
+		part, _, err := s.Resolve(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(part.AllOf) > 0 {
+			if part, err = s.mergeAllOf(part); err != nil {
+				return nil, err
+			}
+		}
+		if part.Type != "object" && part.Type != "" {
+			if len(schema.AllOf) == 1 {
+				return part, nil
+			}
+			return nil, fmt.Errorf("allOf of non-object schemas")
+		}
+		for name, property := range part.Properties {
+			merged.Properties[name] = property
+		}
+		if part.AdditionalProperties != nil {
+			merged.AdditionalProperties = part.AdditionalProperties
+		}
+		merged.Required = append(merged.Required, part.Required...)
+	}
+	return merged, nil
+}
+
+func (c *checker) add(field string, kind ViolationKind, message string) {
+	c.violations = append(c.violations, Violation{Field: field, Kind: kind, Message: message})
+}
+
+func (c *checker) addTypeMismatch(field, expected string, value interface{}) {
+	c.add(field, TypeMismatch, fmt.Sprintf("expected %s, got %s", expected, jsonType(value)))
+}
+
+func joinField(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case json.Number, float64, int64, int:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func validTime(layout string) func(string) bool {
+	return func(s string) bool {
+		_, err := time.Parse(layout, s)
+		return err == nil
+	}
+}
+
+func validIP(v4 bool) func(string) bool {
+	return func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && (ip.To4() != nil) == v4
+	}
+}
+
+func validPrefix(v4 bool) func(string) bool {
+	return func(s string) bool {
+		ip, _, err := net.ParseCIDR(s)
+		return err == nil && (ip.To4() != nil) == v4
+	}
+}
+
+// number returns the value of a JSON number.
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// integer returns the value of a JSON number without fraction.
+func integer(value interface{}) (int64, bool) {
+	if n, ok := value.(json.Number); ok {
+		i, err := strconv.ParseInt(string(n), 10, 64)
+		return i, err == nil
+	}
+	f, ok := number(value)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int64(f), true
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	pathParamPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+	// paramPatterns holds the patterns of path parameters that do not match an arbitrary path segment.
+	// The generated clients do not escape path parameters, hence interface names like ifp-0/0/1 span multiple
+	// segments.
+	paramPatterns = map[string]string{
+		"if_name":       `[a-z]+-[0-9]+/[0-9]+/[0-9]+(?:/[0-9]+)?|[^/]+`,
+		"ifp_name":      `[a-z]+-[0-9]+/[0-9]+/[0-9]+|[^/]+`,
+		"ifl_name":      `[a-z]+-[0-9]+/[0-9]+/[0-9]+/[0-9]+|[^/]+`,
+		"anp":           `[0-9]+`,
+		"s_vlan":        `[0-9]+`,
+		"s_vlan_low":    `[0-9]+`,
+		"s_vlan_high":   `[0-9]+`,
+		"subscriber_id": `[0-9]+`,
+	}
+)
+
+type (
+	// PathTemplate matches request paths against a path template (e.g. /bgp/instances/{instance_name}).
+	PathTemplate struct {
+		Template string
+		// Params holds the names of the path parameters in the order of the template.
+		Params  []string
+		pattern *regexp.Regexp
+	}
+
+	route struct {
+		template *PathTemplate
+		op       PathOperation
+	}
+)
+
+// CompilePathTemplate compiles the given path template. Path parameters match a single path segment, except
+// interface names and numeric parameters.
+func CompilePathTemplate(template string) *PathTemplate {
+	var params []string
+	expr := "^"
+	last := 0
+	for _, match := range pathParamPattern.FindAllStringSubmatchIndex(template, -1) {
+		name := template[match[2]:match[3]]
+		params = append(params, name)
+		pattern, ok := paramPatterns[name]
+		if !ok {
+			pattern = `[^/]+`
+		}
+		expr += regexp.QuoteMeta(template[last:match[0]]) + "((?:" + pattern + "))"
+		last = match[1]
+	}
+	expr += regexp.QuoteMeta(template[last:]) + "$"
+	return &PathTemplate{
+		Template: template,
+		Params:   params,
+		pattern:  regexp.MustCompile(expr),
+	}
+}
+
+// Match matches the given path and returns the path parameter values.
+func (t *PathTemplate) Match(path string) (map[string]string, bool) {
+	match := t.pattern.FindStringSubmatch(path)
+	if match == nil {
+		return nil, false
+	}
+	params := make(map[string]string, len(t.Params))
+	for i, name := range t.Params {
+		params[name] = match[i+1]
+	}
+	return params, true
+}
+
+// Match returns the operation matching the given method and path relative to the API endpoint and the path
+// parameter values. Templates with fewer path parameters take precedence, so that literal path segments are not
+// mistaken for parameter values.
+func (s *Spec) Match(method, path string) (PathOperation, map[string]string, bool) {
+	s.routesOnce.Do(func() {
+		for _, op := range s.Operations() {
+			s.routes = append(s.routes, route{template: CompilePathTemplate(op.Path), op: op})
+		}
+		sort.SliceStable(s.routes, func(i, j int) bool {
+			return len(s.routes[i].template.Params) < len(s.routes[j].template.Params)
+		})
+	})
+	method = strings.ToUpper(method)
+	for _, route := range s.routes {
+		if route.op.Method != method {
+			continue
+		}
+		if params, ok := route.template.Match(path); ok {
+			return route.op, params, true
+		}
+	}
+	return PathOperation{}, nil, false
+}
@@ -0,0 +1,29 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package openapi
+
+import (
+	_ "embed"
+	"sync"
+)
+
+//go:embed opsd-openapi.yaml
+var opsdSpec []byte
+
+var (
+	opsdOnce sync.Once
+	opsd     *Spec
+	opsdErr  error
+)
+
+// Opsd returns the opsd OpenAPI specification the opsd client has been generated from.
+// The specification is parsed once and must not be modified.
+func Opsd() (*Spec, error) {
+	opsdOnce.Do(func() {
+		opsd, opsdErr = Parse(opsdSpec)
+	})
+	return opsd, opsdErr
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+const (
+	// InPath locates a violation in a path parameter.
+	InPath = "path"
+	// InQuery locates a violation in a query parameter.
+	InQuery = "query"
+	// InBody locates a violation in the request body.
+	InBody = "body"
+)
+
+// ValidateRequest validates the path parameters, the query parameters and the JSON body of a request of the given
+// operation. The violations are located by Violation.In and sorted by location and field. Query parameters not
+// defined by the operation are reported as unknown fields.
+func (s *Spec) ValidateRequest(op PathOperation, pathParams map[string]string, query url.Values, body []byte) ([]Violation, error) {
+	var violations []Violation
+	known := make(map[string]bool)
+	for _, param := range op.Parameters {
+		var raw []string
+		switch param.In {
+		case InPath:
+			if value, ok := pathParams[param.Name]; ok {
+				raw = []string{value}
+			}
+		case InQuery:
+			known[param.Name] = true
+			raw = query[param.Name]
+		default:
+			continue
+		}
+
+		if len(raw) == 0 {
+			if param.Required {
+				violations = append(violations, Violation{In: param.In, Field: param.Name, Kind: MissingField, Message: "missing required parameter"})
+			}
+			continue
+		}
+		for _, value := range raw {
+			found, err := s.validateParameter(param, value)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, found...)
+		}
+	}
+	for name := range query {
+		if !known[name] {
+			violations = append(violations, Violation{In: InQuery, Field: name, Kind: UnknownField, Message: "unknown parameter"})
+		}
+	}
+
+	found, err := s.validateBody(op.Operation, body)
+	if err != nil {
+		return nil, err
+	}
+	violations = append(violations, found...)
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].In != violations[j].In {
+			return violations[i].In < violations[j].In
+		}
+		return violations[i].Field < violations[j].Field
+	})
+	return violations, nil
+}
+
+func (s *Spec) validateParameter(param *Parameter, raw string) ([]Violation, error) {
+	value, err := s.parameterValue(param.Schema, raw)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := s.Validate(param.Schema, value)
+	for i := range violations {
+		violations[i].In = param.In
+		if violations[i].Field == "" {
+			violations[i].Field = param.Name
+		} else {
+			violations[i].Field = param.Name + "." + violations[i].Field
+		}
+	}
+	return violations, err
+}
+
+// parameterValue converts the raw value of a parameter into the JSON value of its schema. Values not matching the
+// schema type are passed as string to be reported as type mismatch.
+func (s *Spec) parameterValue(schema *Schema, raw string) (interface{}, error) {
+	schema, _, err := s.Resolve(schema)
+	if err != nil || schema == nil {
+		return raw, err
+	}
+	switch schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw), nil
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b, nil
+		}
+	}
+	return raw, nil
+}
+
+func (s *Spec) validateBody(op *Operation, body []byte) ([]Violation, error) {
+	if op.RequestBody == nil {
+		return nil, nil
+	}
+	schema := JSONSchema(op.RequestBody.Content)
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return []Violation{{In: InBody, Kind: MissingField, Message: "missing required request body"}}, nil
+		}
+		return nil, nil
+	}
+	if schema == nil {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []Violation{{In: InBody, Kind: TypeMismatch, Message: "invalid JSON: " + err.Error()}}, nil
+	}
+	violations, err := s.Validate(schema, value)
+	for i := range violations {
+		violations[i].In = InBody
+	}
+	return violations, err
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec_Validate(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+	node := &Schema{Ref: "#/components/schemas/Node"}
+
+	value := map[string]interface{}{
+		"address": "192.0.2.256",
+		"weight":  json.Number("5"),
+		"ratio":   json.Number("1.5"),
+		"state":   "UNKNOWN",
+		"detail":  map[string]interface{}{"since": "yesterday"},
+	}
+	violations, err := spec.Validate(node, value)
+	require.NoError(t, err)
+	require.Equal(t, []Violation{
+		{Field: "address", Kind: InvalidValue, Message: `invalid ipv6 "192.0.2.256"`},
+		{Field: "detail.since", Kind: InvalidValue, Message: `invalid date-time "yesterday"`},
+		{Field: "node_name", Kind: MissingField, Message: "missing required attribute"},
+		{Field: "ratio", Kind: InvalidValue, Message: "value 1.5 greater than maximum 1"},
+		{Field: "state", Kind: InvalidValue, Message: "invalid value UNKNOWN, expected one of UP, DOWN"},
+		{Field: "weight", Kind: InvalidValue, Message: "value 5 less than minimum 10"},
+	}, violations)
+
+	// Check does not validate constraints
+	violations, err = spec.Check(node, value)
+	require.NoError(t, err)
+	require.Empty(t, violations)
+}
+
+func TestSpec_ValidateRequest(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+	op, params, ok := spec.Match("GET", "/nodes/node1")
+	require.True(t, ok)
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []Violation
+	}{
+		{
+			name:  "valid",
+			query: url.Values{"depth": {"5"}, "state": {"UP"}},
+		},
+		{
+			name:  "missing",
+			query: url.Values{"state": {"UP"}},
+			want: []Violation{
+				{In: InQuery, Field: "depth", Kind: MissingField, Message: "missing required parameter"},
+			},
+		},
+		{
+			name:  "invalid",
+			query: url.Values{"depth": {"deep"}, "state": {"up"}, "limit": {"1"}},
+			want: []Violation{
+				{In: InQuery, Field: "depth", Kind: TypeMismatch, Message: "expected integer, got string"},
+				{In: InQuery, Field: "limit", Kind: UnknownField, Message: "unknown parameter"},
+				{In: InQuery, Field: "state", Kind: InvalidValue, Message: "invalid value up, expected one of UP, DOWN"},
+			},
+		},
+		{
+			name:  "range",
+			query: url.Values{"depth": {"6"}},
+			want: []Violation{
+				{In: InQuery, Field: "depth", Kind: InvalidValue, Message: "value 6 out of range 1..5"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := spec.ValidateRequest(op, params, tt.query, nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, violations)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// formatSamples holds the sample values of string formats.
+	formatSamples = map[string]string{
+		"date":         "2021-06-01",
+		"date-time":    "2021-06-01T12:00:00Z",
+		"ipv4":         "192.0.2.1",
+		"ipv4_address": "192.0.2.1",
+		"ipv4_string":  "192.0.2.1",
+		"ipv4_prefix":  "192.0.2.0/24",
+		"ipv6":         "2001:db8::1",
+		"ipv6_prefix":  "2001:db8::/32",
+	}
+
+	// propertySamples holds the sample values of string properties by the suffix of the property name.
+	// Samples of other string properties are the property name.
+	propertySamples = []struct {
+		suffix string
+		value  string
+	}{
+		{"mac", "02:00:00:00:00:01"},
+		{"ifp_name", "ifp-0/0/1"},
+		{"ifl_name", "ifl-0/0/1/1"},
+		{"if_name", "ifp-0/0/1"},
+		{"instance_name", "default"},
+	}
+)
+
+// Sample returns a sample value of the given schema. The sample uses the example, default or first enum value if
+// the schema defines any. Otherwise, the sample is derived from the type and format of the schema. Objects contain
+// a sample of every property, which is not deprecated, arrays contain a single sample item. Recursive schemas are
+// sampled once per branch.
+func (s *Spec) Sample(schema *Schema) (interface{}, error) {
+	return s.sample(schema, "", make(map[string]bool))
+}
+
+func (s *Spec) sample(schema *Schema, property string, visiting map[string]bool) (interface{}, error) {
+	schema, name, err := s.Resolve(schema)
+	if err != nil || schema == nil {
+		return nil, err
+	}
+	if name != "" {
+		if visiting[name] {
+			return nil, nil
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+	}
+
+	switch {
+	case schema.Example != nil:
+		return coerce(schema, schema.Example), nil
+	case schema.Default != nil:
+		return coerce(schema, schema.Default), nil
+	case len(schema.Enum) > 0:
+		return coerce(schema, schema.Enum[0]), nil
+	case len(schema.AllOf) > 0:
+		return s.sampleAllOf(schema.AllOf, visiting)
+	case len(schema.OneOf) > 0:
+		return s.sample(schema.OneOf[0], property, visiting)
+	case len(schema.AnyOf) > 0:
+		return s.sample(schema.AnyOf[0], property, visiting)
+	}
+
+	switch schema.Type {
+	case "object", "":
+		return s.sampleObject(schema, visiting)
+	case "array":
+		item, err := s.sample(schema.Items, property, visiting)
+		if err != nil || item == nil {
+			return []interface{}{}, err
+		}
+		return []interface{}{item}, nil
+	case "string":
+		return sampleString(schema.Format, property), nil
+	case "integer":
+		return int64(sampleNumber(schema, 1)), nil
+	case "number":
+		return sampleNumber(schema, 1.5), nil
+	case "boolean":
+		return true, nil
+	default:
+		return nil, fmt.Errorf("unsupported schema type %s", schema.Type)
+	}
+}
+
+func (s *Spec) sampleObject(schema *Schema, visiting map[string]bool) (map[string]interface{}, error) {
+	object := make(map[string]interface{}, len(schema.Properties))
+	for property, propertySchema := range schema.Properties {
+		if propertySchema.Deprecated {
+			continue
+		}
+		value, err := s.sample(propertySchema, property, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", property, err)
+		}
+		if value != nil {
+			object[property] = value
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		value, err := s.sample(schema.AdditionalProperties, "", visiting)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			object["key"] = value
+		}
+	}
+	return object, nil
+}
+
+// sampleAllOf merges the samples of all object schemas.
+func (s *Spec) sampleAllOf(schemas []*Schema, visiting map[string]bool) (interface{}, error) {
+	merged := make(map[string]interface{})
+	for _, schema := range schemas {
+		value, err := s.sample(schema, "", visiting)
+		if err != nil {
+			return nil, err
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			if len(schemas) == 1 {
+				return value, nil
+			}
+			return nil, fmt.Errorf("allOf of non-object schemas")
+		}
+		for property, value := range object {
+			merged[property] = value
+		}
+	}
+	return merged, nil
+}
+
+// coerce converts examples, defaults and enum values of string schemas to strings. The specification defines
+// numeric defaults for some string attributes.
+func coerce(schema *Schema, value interface{}) interface{} {
+	if _, ok := value.(string); !ok && schema.Type == "string" {
+		return fmt.Sprint(value)
+	}
+	return value
+}
+
+func sampleString(format, property string) string {
+	if value, ok := formatSamples[format]; ok {
+		return value
+	}
+	for _, sample := range propertySamples {
+		if strings.HasSuffix(property, sample.suffix) {
+			return sample.value
+		}
+	}
+	if property != "" {
+		return property
+	}
+	return "string"
+}
+
+// sampleNumber returns the minimum, if any, or the preferred value limited by the maximum.
+func sampleNumber(schema *Schema, preferred float64) float64 {
+	if schema.Minimum != nil {
+		return *schema.Minimum
+	}
+	if schema.Maximum != nil {
+		return math.Min(preferred, *schema.Maximum)
+	}
+	return preferred
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+paths:
+  /nodes/{node_name}:
+    get:
+      operationId: getNode
+      parameters:
+        - in: path
+          name: node_name
+          required: true
+          schema:
+            type: string
+        - in: query
+          name: depth
+          required: true
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 5
+        - in: query
+          name: state
+          schema:
+            type: string
+            enum: [UP, DOWN]
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Node"
+        404:
+          $ref: "#/components/responses/404"
+  /nodes:
+    get:
+      responses:
+        204:
+          description: No nodes.
+components:
+  responses:
+    404:
+      description: Not found.
+  schemas:
+    Node:
+      type: object
+      required: [node_name]
+      properties:
+        node_name:
+          type: string
+        ifp_name:
+          type: string
+        address:
+          oneOf:
+            - type: string
+              format: ipv6
+            - type: string
+              format: ipv4
+        hops:
+          type: string
+          default: 30
+        weight:
+          type: integer
+          minimum: 10
+        ratio:
+          type: number
+          maximum: 1
+        state:
+          type: string
+          enum: [UP, DOWN]
+        legacy:
+          type: string
+          deprecated: true
+        children:
+          type: array
+          items:
+            $ref: "#/components/schemas/Node"
+        labels:
+          type: object
+          additionalProperties:
+            type: boolean
+        detail:
+          allOf:
+            - $ref: "#/components/schemas/Detail"
+            - type: object
+              properties:
+                extra:
+                  type: integer
+    Detail:
+      type: object
+      properties:
+        since:
+          type: string
+          format: date-time
+`
+
+func TestSpec_Sample(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+
+	sample, err := spec.Sample(&Schema{Ref: "#/components/schemas/Node"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"node_name": "node_name",
+		"ifp_name":  "ifp-0/0/1",
+		"address":   "2001:db8::1",
+		"hops":      "30",
+		"weight":    int64(10),
+		"ratio":     float64(1),
+		"state":     "UP",
+		"children":  []interface{}{},
+		"labels":    map[string]interface{}{"key": true},
+		"detail": map[string]interface{}{
+			"since": "2021-06-01T12:00:00Z",
+			"extra": int64(1),
+		},
+	}, sample)
+
+	_, err = spec.Sample(&Schema{Ref: "#/components/schemas/Unknown"})
+	require.EqualError(t, err, "unknown schema Unknown")
+}
+
+func TestSpec_Operations(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	require.NoError(t, err)
+
+	operations := spec.Operations()
+	require.Len(t, operations, 2)
+	require.Equal(t, "nodesGet", operations[0].ID())
+	require.Equal(t, "getNode", operations[1].ID())
+
+	status, response, err := spec.SuccessResponse(operations[0].Operation)
+	require.NoError(t, err)
+	require.Equal(t, "204", status)
+	require.Nil(t, JSONSchema(response.Content))
+
+	status, response, err = spec.SuccessResponse(operations[1].Operation)
+	require.NoError(t, err)
+	require.Equal(t, "200", status)
+	require.Equal(t, "#/components/schemas/Node", JSONSchema(response.Content).Ref)
+}
+
+// TestOpsd_SampleCheck verifies that the samples of all opsd schemas pass the validation.
+func TestOpsd_SampleCheck(t *testing.T) {
+	spec, err := Opsd()
+	require.NoError(t, err)
+	for _, name := range spec.SchemaNames() {
+		schema := &Schema{Ref: schemaRefPrefix + name}
+		sample, err := spec.Sample(schema)
+		require.NoError(t, err, name)
+		violations, err := spec.Validate(schema, sample)
+		require.NoError(t, err, name)
+		require.Empty(t, violations, name)
+	}
+
+	op, params, ok := spec.Match("GET", "/interfaces/physicals/ifp-0/1/2")
+	require.True(t, ok)
+	require.Equal(t, "getPhysicalInterface", op.ID())
+	require.Equal(t, map[string]string{"ifp_name": "ifp-0/1/2"}, params)
+	op, _, ok = spec.Match("GET", "/interfaces/physicals")
+	require.True(t, ok)
+	require.Equal(t, "getPhysicalInterfaces", op.ID())
+	_, _, ok = spec.Match("PATCH", "/interfaces/physicals")
+	require.False(t, ok)
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package openapi reads the subset of OpenAPI 3 used by the opsd specification.
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// Methods holds the supported operation methods in the order of the operations of a path.
+var Methods = []string{"GET", "PUT", "POST", "DELETE", "PATCH"}
+
+type (
+	// Spec holds an OpenAPI specification.
+	Spec struct {
+		Paths      map[string]*PathItem `yaml:"paths"`
+		Components Components           `yaml:"components"`
+
+		routesOnce sync.Once
+		routes     []route
+	}
+
+	// Components holds the reusable schemas and responses.
+	Components struct {
+		Schemas   map[string]*Schema   `yaml:"schemas"`
+		Responses map[string]*Response `yaml:"responses"`
+	}
+
+	// PathItem holds the operations of a path.
+	PathItem struct {
+		Get        *Operation   `yaml:"get"`
+		Put        *Operation   `yaml:"put"`
+		Post       *Operation   `yaml:"post"`
+		Delete     *Operation   `yaml:"delete"`
+		Patch      *Operation   `yaml:"patch"`
+		Parameters []*Parameter `yaml:"parameters"`
+	}
+
+	// Operation describes an API operation.
+	Operation struct {
+		OperationID string               `yaml:"operationId"`
+		Summary     string               `yaml:"summary"`
+		Tags        []string             `yaml:"tags"`
+		Parameters  []*Parameter         `yaml:"parameters"`
+		RequestBody *RequestBody         `yaml:"requestBody"`
+		Responses   map[string]*Response `yaml:"responses"`
+	}
+
+	// Parameter describes a path or query parameter.
+	Parameter struct {
+		In       string  `yaml:"in"`
+		Name     string  `yaml:"name"`
+		Required bool    `yaml:"required"`
+		Schema   *Schema `yaml:"schema"`
+	}
+
+	// RequestBody describes the body of a request.
+	RequestBody struct {
+		Required bool                  `yaml:"required"`
+		Content  map[string]*MediaType `yaml:"content"`
+	}
+
+	// Response describes a response or references a response of the components.
+	Response struct {
+		Ref         string                `yaml:"$ref"`
+		Description string                `yaml:"description"`
+		Content     map[string]*MediaType `yaml:"content"`
+	}
+
+	// MediaType holds the schema of a request or response body.
+	MediaType struct {
+		Schema *Schema `yaml:"schema"`
+	}
+
+	// Schema describes a value or references a schema of the components.
+	Schema struct {
+		Ref                  string             `yaml:"$ref"`
+		Type                 string             `yaml:"type"`
+		Format               string             `yaml:"format"`
+		Enum                 []interface{}      `yaml:"enum"`
+		Default              interface{}        `yaml:"default"`
+		Example              interface{}        `yaml:"example"`
+		Minimum              *float64           `yaml:"minimum"`
+		Maximum              *float64           `yaml:"maximum"`
+		Items                *Schema            `yaml:"items"`
+		Properties           map[string]*Schema `yaml:"properties"`
+		Required             []string           `yaml:"required"`
+		AdditionalProperties *Schema            `yaml:"additionalProperties"`
+		AllOf                []*Schema          `yaml:"allOf"`
+		OneOf                []*Schema          `yaml:"oneOf"`
+		AnyOf                []*Schema          `yaml:"anyOf"`
+		Deprecated           bool               `yaml:"deprecated"`
+	}
+
+	// PathOperation is an operation with its method and path template.
+	PathOperation struct {
+		*Operation
+		Method string
+		Path   string
+		// Parameters holds the path item and operation parameters.
+		Parameters []*Parameter
+	}
+)
+
+// ID returns the operation ID. Operations without an operation ID are identified like the generated opsd client
+// names their methods, by the path and method (e.g. flowspecInstancesInstanceRulesGet).
+func (op PathOperation) ID() string {
+	if op.OperationID != "" {
+		return op.OperationID
+	}
+	fields := strings.FieldsFunc(op.Path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == '{' || r == '}'
+	})
+	fields = append(fields, strings.ToLower(op.Method))
+	id := fields[0]
+	for _, field := range fields[1:] {
+		id += strings.ToUpper(field[:1]) + field[1:]
+	}
+	return id
+}
+
+// Load reads the OpenAPI specification from the given file.
+func Load(name string) (*Spec, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes the given OpenAPI specification.
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("cannot decode OpenAPI specification: %w", err)
+	}
+	return spec, nil
+}
+
+// SchemaNames returns the names of the component schemas in alphabetical order.
+func (s *Spec) SchemaNames() []string {
+	names := make([]string, 0, len(s.Components.Schemas))
+	for name := range s.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the referenced component schema, if the given schema is a reference, and the schema name.
+// The name is empty for schemas that are not a reference.
+func (s *Spec) Resolve(schema *Schema) (*Schema, string, error) {
+	if schema == nil || schema.Ref == "" {
+		return schema, "", nil
+	}
+	if !strings.HasPrefix(schema.Ref, schemaRefPrefix) {
+		return nil, "", fmt.Errorf("unsupported schema reference %s", schema.Ref)
+	}
+	name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+	resolved, ok := s.Components.Schemas[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown schema %s", name)
+	}
+	return resolved, name, nil
+}
+
+// Operations returns the operations sorted by path and method.
+func (s *Spec) Operations() []PathOperation {
+	paths := make([]string, 0, len(s.Paths))
+	for path := range s.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var operations []PathOperation
+	for _, path := range paths {
+		item := s.Paths[path]
+		for i, op := range []*Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if op == nil {
+				continue
+			}
+			operations = append(operations, PathOperation{
+				Operation:  op,
+				Method:     Methods[i],
+				Path:       path,
+				Parameters: append(append([]*Parameter(nil), item.Parameters...), op.Parameters...),
+			})
+		}
+	}
+	return operations
+}
+
+// SuccessResponse returns the status code and the response of the first 2xx response of the given operation.
+// The response is resolved, if it references a component response.
+func (s *Spec) SuccessResponse(op *Operation) (string, *Response, error) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "", nil, fmt.Errorf("operation has no success response")
+	}
+	sort.Strings(codes)
+	response, err := s.Response(op, codes[0])
+	return codes[0], response, err
+}
+
+// Response returns the response of the given operation with the given status code or nil, if the operation does
+// not define a response for the status code. The response is resolved, if it references a component response.
+func (s *Spec) Response(op *Operation, code string) (*Response, error) {
+	response, ok := op.Responses[code]
+	if !ok || response.Ref == "" {
+		return response, nil
+	}
+	name := strings.TrimPrefix(response.Ref, "#/components/responses/")
+	resolved, ok := s.Components.Responses[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown response %s", response.Ref)
+	}
+	return resolved, nil
+}
+
+// JSONSchema returns the schema of the JSON content, if any.
+func JSONSchema(content map[string]*MediaType) *Schema {
+	if media, ok := content["application/json"]; ok {
+		return media.Schema
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package dualstack compares the IPv4 and IPv6 reachability of hostnames by pinging the A and AAAA records of each
+// hostname concurrently from the same element and routing instance.
+package dualstack
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+const (
+	// BothReachable indicates that the hostname is reachable via IPv4 and IPv6.
+	BothReachable = Outcome("both")
+	// IPv4Only indicates that the hostname is reachable via IPv4 only, i.e. IPv6 failed.
+	IPv4Only = Outcome("ipv4-only")
+	// IPv6Only indicates that the hostname is reachable via IPv6 only, i.e. IPv4 failed.
+	IPv6Only = Outcome("ipv6-only")
+	// Unreachable indicates that the hostname is reachable neither via IPv4 nor via IPv6.
+	Unreachable = Outcome("unreachable")
+)
+
+type (
+	defaultService struct {
+		ping        ping.Service
+		concurrency int
+		options     []ping.Option
+	}
+
+	// Outcome classifies the dual-stack reachability of a hostname.
+	Outcome string
+
+	// Family holds the ping result of a single address family.
+	Family struct {
+		// Status holds the ping status, if the ping succeeded.
+		Status *state.PingStatus
+		// Statistics holds the statistics computed from the ping output, if the ping succeeded.
+		Statistics *ping.Statistics
+		// Err holds the error of a failed ping, e.g. if the hostname has no record of this address family.
+		Err error
+	}
+
+	// Comparison compares the IPv4 and IPv6 reachability of a hostname.
+	Comparison struct {
+		// Hostname holds the pinged hostname.
+		Hostname string
+		// IPv4 holds the result of pinging the A record.
+		IPv4 Family
+		// IPv6 holds the result of pinging the AAAA record.
+		IPv6 Family
+		// Outcome classifies the reachability.
+		Outcome Outcome
+		// LossDelta holds the IPv6 loss ratio minus the IPv4 loss ratio, if both families are reachable.
+		LossDelta float64
+		// LatencyDelta holds the IPv6 average round-trip time minus the IPv4 average round-trip time,
+		// if both families are reachable.
+		LatencyDelta time.Duration
+	}
+
+	// Option applies a dual-stack service option.
+	Option func(*defaultService)
+
+	// Service compares the IPv4 and IPv6 reachability of hostnames.
+	Service interface {
+		// Compare pings the A and AAAA record of the given hostname concurrently.
+		Compare(ctx rbfs.RbfsContext, hostname string) (*Comparison, error)
+
+		// CompareAll compares all given hostnames. The comparisons are returned in the order of the hostnames.
+		CompareAll(ctx rbfs.RbfsContext, hostnames ...string) ([]*Comparison, error)
+	}
+)
+
+// PingOptions sets additional options applied to every ping, e.g. the routing instance or the number of echo
+// requests.
+func PingOptions(options ...ping.Option) Option {
+	return func(s *defaultService) {
+		s.options = append(s.options, options...)
+	}
+}
+
+// Concurrency limits the number of pings running in parallel in batch mode. The default limit is 10.
+func Concurrency(limit int) Option {
+	return func(s *defaultService) {
+		s.concurrency = limit
+	}
+}
+
+// Reachable reports whether at least one echo reply was received.
+func (f *Family) Reachable() bool {
+	return f.Statistics != nil && f.Statistics.Received > 0
+}
+
+func (s *defaultService) Compare(ctx rbfs.RbfsContext, hostname string) (*Comparison, error) {
+	comparisons, err := s.CompareAll(ctx, hostname)
+	if err != nil {
+		return nil, err
+	}
+	return comparisons[0], nil
+}
+
+func (s *defaultService) CompareAll(ctx rbfs.RbfsContext, hostnames ...string) ([]*Comparison, error) {
+	pings := make([]*ping.Ping, 0, 2*len(hostnames))
+	for _, hostname := range hostnames {
+		for _, destination := range []ping.Option{ping.DestinationHostNameA(hostname), ping.DestinationHostNameAAAA(hostname)} {
+			p, err := ping.NewPing(append(append([]ping.Option{}, s.options...), destination)...)
+			if err != nil {
+				return nil, err
+			}
+			pings = append(pings, p)
+		}
+	}
+
+	outcomes := s.ping.RunBatch(ctx, pings, ping.Concurrency(s.concurrency), ping.ContinueOnError())
+	comparisons := make([]*Comparison, 0, len(hostnames))
+	for i, hostname := range hostnames {
+		comparisons = append(comparisons, compare(hostname, newFamily(outcomes[2*i]), newFamily(outcomes[2*i+1])))
+	}
+	return comparisons, nil
+}
+
+func newFamily(outcome ping.Outcome) Family {
+	if outcome.Err != nil {
+		return Family{Err: outcome.Err}
+	}
+	status := outcome.Status
+	return Family{Status: &status, Statistics: &ping.NewResult(status).Statistics}
+}
+
+func compare(hostname string, ipv4, ipv6 Family) *Comparison {
+	c := &Comparison{Hostname: hostname, IPv4: ipv4, IPv6: ipv6}
+	switch {
+	case ipv4.Reachable() && ipv6.Reachable():
+		c.Outcome = BothReachable
+		c.LossDelta = ipv6.Statistics.Loss - ipv4.Statistics.Loss
+		c.LatencyDelta = ipv6.Statistics.Avg - ipv4.Statistics.Avg
+	case ipv4.Reachable():
+		c.Outcome = IPv4Only
+	case ipv6.Reachable():
+		c.Outcome = IPv6Only
+	default:
+		c.Outcome = Unreachable
+	}
+	return c
+}
+
+// NewDualStackService creates a new dual-stack defaultService.
+func NewDualStackService(client *http.Client, options ...Option) Service {
+	s := &defaultService{ping: ping.NewPingService(client), concurrency: 10}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package dualstack
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePingService answers pings with the configured round-trip times per destination record.
+// A negative round-trip time is reported as lost echo request, a missing record as ping error.
+type fakePingService struct {
+	ping.Service
+	records   map[string][]time.Duration
+	instances map[string]bool
+}
+
+func (s *fakePingService) RunBatch(_ rbfs.RbfsContext, pings []*ping.Ping, _ ...ping.BatchOption) []ping.Outcome {
+	outcomes := make([]ping.Outcome, 0, len(pings))
+	for _, p := range pings {
+		record := "A " + p.DestinationHostNameA()
+		if p.DestinationHostNameAAAA() != "" {
+			record = "AAAA " + p.DestinationHostNameAAAA()
+		}
+		s.instances[p.InstanceName()] = true
+		rtts, ok := s.records[record]
+		if !ok {
+			outcomes = append(outcomes, ping.Outcome{Ping: p, Err: fmt.Errorf("unknown host")})
+			continue
+		}
+		var output []string
+		for i, rtt := range rtts {
+			if rtt >= 0 {
+				output = append(output, fmt.Sprintf("64 bytes from 192.0.2.1: icmp_seq=%d ttl=64 time=%d ms", i+1, rtt.Milliseconds()))
+			}
+		}
+		outcomes = append(outcomes, ping.Outcome{Ping: p, Status: state.PingStatus{
+			Statistics: &state.PingStatistics{Sent: len(rtts)},
+			Output:     output,
+		}})
+	}
+	return outcomes
+}
+
+func Test_defaultService_CompareAll(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	ms := time.Millisecond
+	pinger := &fakePingService{
+		records: map[string][]time.Duration{
+			"A both.example.net":    {10 * ms, 10 * ms},
+			"AAAA both.example.net": {15 * ms, -1},
+			"A v4.example.net":      {10 * ms},
+			"AAAA v4.example.net":   {-1},
+			"AAAA v6.example.net":   {10 * ms},
+		},
+		instances: map[string]bool{},
+	}
+	s := &defaultService{
+		ping:    pinger,
+		options: []ping.Option{ping.InstanceName("services"), ping.Count(2)},
+	}
+
+	comparisons, err := s.CompareAll(ctx, "both.example.net", "v4.example.net", "v6.example.net", "none.example.net")
+	require.NoError(t, err)
+	require.Len(t, comparisons, 4)
+	require.Equal(t, map[string]bool{"services": true}, pinger.instances)
+
+	both := comparisons[0]
+	require.Equal(t, "both.example.net", both.Hostname)
+	require.Equal(t, BothReachable, both.Outcome)
+	require.InDelta(t, 0.5, both.LossDelta, 1e-9)
+	require.Equal(t, 5*ms, both.LatencyDelta)
+
+	require.Equal(t, IPv4Only, comparisons[1].Outcome)
+	require.False(t, comparisons[1].IPv6.Reachable())
+	require.Equal(t, IPv6Only, comparisons[2].Outcome)
+	require.EqualError(t, comparisons[2].IPv4.Err, "unknown host")
+	require.Equal(t, Unreachable, comparisons[3].Outcome)
+
+	comparison, err := s.Compare(ctx, "v4.example.net")
+	require.NoError(t, err)
+	require.Equal(t, IPv4Only, comparison.Outcome)
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package parallel runs diagnostic operations in parallel go routines.
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+)
+
+// Options controls the execution of parallel operations.
+type Options struct {
+	// Concurrency limits the number of operations running in parallel.
+	// A limit less than or equal to zero runs all operations at once.
+	Concurrency int
+	// ContinueOnError keeps running the remaining operations if an operation fails.
+	// Otherwise, all operations are cancelled on the first error.
+	ContinueOnError bool
+}
+
+// Run invokes the given function for the operations 0 to n-1 in parallel go routines and returns the error of each
+// operation. Operations cancelled before they have been started report the cancellation error.
+func Run(ctx rbfs.RbfsContext, n int, options Options, run func(ctx rbfs.RbfsContext, i int) error) []error {
+	limit := options.Concurrency
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+
+	c, cancel := context.WithCancel(ctx)
+	defer cancel()
+	rc := rbfs.MustRbfsContext(c)
+
+	errs := make([]error, n)
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case slots <- struct{}{}:
+		case <-c.Done():
+			errs[i] = c.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := c.Err(); err != nil {
+				// Cancelled while waiting for a free slot
+				errs[i] = err
+				return
+			}
+			errs[i] = run(rc, i)
+			if errs[i] != nil && !options.ContinueOnError {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+// FirstError returns the first of the given errors. The error that caused the cancellation of the other operations
+// is preferred over the cancellation errors.
+func FirstError(errs []error) error {
+	var first error
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case first == nil || errors.Is(first, context.Canceled) && !errors.Is(err, context.Canceled):
+			first = err
+		}
+	}
+	return first
+}
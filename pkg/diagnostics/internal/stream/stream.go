@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package stream reads the plain text output of the opsd streams API incrementally.
+package stream
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// maxErrorBodySize limits the number of bytes read from an error response.
+const maxErrorBodySize = 4096
+
+// Open invokes the given opsd streams resource, e.g. /streams/ping, with the given query parameters
+// and returns the response body to read the command output from.
+func Open(ctx rbfs.RbfsContext, client *http.Client, resource string, query url.Values) (io.ReadCloser, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return nil, err
+	}
+	streamURL := fmt.Sprintf("%s/%s?%s", endpoint, strings.TrimPrefix(resource, "/"), query.Encode())
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, streamURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "text/plain")
+
+	if accessToken, ok := ctx.Value(state.ContextAccessToken).(string); ok {
+		request.Header.Add("Authorization", "Bearer "+accessToken)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		defer response.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+		return nil, fmt.Errorf("cannot open stream %s. Status: %d %s", resource, response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return response.Body, nil
+}
+
+// Lines reads the given output line by line and invokes the handler for each non-empty line.
+// The output is closed when the reader stops, either because all lines are read, the handler returns false
+// or the context is done.
+func Lines(ctx context.Context, output io.ReadCloser, handler func(line string) bool) error {
+	defer output.Close()
+
+	// Unblock the scanner if the context is cancelled while waiting for the next line
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			output.Close()
+		case <-done:
+		}
+	}()
+
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !handler(line) {
+			return nil
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package summary parses the summary lines of the ping and LSPING command output.
+package summary
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+var (
+	// 5 packets transmitted, 4 received, 20% packet loss, time 4005ms
+	transmittedPattern = regexp.MustCompile(`(\d+) packets transmitted, (\d+) (?:packets )?received(?:.*?time (\d+)ms)?`)
+	// rtt min/avg/max/mdev = 0.412/0.512/0.611/0.071 ms
+	rttPattern = regexp.MustCompile(`(?:rtt|round-trip) min/avg/max/(?:mdev|stddev) = ([\d.]+)/([\d.]+)/([\d.]+)/([\d.]+) ms`)
+)
+
+// Parse updates the statistics from a summary line. The round-trip times are reported in milliseconds.
+// False is returned if the line is not a summary line.
+func Parse(line string, statistics *state.PingStatistics) bool {
+	if m := transmittedPattern.FindStringSubmatch(line); m != nil {
+		statistics.Sent, _ = strconv.Atoi(m[1])
+		statistics.Received, _ = strconv.Atoi(m[2])
+		statistics.Lost = statistics.Sent - statistics.Received
+		if m[3] != "" {
+			rtt(statistics).Total, _ = strconv.ParseFloat(m[3], 64)
+		}
+		return true
+	}
+	if m := rttPattern.FindStringSubmatch(line); m != nil {
+		r := rtt(statistics)
+		r.Min, _ = strconv.ParseFloat(m[1], 64)
+		r.Avg, _ = strconv.ParseFloat(m[2], 64)
+		r.Max, _ = strconv.ParseFloat(m[3], 64)
+		r.Mdev, _ = strconv.ParseFloat(m[4], 64)
+		return true
+	}
+	return false
+}
+
+// Complete sets the packet counters from the given number of sent and received packets,
+// if the output did not contain a summary.
+func Complete(statistics *state.PingStatistics, sent, received int) {
+	if statistics.Sent == 0 {
+		statistics.Sent = sent
+		statistics.Received = received
+		statistics.Lost = sent - received
+	}
+}
+
+func rtt(statistics *state.PingStatistics) *state.PingStatisticsRtt {
+	if statistics.Rtt == nil {
+		statistics.Rtt = &state.PingStatisticsRtt{}
+	}
+	return statistics.Rtt
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package lsping pings MPLS label switched paths (LSPING) identified by a forwarding equivalence class (FEC).
+package lsping
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// FecTypeLDP selects a FEC learned via LDP.
+	FecTypeLDP = FecType("ldp")
+	// FecTypeISIS selects a FEC learned via IS-IS segment routing.
+	FecTypeISIS = FecType("isis")
+)
+
+type (
+	// FecType describes how the FEC to be pinged was learned.
+	FecType string
+
+	// LSPing contains all arguments to ping a label switched path.
+	LSPing struct {
+		fecType         FecType
+		destinationFEC  *net.IPNet
+		destinationA    string
+		destinationAAAA string
+		sourceIP        net.IP
+		instanceName    string
+		count           int
+		interval        time.Duration
+		size            int
+	}
+
+	// Option applies a LSPING command argument
+	Option func(*LSPing) error
+)
+
+// NewLSPing creates a new LSPING command
+func NewLSPing(options ...Option) (*LSPing, error) {
+	p := &LSPing{
+		fecType:      FecTypeLDP,
+		instanceName: "default",
+		count:        5,
+		interval:     1 * time.Second,
+		size:         100,
+	}
+
+	// Apply all given LSPING option
+	for _, option := range options {
+		if err := option(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.destinationFEC == nil && p.destinationA == "" && p.destinationAAAA == "" {
+		return nil, fmt.Errorf("lsping destination not specified")
+	}
+
+	return p, nil
+}
+
+// Fec sets the FEC type.
+func Fec(fecType FecType) Option {
+	return func(p *LSPing) error {
+		if fecType != FecTypeLDP && fecType != FecTypeISIS {
+			return fmt.Errorf("unsupported FEC type %s", fecType)
+		}
+		p.fecType = fecType
+		return nil
+	}
+}
+
+// DestinationFEC sets the destination FEC prefix.
+// Override destination host name settings, if any.
+func DestinationFEC(prefix *net.IPNet) Option {
+	return func(p *LSPing) error {
+		if prefix == nil {
+			return fmt.Errorf("destination FEC must not be empty")
+		}
+		p.destinationFEC = prefix
+		p.destinationA = ""
+		p.destinationAAAA = ""
+		return nil
+	}
+}
+
+// DestinationHostNameA sets the destination hostname that shall be translated to an IPv4 host prefix
+// (DNS A record) used as FEC.
+func DestinationHostNameA(hostname string) Option {
+	return func(p *LSPing) error {
+		p.destinationFEC = nil
+		p.destinationA = hostname
+		p.destinationAAAA = ""
+		return nil
+	}
+}
+
+// DestinationHostNameAAAA sets the destination hostname that shall be translated to an IPv6 host prefix
+// (DNS AAAA record) used as FEC.
+func DestinationHostNameAAAA(hostname string) Option {
+	return func(p *LSPing) error {
+		p.destinationFEC = nil
+		p.destinationA = ""
+		p.destinationAAAA = hostname
+		return nil
+	}
+}
+
+// SourceIP specifies the source IP address
+func SourceIP(ipAddress net.IP) Option {
+	return func(p *LSPing) error {
+		p.sourceIP = ipAddress
+		return nil
+	}
+}
+
+// Size sets the packet payload size in bytes.
+// The size must be between 8 and 32768 bytes.
+func Size(size int) Option {
+	return func(p *LSPing) error {
+		if size < 8 || size > 32768 {
+			return fmt.Errorf("packet size must be between 8 and 32768 bytes")
+		}
+		p.size = size
+		return nil
+	}
+}
+
+// Count sets the number of pings to be sent.
+func Count(count int) Option {
+	return func(p *LSPing) error {
+		if count <= 0 {
+			return fmt.Errorf("count value must be greater than 0")
+		}
+
+		const maxAllowedPings = 65535
+		if count > maxAllowedPings {
+			return fmt.Errorf("count value must not be greater than %d", maxAllowedPings)
+		}
+
+		p.count = count
+		return nil
+	}
+}
+
+// Interval sets the interval between two pings.
+// The accepted interval range is between 1 and 60 seconds in full seconds.
+func Interval(interval time.Duration) Option {
+	return func(p *LSPing) error {
+		if interval < 1*time.Second {
+			return fmt.Errorf("interval must not be less than 1s")
+		}
+		if interval > 60*time.Second {
+			return fmt.Errorf("interval must not exceed 60s")
+		}
+		if interval%time.Second != 0 {
+			return fmt.Errorf("interval must be a multiple of 1s")
+		}
+		p.interval = interval
+		return nil
+	}
+}
+
+// InstanceName sets the routing instance name to run the LSPING command.
+func InstanceName(instanceName string) Option {
+	return func(p *LSPing) error {
+		if instanceName == "" {
+			return fmt.Errorf("instance name must not be empty")
+		}
+		p.instanceName = instanceName
+		return nil
+	}
+}
+
+func (p *LSPing) FecType() FecType {
+	return p.fecType
+}
+
+func (p *LSPing) DestinationFEC() *net.IPNet {
+	return p.destinationFEC
+}
+
+func (p *LSPing) DestinationHostNameA() string {
+	return p.destinationA
+}
+
+func (p *LSPing) DestinationHostNameAAAA() string {
+	return p.destinationAAAA
+}
+
+func (p *LSPing) SourceIP() net.IP {
+	return p.sourceIP
+}
+
+func (p *LSPing) InstanceName() string {
+	return p.instanceName
+}
+
+func (p *LSPing) Count() int {
+	return p.count
+}
+
+func (p *LSPing) Interval() time.Duration {
+	return p.interval
+}
+
+func (p *LSPing) Size() int {
+	return p.size
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package lsping
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseCIDR(t *testing.T, cidr string) *net.IPNet {
+	t.Helper()
+	_, prefix, err := net.ParseCIDR(cidr)
+	require.NoError(t, err)
+	return prefix
+}
+
+func TestNewLSPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    *LSPing
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			options: []Option{
+				DestinationHostNameA("pe1.rtbrick.net"),
+			},
+			want: &LSPing{
+				fecType:      FecTypeLDP,
+				destinationA: "pe1.rtbrick.net",
+				instanceName: "default",
+				count:        5,
+				interval:     time.Second,
+				size:         100,
+			},
+		}, {
+			name: "options",
+			options: []Option{
+				Fec(FecTypeISIS),
+				DestinationFEC(mustParseCIDR(t, "198.51.100.1/32")),
+				SourceIP(net.ParseIP("192.0.2.1")),
+				Count(10), Interval(2 * time.Second), Size(1500), InstanceName("default"),
+			},
+			want: &LSPing{
+				fecType:        FecTypeISIS,
+				destinationFEC: mustParseCIDR(t, "198.51.100.1/32"),
+				sourceIP:       net.ParseIP("192.0.2.1"),
+				instanceName:   "default",
+				count:          10,
+				interval:       2 * time.Second,
+				size:           1500,
+			},
+		}, {
+			name:    "destination not specified",
+			options: []Option{Count(1)},
+			wantErr: "lsping destination not specified",
+		}, {
+			name:    "unsupported FEC type",
+			options: []Option{Fec("bgp")},
+			wantErr: "unsupported FEC type bgp",
+		}, {
+			name:    "destination FEC must not be empty",
+			options: []Option{DestinationFEC(nil)},
+			wantErr: "destination FEC must not be empty",
+		}, {
+			name:    "count value must not be greater than 65535",
+			options: []Option{DestinationHostNameA("pe1.rtbrick.net"), Count(65536)},
+			wantErr: "count value must not be greater than 65535",
+		}, {
+			name:    "interval must not be less than 1s",
+			options: []Option{DestinationHostNameA("pe1.rtbrick.net"), Interval(time.Millisecond)},
+			wantErr: "interval must not be less than 1s",
+		}, {
+			name:    "interval must be a multiple of 1s",
+			options: []Option{DestinationHostNameA("pe1.rtbrick.net"), Interval(1500 * time.Millisecond)},
+			wantErr: "interval must be a multiple of 1s",
+		}, {
+			name:    "packet size must be between 8 and 32768 bytes",
+			options: []Option{DestinationHostNameA("pe1.rtbrick.net"), Size(32769)},
+			wantErr: "packet size must be between 8 and 32768 bytes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewLSPing(tt.options...)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package lsping
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/parallel"
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/stream"
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/summary"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// This function variable we need to be able to mock the API Service
+var getActionsAPIFunc = getActionsAPI
+
+type (
+	defaultService struct {
+		client *http.Client
+	}
+
+	// Reply describes the outcome of a single MPLS echo request as reported by the LSPING output.
+	Reply struct {
+		// Sequence holds the sequence number.
+		Sequence int
+		// From holds the address of the replying router.
+		From string
+		// RTT holds the round-trip time.
+		RTT time.Duration
+		// Timeout indicates that no reply was received for the echo request.
+		Timeout bool
+	}
+
+	// Event is emitted by a streaming LSPING. Exactly one of the event fields is set.
+	Event struct {
+		// Reply holds a single LSPING reply.
+		Reply *Reply
+		// Statistics holds the final statistics. The statistics are the last event of a successful LSPING.
+		Statistics *state.PingStatistics
+		// Err holds the error that terminated the stream.
+		Err error
+	}
+
+	// Service pings label switched paths.
+	Service interface {
+		// Run executes the given LSPING.
+		Run(rbfs.RbfsContext, *LSPing) (state.PingStatus, error)
+
+		// RunAll runs all given LSPINGs in parallel go routines.
+		// The results are returned in the order of the given LSPINGs. All LSPINGs are cancelled on the first error.
+		RunAll(rbfs.RbfsContext, ...*LSPing) ([]state.PingStatus, error)
+
+		// Stream executes the given LSPING using the streams API and emits an event per reply, followed by the
+		// final statistics. The event channel is closed when the LSPING terminates or the context is cancelled.
+		Stream(rbfs.RbfsContext, *LSPing) (<-chan Event, error)
+	}
+)
+
+func (s *defaultService) Run(ctx rbfs.RbfsContext, lsping *LSPing) (state.PingStatus, error) {
+	api, err := s.getActionsAPI(ctx)
+	if err != nil {
+		return state.PingStatus{}, err
+	}
+
+	optionalPingMplsArgs := &state.ActionsApiPingMplsOpts{
+		FecType:          rbfs.OptionalString(string(lsping.fecType)),
+		DestinationIpFec: rbfs.OptionalString(lsping.destinationFECString()),
+		DestinationAaaa:  rbfs.OptionalString(lsping.destinationAAAA),
+		DestinationA:     rbfs.OptionalString(lsping.destinationA),
+		SourceIp:         rbfs.OptionalIP(lsping.sourceIP),
+		InstanceName:     rbfs.OptionalString(lsping.instanceName),
+		Count:            rbfs.OptionalInt(lsping.count),
+		Interval:         rbfs.OptionalFloat64(lsping.interval.Seconds()),
+		Size:             rbfs.OptionalInt(lsping.size),
+	}
+
+	//nolint:bodyclose //generated code
+	pingStatus, _, err := api.PingMpls(ctx, optionalPingMplsArgs)
+	if err != nil {
+		return state.PingStatus{}, err
+	}
+	return pingStatus, nil
+}
+
+func (s *defaultService) RunAll(ctx rbfs.RbfsContext, lspings ...*LSPing) ([]state.PingStatus, error) {
+	results := make([]state.PingStatus, len(lspings))
+	errs := parallel.Run(ctx, len(lspings), parallel.Options{}, func(ctx rbfs.RbfsContext, i int) (err error) {
+		results[i], err = s.Run(ctx, lspings[i])
+		return err
+	})
+	if err := parallel.FirstError(errs); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *defaultService) Stream(ctx rbfs.RbfsContext, lsping *LSPing) (<-chan Event, error) {
+	output, err := stream.Open(ctx, s.client, "/streams/ping-mpls", lsping.query())
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		send := func(e Event) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var statistics state.PingStatistics
+		var sent, received int
+		err := stream.Lines(ctx, output, func(line string) bool {
+			if summary.Parse(line, &statistics) {
+				return true
+			}
+			if reply, ok := parseReply(line); ok {
+				sent++
+				if !reply.Timeout {
+					received++
+				}
+				return send(Event{Reply: &reply})
+			}
+			return true
+		})
+		if err != nil {
+			send(Event{Err: err})
+			return
+		}
+
+		summary.Complete(&statistics, sent, received)
+		send(Event{Statistics: &statistics})
+	}()
+	return events, nil
+}
+
+// destinationFECString returns the destination FEC in CIDR notation, if any.
+func (p *LSPing) destinationFECString() string {
+	if p.destinationFEC == nil {
+		return ""
+	}
+	return p.destinationFEC.String()
+}
+
+// query returns the query parameters to invoke the streaming LSPING.
+func (p *LSPing) query() url.Values {
+	query := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	set("fec_type", string(p.fecType))
+	set("destination_ip_fec", p.destinationFECString())
+	set("destination_a", p.destinationA)
+	set("destination_aaaa", p.destinationAAAA)
+	if p.sourceIP != nil {
+		set("source_ip", p.sourceIP.String())
+	}
+	set("instance_name", p.instanceName)
+	set("count", strconv.Itoa(p.count))
+	set("interval", strconv.FormatFloat(p.interval.Seconds(), 'f', -1, 64))
+	set("size", strconv.Itoa(p.size))
+	return query
+}
+
+func (s *defaultService) getActionsAPI(ctx rbfs.RbfsContext) (state.ActionsApi, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return getActionsAPIFunc(s.client, endpoint)
+}
+
+func getActionsAPI(c *http.Client, endpoint *url.URL) (state.ActionsApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.ActionsApi, nil
+}
+
+// NewLSPingService creates a new LSPING defaultService.
+func NewLSPingService(client *http.Client) Service {
+	return &defaultService{client}
+}
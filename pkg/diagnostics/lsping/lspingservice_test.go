@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package lsping
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state/statemock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func expectedLSPing(fec string) *state.ActionsApiPingMplsOpts {
+	return &state.ActionsApiPingMplsOpts{
+		FecType:          rbfs.OptionalString("ldp"),
+		DestinationIpFec: rbfs.OptionalString(fec),
+		DestinationAaaa:  rbfs.OptionalString(""),
+		DestinationA:     rbfs.OptionalString(""),
+		SourceIp:         rbfs.OptionalIP(nil),
+		InstanceName:     rbfs.OptionalString("default"),
+		Count:            rbfs.OptionalInt(5),
+		Interval:         rbfs.OptionalFloat64(1),
+		Size:             rbfs.OptionalInt(100),
+	}
+}
+
+func mustLSPing(t *testing.T, fec string) *LSPing {
+	t.Helper()
+	p, err := NewLSPing(DestinationFEC(mustParseCIDR(t, fec)))
+	require.NoError(t, err)
+	return p
+}
+
+func Test_defaultService_RunAll(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+
+		lspings func(t *testing.T) []*LSPing
+		setup   func(actionApi *statemock.ActionsApi)
+		want    []state.PingStatus
+		wantErr bool
+	}{
+		{
+			name: "results in input order",
+			lspings: func(t *testing.T) []*LSPing {
+				return []*LSPing{mustLSPing(t, "198.51.100.1/32"), mustLSPing(t, "198.51.100.2/32")}
+			},
+			setup: func(actionApi *statemock.ActionsApi) {
+				actionApi.On("PingMpls", mock.Anything, expectedLSPing("198.51.100.1/32")).
+					Return(state.PingStatus{Command: "pe1"}, nil, nil).
+					Run(func(args mock.Arguments) { time.Sleep(200 * time.Millisecond) })
+				actionApi.On("PingMpls", mock.Anything, expectedLSPing("198.51.100.2/32")).
+					Return(state.PingStatus{Command: "pe2"}, nil, nil)
+			},
+			want: []state.PingStatus{{Command: "pe1"}, {Command: "pe2"}},
+		},
+		{
+			name: "error",
+			lspings: func(t *testing.T) []*LSPing {
+				return []*LSPing{mustLSPing(t, "198.51.100.1/32")}
+			},
+			setup: func(actionApi *statemock.ActionsApi) {
+				actionApi.On("PingMpls", mock.Anything, expectedLSPing("198.51.100.1/32")).
+					Return(state.PingStatus{}, nil, fmt.Errorf("test"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionAPI := &statemock.ActionsApi{}
+			getActionsAPIFuncOrig := getActionsAPIFunc
+			defer func() { getActionsAPIFunc = getActionsAPIFuncOrig }()
+			getActionsAPIFunc = func(c *http.Client, endpoint *url.URL) (state.ActionsApi, error) {
+				return actionAPI, nil
+			}
+			actionAPI.Test(t)
+			tt.setup(actionAPI)
+			defer actionAPI.AssertExpectations(t)
+
+			s := NewLSPingService(nil)
+			got, err := s.RunAll(ctx, tt.lspings(t)...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_defaultService_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/rbfs/elements/test/services/opsd/proxy/streams/ping-mpls", r.URL.Path)
+		require.Equal(t, "198.51.100.1/32", r.URL.Query().Get("destination_ip_fec"))
+		require.Equal(t, "ldp", r.URL.Query().Get("fec_type"))
+		for _, line := range []string{
+			"LSPING 198.51.100.1/32 FEC type ldp",
+			"Reply from 198.51.100.1: seq=1 time=1.500 ms",
+			"Request timed out: seq=2",
+			"--- 198.51.100.1/32 lsping statistics ---",
+			"2 packets transmitted, 1 received, 50% packet loss",
+			"rtt min/avg/max/mdev = 1.500/1.500/1.500/0.000 ms",
+		} {
+			fmt.Fprintln(w, line)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	events, err := NewLSPingService(nil).Stream(ctx, mustLSPing(t, "198.51.100.1/32"))
+	require.NoError(t, err)
+
+	var got []Event
+	for e := range events {
+		got = append(got, e)
+	}
+	require.Equal(t, []Event{
+		{Reply: &Reply{Sequence: 1, From: "198.51.100.1", RTT: 1500 * time.Microsecond}},
+		{Reply: &Reply{Sequence: 2, Timeout: true}},
+		{Statistics: &state.PingStatistics{
+			Sent:     2,
+			Received: 1,
+			Lost:     1,
+			Rtt:      &state.PingStatisticsRtt{Min: 1.5, Avg: 1.5, Max: 1.5},
+		}},
+	}, got)
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package lsping
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	// Reply from 198.51.100.1: seq=1 time=1.234 ms
+	sequencePattern = regexp.MustCompile(`\bseq(?:uence)?[=:\s]\s*(\d+)`)
+	timePattern     = regexp.MustCompile(`\btime[=<]\s*([\d.]+)\s*ms`)
+	fromPattern     = regexp.MustCompile(`(?i)\bfrom\s+([^\s:,]+)`)
+	// Request timed out: seq=2
+	timeoutPattern = regexp.MustCompile(`(?i)time(?:d)?\s?out|no (?:reply|answer)`)
+)
+
+// parseReply parses a LSPING output line reporting an echo reply or a lost echo request.
+// False is returned if the line does not describe an echo request.
+func parseReply(line string) (Reply, bool) {
+	seq := sequencePattern.FindStringSubmatch(line)
+	if seq == nil {
+		return Reply{}, false
+	}
+	reply := Reply{}
+	reply.Sequence, _ = strconv.Atoi(seq[1])
+
+	if m := timePattern.FindStringSubmatch(line); m != nil {
+		ms, _ := strconv.ParseFloat(m[1], 64)
+		reply.RTT = time.Duration(ms * float64(time.Millisecond))
+		if from := fromPattern.FindStringSubmatch(line); from != nil {
+			reply.From = from[1]
+		}
+		return reply, true
+	}
+	if timeoutPattern.MatchString(line) {
+		reply.Timeout = true
+		return reply, true
+	}
+	return Reply{}, false
+}
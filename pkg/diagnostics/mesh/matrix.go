@@ -0,0 +1,237 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package mesh
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+)
+
+type (
+	// Cell holds the outcome of a single target.
+	Cell struct {
+		// Target holds the pinged target.
+		Target Target
+		// Source holds the row label, i.e. the name of the element executing the ping.
+		Source string
+		// Destination holds the column label.
+		Destination string
+		// Statistics holds the loss and latency statistics of a successful ping.
+		Statistics ping.Statistics
+		// Err holds the error of a failed ping.
+		Err error
+	}
+
+	// Matrix holds the cells of all targets, with a row per source element and a column per destination.
+	Matrix struct {
+		// Sources holds the row labels in the order of the targets.
+		Sources []string
+		// Destinations holds the column labels in the order of the targets.
+		Destinations []string
+		// Cells holds a cell per target in the order of the targets.
+		Cells []Cell
+	}
+
+	// Thresholds defines the loss and latency limits of a healthy mesh.
+	Thresholds struct {
+		// MaxLoss holds the maximum tolerated loss ratio between 0 and 1.
+		MaxLoss float64
+		// MaxLatency holds the maximum tolerated average round-trip time. Zero disables the latency check.
+		MaxLatency time.Duration
+	}
+
+	// cellView is the JSON and CSV representation of a cell.
+	cellView struct {
+		Source      string  `json:"source"`
+		Destination string  `json:"destination"`
+		Address     string  `json:"address"`
+		Instance    string  `json:"instance,omitempty"`
+		Sent        int     `json:"sent"`
+		Received    int     `json:"received"`
+		Loss        float64 `json:"loss"`
+		AvgMs       float64 `json:"avg_ms"`
+		MaxMs       float64 `json:"max_ms"`
+		JitterMs    float64 `json:"jitter_ms"`
+		Error       string  `json:"error,omitempty"`
+		Violation   bool    `json:"violation"`
+	}
+)
+
+func newCell(target Target) Cell {
+	return Cell{Target: target, Source: target.Element, Destination: target.column()}
+}
+
+func newMatrix(cells []Cell) *Matrix {
+	m := &Matrix{Cells: cells}
+	sources, destinations := map[string]bool{}, map[string]bool{}
+	for _, cell := range cells {
+		if !sources[cell.Source] {
+			sources[cell.Source] = true
+			m.Sources = append(m.Sources, cell.Source)
+		}
+		if !destinations[cell.Destination] {
+			destinations[cell.Destination] = true
+			m.Destinations = append(m.Destinations, cell.Destination)
+		}
+	}
+	return m
+}
+
+// Cell returns the cell of the given source and destination.
+func (m *Matrix) Cell(source, destination string) (Cell, bool) {
+	for _, cell := range m.Cells {
+		if cell.Source == source && cell.Destination == destination {
+			return cell, true
+		}
+	}
+	return Cell{}, false
+}
+
+// Violated reports whether the given cell failed or exceeds the thresholds.
+func (t Thresholds) Violated(cell Cell) bool {
+	if cell.Err != nil {
+		return true
+	}
+	if cell.Statistics.Loss > t.MaxLoss {
+		return true
+	}
+	return t.MaxLatency > 0 && cell.Statistics.Avg > t.MaxLatency
+}
+
+// Violations returns all cells failed or exceeding the given thresholds.
+func (m *Matrix) Violations(thresholds Thresholds) []Cell {
+	var violations []Cell
+	for _, cell := range m.Cells {
+		if thresholds.Violated(cell) {
+			violations = append(violations, cell)
+		}
+	}
+	return violations
+}
+
+// WriteJSON writes all cells as JSON array.
+func (m *Matrix) WriteJSON(w io.Writer, thresholds Thresholds) error {
+	views := make([]cellView, 0, len(m.Cells))
+	for _, cell := range m.Cells {
+		views = append(views, newCellView(cell, thresholds))
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(views)
+}
+
+// WriteCSV writes all cells as CSV with a header line and a line per cell.
+func (m *Matrix) WriteCSV(w io.Writer, thresholds Thresholds) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{
+		"source", "destination", "address", "instance", "sent", "received", "loss", "avg_ms", "max_ms", "jitter_ms",
+		"error", "violation",
+	}); err != nil {
+		return err
+	}
+	for _, cell := range m.Cells {
+		v := newCellView(cell, thresholds)
+		if err := out.Write([]string{
+			v.Source,
+			v.Destination,
+			v.Address,
+			v.Instance,
+			strconv.Itoa(v.Sent),
+			strconv.Itoa(v.Received),
+			formatFloat(v.Loss),
+			formatFloat(v.AvgMs),
+			formatFloat(v.MaxMs),
+			formatFloat(v.JitterMs),
+			v.Error,
+			strconv.FormatBool(v.Violation),
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteTable writes the matrix as text table with a row per source and a column per destination.
+// Each cell shows the loss and the average round-trip time. Cells violating the thresholds are marked with '!'.
+func (m *Matrix) WriteTable(w io.Writer, thresholds Thresholds) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "SOURCE")
+	for _, destination := range m.Destinations {
+		fmt.Fprintf(tw, "\t%s", destination)
+	}
+	fmt.Fprintln(tw)
+
+	for _, source := range m.Sources {
+		fmt.Fprint(tw, source)
+		for _, destination := range m.Destinations {
+			cell, ok := m.Cell(source, destination)
+			fmt.Fprintf(tw, "\t%s", formatCell(cell, ok, thresholds))
+		}
+		fmt.Fprintln(tw)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if violations := len(m.Violations(thresholds)); violations > 0 {
+		_, err := fmt.Fprintf(w, "\n%d of %d cells exceed thresholds (!)\n", violations, len(m.Cells))
+		return err
+	}
+	return nil
+}
+
+func newCellView(cell Cell, thresholds Thresholds) cellView {
+	v := cellView{
+		Source:      cell.Source,
+		Destination: cell.Destination,
+		Address:     cell.Target.Destination.String(),
+		Instance:    cell.Target.Instance,
+		Sent:        cell.Statistics.Sent,
+		Received:    cell.Statistics.Received,
+		Loss:        cell.Statistics.Loss,
+		AvgMs:       milliseconds(cell.Statistics.Avg),
+		MaxMs:       milliseconds(cell.Statistics.Max),
+		JitterMs:    milliseconds(cell.Statistics.Jitter),
+		Violation:   thresholds.Violated(cell),
+	}
+	if cell.Err != nil {
+		v.Error = cell.Err.Error()
+	}
+	return v
+}
+
+// formatCell returns the text table representation of a cell.
+func formatCell(cell Cell, ok bool, thresholds Thresholds) string {
+	if !ok {
+		return "-"
+	}
+	marker := ""
+	if thresholds.Violated(cell) {
+		marker = "!"
+	}
+	if cell.Err != nil {
+		return "error" + marker
+	}
+	const percent = 100
+	return fmt.Sprintf("%.1f%% %.2fms%s", cell.Statistics.Loss*percent, milliseconds(cell.Statistics.Avg), marker)
+}
+
+func milliseconds(d time.Duration) float64 {
+	const precision = 1000
+	return float64(d.Microseconds()) / precision
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
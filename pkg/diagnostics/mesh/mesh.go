@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package mesh verifies the reachability between elements by pinging every target from its source element and
+// collecting the loss and latency of all pings in a matrix.
+package mesh
+
+import (
+	"net"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
+)
+
+type (
+	// Target describes a single ping executed on an element.
+	Target struct {
+		// Element holds the name of the element executing the ping.
+		Element string
+		// Source holds the optional source IP address of the ping.
+		Source net.IP
+		// Destination holds the pinged IP address.
+		Destination net.IP
+		// DestinationName holds the optional name of the destination used to label the matrix column.
+		// The destination IP address is used if no name is set.
+		DestinationName string
+		// Instance holds the name of the routing instance. The default instance is used if no instance is set.
+		Instance string
+	}
+
+	// Node describes an element participating in a full mesh.
+	Node struct {
+		// Element holds the element name.
+		Element string
+		// Loopback holds the loopback IP address of the element.
+		Loopback net.IP
+	}
+
+	// LoopbackFunc returns the loopback IP address of the given element or nil if the element has no loopback.
+	LoopbackFunc func(elements.Element) net.IP
+)
+
+// column returns the label of the matrix column of this target.
+func (t Target) column() string {
+	if t.DestinationName != "" {
+		return t.DestinationName
+	}
+	return t.Destination.String()
+}
+
+// FullMesh returns the targets to ping the loopback of every node from the loopback of every other node.
+func FullMesh(nodes []Node, instance string) []Target {
+	var targets []Target
+	for _, source := range nodes {
+		for _, destination := range nodes {
+			if source.Element == destination.Element {
+				continue
+			}
+			targets = append(targets, Target{
+				Element:         source.Element,
+				Source:          source.Loopback,
+				Destination:     destination.Loopback,
+				DestinationName: destination.Element,
+				Instance:        instance,
+			})
+		}
+	}
+	return targets
+}
+
+// DiscoverNodes returns a node for every operational element managed by the CTRLD instance.
+// Elements without a loopback IP address are skipped.
+func DiscoverNodes(ctx rbfs.RbfsContext, client elements.Client, loopback LoopbackFunc) ([]Node, error) {
+	list, err := client.ListElements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []Node
+	for _, element := range list {
+		if element.ContainerState != elements.ContainerStateRunning ||
+			element.OperationalState != elements.OperationalStateUp {
+			continue
+		}
+		if ip := loopback(element); ip != nil {
+			nodes = append(nodes, Node{Element: element.ElementName, Loopback: ip})
+		}
+	}
+	return nodes, nil
+}
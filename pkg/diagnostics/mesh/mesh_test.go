@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package mesh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	fakePingService struct {
+		ping.Service
+		mu      sync.Mutex
+		calls   []string
+		results map[string]func(p *ping.Ping) (state.PingStatus, error)
+	}
+
+	fakeElementsClient struct {
+		elements.Client
+		elements []elements.Element
+	}
+)
+
+func (s *fakePingService) Run(ctx rbfs.RbfsContext, p *ping.Ping) (state.PingStatus, error) {
+	key := rbfs.ElementName(ctx) + "->" + p.DestinationIP().String()
+	s.mu.Lock()
+	s.calls = append(s.calls, key)
+	s.mu.Unlock()
+	return s.results[key](p)
+}
+
+func (s *fakePingService) RunBatch(ctx rbfs.RbfsContext, pings []*ping.Ping, _ ...ping.BatchOption) []ping.Outcome {
+	outcomes := make([]ping.Outcome, len(pings))
+	for i, p := range pings {
+		outcomes[i].Ping = p
+		if outcomes[i].Err = ctx.Err(); outcomes[i].Err == nil {
+			outcomes[i].Status, outcomes[i].Err = s.Run(rbfs.WithElement(ctx, p.Element()), p)
+		}
+	}
+	return outcomes
+}
+
+func (c *fakeElementsClient) ListElements(rbfs.RbfsContext) ([]elements.Element, error) {
+	return c.elements, nil
+}
+
+func replies(rtts ...time.Duration) func(p *ping.Ping) (state.PingStatus, error) {
+	return func(p *ping.Ping) (state.PingStatus, error) {
+		output := []string{}
+		for i, rtt := range rtts {
+			if rtt < 0 {
+				continue
+			}
+			output = append(output, fmt.Sprintf("64 bytes from %s: icmp_seq=%d ttl=64 time=%.2f ms",
+				p.DestinationIP(), i+1, float64(rtt.Microseconds())/1000))
+		}
+		return state.PingStatus{
+			Statistics: &state.PingStatistics{Sent: len(rtts)},
+			Output:     output,
+		}, nil
+	}
+}
+
+func testContext(t *testing.T) rbfs.RbfsContext {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "ctrld")
+	require.NoError(t, err)
+	return ctx
+}
+
+func TestDiscoverNodes(t *testing.T) {
+	client := &fakeElementsClient{elements: []elements.Element{
+		{ElementName: "leaf01", ContainerState: elements.ContainerStateRunning, OperationalState: elements.OperationalStateUp},
+		{ElementName: "leaf02", ContainerState: elements.ContainerStateRunning, OperationalState: elements.OperationalStateDown},
+		{ElementName: "spine01", ContainerState: elements.ContainerStateRunning, OperationalState: elements.OperationalStateUp},
+		{ElementName: "spine02", ContainerState: elements.ContainerStateStopper, OperationalState: elements.OperationalStateUp},
+		{ElementName: "spine03", ContainerState: elements.ContainerStateRunning, OperationalState: elements.OperationalStateUp},
+	}}
+	loopbacks := map[string]net.IP{
+		"leaf01":  net.ParseIP("192.0.2.1"),
+		"spine01": net.ParseIP("192.0.2.11"),
+	}
+
+	nodes, err := DiscoverNodes(testContext(t), client, func(e elements.Element) net.IP { return loopbacks[e.ElementName] })
+	require.NoError(t, err)
+	require.Equal(t, []Node{
+		{Element: "leaf01", Loopback: loopbacks["leaf01"]},
+		{Element: "spine01", Loopback: loopbacks["spine01"]},
+	}, nodes)
+
+	targets := FullMesh(nodes, "default")
+	require.Equal(t, []Target{
+		{Element: "leaf01", Source: loopbacks["leaf01"], Destination: loopbacks["spine01"], DestinationName: "spine01", Instance: "default"},
+		{Element: "spine01", Source: loopbacks["spine01"], Destination: loopbacks["leaf01"], DestinationName: "leaf01", Instance: "default"},
+	}, targets)
+}
+
+func TestRunner_Run(t *testing.T) {
+	nodes := []Node{
+		{Element: "leaf01", Loopback: net.ParseIP("192.0.2.1")},
+		{Element: "leaf02", Loopback: net.ParseIP("192.0.2.2")},
+		{Element: "spine01", Loopback: net.ParseIP("192.0.2.11")},
+	}
+	ms := time.Millisecond
+	service := &fakePingService{results: map[string]func(p *ping.Ping) (state.PingStatus, error){
+		"leaf01->192.0.2.2":  replies(ms, 3*ms),
+		"leaf01->192.0.2.11": replies(ms, ms),
+		"leaf02->192.0.2.1":  replies(2*ms, -1),
+		"leaf02->192.0.2.11": replies(ms, ms),
+		"spine01->192.0.2.1": func(*ping.Ping) (state.PingStatus, error) { return state.PingStatus{}, fmt.Errorf("unreachable") },
+		"spine01->192.0.2.2": replies(ms, ms),
+	}}
+
+	m := NewRunner(service, Concurrency(2), PingOptions(ping.Count(2))).Run(testContext(t), FullMesh(nodes, ""))
+	require.Len(t, service.calls, 6)
+	require.Equal(t, []string{"leaf01", "leaf02", "spine01"}, m.Sources)
+	require.Equal(t, []string{"leaf02", "spine01", "leaf01"}, m.Destinations)
+
+	cell, ok := m.Cell("leaf01", "leaf02")
+	require.True(t, ok)
+	require.Equal(t, 2*ms, cell.Statistics.Avg)
+	cell, _ = m.Cell("leaf02", "leaf01")
+	require.Equal(t, 0.5, cell.Statistics.Loss)
+	cell, _ = m.Cell("spine01", "leaf01")
+	require.EqualError(t, cell.Err, "unreachable")
+	_, ok = m.Cell("leaf01", "leaf01")
+	require.False(t, ok)
+
+	thresholds := Thresholds{MaxLoss: 0.1, MaxLatency: 1500 * time.Microsecond}
+	require.Len(t, m.Violations(thresholds), 3)
+
+	var table bytes.Buffer
+	require.NoError(t, m.WriteTable(&table, thresholds))
+	require.Equal(t, `SOURCE   leaf02        spine01      leaf01
+leaf01   0.0% 2.00ms!  0.0% 1.00ms  -
+leaf02   -             0.0% 1.00ms  50.0% 2.00ms!
+spine01  0.0% 1.00ms   -            error!
+
+3 of 6 cells exceed thresholds (!)
+`, table.String())
+
+	var csv bytes.Buffer
+	require.NoError(t, m.WriteCSV(&csv, thresholds))
+	require.Contains(t, csv.String(), "source,destination,address,instance,sent,received,loss,avg_ms,max_ms,jitter_ms,error,violation\n")
+	require.Contains(t, csv.String(), "leaf02,leaf01,192.0.2.1,,2,1,0.5,2,2,0,,true\n")
+	require.Contains(t, csv.String(), "spine01,leaf01,192.0.2.1,,0,0,0,0,0,0,unreachable,true\n")
+
+	var js bytes.Buffer
+	require.NoError(t, m.WriteJSON(&js, thresholds))
+	require.Contains(t, js.String(), `"error": "unreachable"`)
+	require.Contains(t, js.String(), `"avg_ms": 2,`)
+}
+
+func TestRunner_RunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(testContext(t))
+	cancel()
+	service := &fakePingService{}
+	m := NewRunner(service, Concurrency(1)).Run(rbfs.MustRbfsContext(ctx), []Target{
+		{Element: "leaf01", Destination: net.ParseIP("192.0.2.2")},
+	})
+	require.Empty(t, service.calls)
+	require.ErrorIs(t, m.Cells[0].Err, context.Canceled)
+}
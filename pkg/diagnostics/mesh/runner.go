@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package mesh
+
+import (
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+)
+
+type (
+	// Runner pings all targets of a mesh.
+	Runner struct {
+		service     ping.Service
+		concurrency int
+		options     []ping.Option
+	}
+
+	// Option applies a runner option.
+	Option func(*Runner)
+)
+
+// Concurrency limits the number of pings running at the same time. The default limit is 10.
+func Concurrency(limit int) Option {
+	return func(r *Runner) {
+		if limit > 0 {
+			r.concurrency = limit
+		}
+	}
+}
+
+// PingOptions sets additional options applied to every ping, e.g. the number of echo requests.
+func PingOptions(options ...ping.Option) Option {
+	return func(r *Runner) {
+		r.options = append(r.options, options...)
+	}
+}
+
+// NewRunner creates a new mesh runner using the given ping service.
+func NewRunner(service ping.Service, options ...Option) *Runner {
+	r := &Runner{service: service, concurrency: 10}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Run pings all targets, each from the RBFS context of its element, and returns the resulting matrix.
+// A failed ping is recorded in the matrix cell of the target and does not stop the remaining pings.
+func (r *Runner) Run(ctx rbfs.RbfsContext, targets []Target) *Matrix {
+	cells := make([]Cell, len(targets))
+	pings := make([]*ping.Ping, 0, len(targets))
+	indexes := make([]int, 0, len(targets))
+	for i, target := range targets {
+		cells[i] = newCell(target)
+		p, err := r.newPing(target)
+		if err != nil {
+			cells[i].Err = err
+			continue
+		}
+		pings = append(pings, p)
+		indexes = append(indexes, i)
+	}
+
+	outcomes := r.service.RunBatch(ctx, pings, ping.Concurrency(r.concurrency), ping.ContinueOnError())
+	for j, outcome := range outcomes {
+		cell := &cells[indexes[j]]
+		if outcome.Err != nil {
+			cell.Err = outcome.Err
+			continue
+		}
+		cell.Statistics = ping.NewResult(outcome.Status).Statistics
+	}
+	return newMatrix(cells)
+}
+
+// newPing returns the ping of a single target.
+func (r *Runner) newPing(target Target) (*ping.Ping, error) {
+	options := []ping.Option{ping.Element(target.Element), ping.DestinationIP(target.Destination)}
+	if target.Source != nil {
+		options = append(options, ping.SourceIP(target.Source))
+	}
+	if target.Instance != "" {
+		options = append(options, ping.InstanceName(target.Instance))
+	}
+	return ping.NewPing(append(options, r.options...)...)
+}
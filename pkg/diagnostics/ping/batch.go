@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/parallel"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+type (
+	// Outcome holds the result of a single ping executed by RunBatch.
+	Outcome struct {
+		// Ping holds the executed ping.
+		Ping *Ping
+		// Status holds the ping status, if the ping succeeded.
+		Status state.PingStatus
+		// Err holds the error, if the ping failed or was cancelled before completion.
+		Err error
+	}
+
+	// BatchOption applies a RunBatch setting.
+	BatchOption func(*batch)
+
+	batch parallel.Options
+)
+
+// Concurrency limits the number of pings running in parallel.
+// A limit less than or equal to zero runs all pings at once.
+func Concurrency(limit int) BatchOption {
+	return func(b *batch) {
+		b.Concurrency = limit
+	}
+}
+
+// ContinueOnError keeps running the remaining pings if a ping fails.
+func ContinueOnError() BatchOption {
+	return func(b *batch) {
+		b.ContinueOnError = true
+	}
+}
+
+func (s *defaultService) RunBatch(ctx rbfs.RbfsContext, pings []*Ping, options ...BatchOption) []Outcome {
+	b := batch{}
+	for _, option := range options {
+		option(&b)
+	}
+
+	outcomes := make([]Outcome, len(pings))
+	errs := parallel.Run(ctx, len(pings), parallel.Options(b), func(ctx rbfs.RbfsContext, i int) (err error) {
+		outcomes[i].Status, err = s.Run(ctx, pings[i])
+		return err
+	})
+	for i, ping := range pings {
+		outcomes[i].Ping = ping
+		outcomes[i].Err = errs[i]
+	}
+	return outcomes
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_defaultService_RunBatch(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	hostnames := []string{"a.rtbrick.com", "b.rtbrick.com", "c.rtbrick.com", "d.rtbrick.com"}
+	var pings []*Ping
+	for _, hostname := range hostnames {
+		p, err := NewPing(DestinationHostNameA(hostname))
+		require.NoError(t, err)
+		pings = append(pings, p)
+	}
+
+	tests := []struct {
+		name    string
+		options []BatchOption
+		setup   func(t *testing.T, actionApi *mockActionsAPI)
+		want    func(t *testing.T, outcomes []Outcome)
+	}{
+		{
+			name:    "concurrency limit",
+			options: []BatchOption{Concurrency(2)},
+			setup: func(t *testing.T, actionApi *mockActionsAPI) {
+				var running, maxRunning int32
+				t.Cleanup(func() { require.Equal(t, int32(2), maxRunning) })
+				for _, hostname := range hostnames {
+					actionApi.On("Ping", mock.Anything, expectedPing(hostname)).
+						Return(state.PingStatus{Command: hostname}, nil).
+						Run(func(args mock.Arguments) {
+							n := atomic.AddInt32(&running, 1)
+							for {
+								m := atomic.LoadInt32(&maxRunning)
+								if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+									break
+								}
+							}
+							time.Sleep(50 * time.Millisecond)
+							atomic.AddInt32(&running, -1)
+						})
+				}
+			},
+			want: func(t *testing.T, outcomes []Outcome) {
+				for i, outcome := range outcomes {
+					require.Same(t, pings[i], outcome.Ping)
+					require.NoError(t, outcome.Err)
+					require.Equal(t, hostnames[i], outcome.Status.Command)
+				}
+			},
+		},
+		{
+			name:    "continue on error",
+			options: []BatchOption{ContinueOnError()},
+			setup: func(t *testing.T, actionApi *mockActionsAPI) {
+				for i, hostname := range hostnames {
+					call := actionApi.On("Ping", mock.Anything, expectedPing(hostname))
+					if i == 1 {
+						call.Return(state.PingStatus{}, fmt.Errorf("unreachable"))
+					} else {
+						call.Return(state.PingStatus{Command: hostname}, nil)
+					}
+				}
+			},
+			want: func(t *testing.T, outcomes []Outcome) {
+				require.Len(t, outcomes, 4)
+				require.EqualError(t, outcomes[1].Err, "unreachable")
+				for _, i := range []int{0, 2, 3} {
+					require.NoError(t, outcomes[i].Err)
+					require.Equal(t, hostnames[i], outcomes[i].Status.Command)
+				}
+			},
+		},
+		{
+			name:    "cancel pending pings on first error",
+			options: []BatchOption{Concurrency(1)},
+			setup: func(t *testing.T, actionApi *mockActionsAPI) {
+				actionApi.On("Ping", mock.Anything, expectedPing(hostnames[0])).
+					Return(state.PingStatus{}, fmt.Errorf("unreachable"))
+			},
+			want: func(t *testing.T, outcomes []Outcome) {
+				require.EqualError(t, outcomes[0].Err, "unreachable")
+				for _, outcome := range outcomes[1:] {
+					require.ErrorIs(t, outcome.Err, context.Canceled)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionAPI := &mockActionsAPI{}
+			getActionsAPIFuncOrig := getActionsAPIFunc
+			defer func() { getActionsAPIFunc = getActionsAPIFuncOrig }()
+			getActionsAPIFunc = func(c *http.Client, endpoint *url.URL) (ActionsAPI, error) {
+				return actionAPI, nil
+			}
+			actionAPI.Test(t)
+			tt.setup(t, actionAPI)
+			defer actionAPI.AssertExpectations(t)
+
+			s := NewPingService(nil)
+			tt.want(t, s.RunBatch(ctx, pings, tt.options...))
+		})
+	}
+}
+
+func Test_defaultService_RunBatch_Elements(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	actionAPI := &mockActionsAPI{}
+	actionAPI.Test(t)
+	getActionsAPIFuncOrig := getActionsAPIFunc
+	defer func() { getActionsAPIFunc = getActionsAPIFuncOrig }()
+	getActionsAPIFunc = func(c *http.Client, endpoint *url.URL) (ActionsAPI, error) {
+		return actionAPI, nil
+	}
+
+	var pings []*Ping
+	for _, element := range []string{"leaf1", "leaf2"} {
+		element := element
+		p, err := NewPing(DestinationHostNameA("www.rtbrick.com"), Element(element))
+		require.NoError(t, err)
+		pings = append(pings, p)
+		actionAPI.On("Ping", mock.MatchedBy(func(ctx context.Context) bool { return rbfs.ElementName(ctx) == element }),
+			expectedPing("www.rtbrick.com")).Return(state.PingStatus{Command: element}, nil)
+	}
+	defer actionAPI.AssertExpectations(t)
+
+	outcomes := NewPingService(nil).RunBatch(ctx, pings)
+	require.Equal(t, "leaf1", outcomes[0].Status.Command)
+	require.Equal(t, "leaf2", outcomes[1].Status.Command)
+}
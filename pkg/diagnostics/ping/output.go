@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	// 64 bytes from 198.51.100.1: icmp_seq=1 ttl=64 time=0.512 ms
+	replyPattern = regexp.MustCompile(`^\s*(\d+) bytes from (.+?): icmp_seq=(\d+)(?:.*?ttl=(\d+))?(?:.*?time[=<]([\d.]+) ms)?`)
+	// no answer yet for icmp_seq=2
+	// Request timeout for icmp_seq 2
+	timeoutPattern = regexp.MustCompile(`(?:no answer yet for icmp_seq=|Request timeout for icmp_seq )(\d+)`)
+	// From 198.51.100.1 icmp_seq=3 Destination Host Unreachable
+	unreachablePattern = regexp.MustCompile(`^\s*From (\S+) icmp_seq=(\d+) `)
+)
+
+// parseReply parses a ping output line reporting an echo reply or a lost echo request.
+// False is returned if the line does not describe an echo request.
+func parseReply(line string) (Reply, bool) {
+	if m := replyPattern.FindStringSubmatch(line); m != nil {
+		return Reply{
+			Bytes:    atoi(m[1]),
+			From:     m[2],
+			Sequence: atoi(m[3]),
+			TTL:      atoi(m[4]),
+			RTT:      milliseconds(m[5]),
+		}, true
+	}
+	if m := timeoutPattern.FindStringSubmatch(line); m != nil {
+		return Reply{Sequence: atoi(m[1]), Timeout: true}, true
+	}
+	if m := unreachablePattern.FindStringSubmatch(line); m != nil {
+		return Reply{From: m[1], Sequence: atoi(m[2]), Timeout: true}, true
+	}
+	return Reply{}, false
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+func atof(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// milliseconds converts the given number of milliseconds to a duration.
+func milliseconds(s string) time.Duration {
+	return time.Duration(atof(s) * float64(time.Millisecond))
+}
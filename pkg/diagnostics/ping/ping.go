@@ -7,8 +7,11 @@ package ping
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
 )
 
 type (
@@ -24,6 +27,8 @@ type (
 		interval        time.Duration
 		size            int
 		ttl             int
+		tos             int
+		element         string
 	}
 
 	// Option applies a ping command argument
@@ -77,6 +82,18 @@ func TTL(ttl int) Option {
 	}
 }
 
+// TOS sets the IP type of service (TOS) byte, e.g. to verify the latency of a DSCP class.
+// The TOS value must be between 0 and 255. The default TOS value is 0.
+func TOS(tos int) Option {
+	return func(p *Ping) error {
+		if tos < 0 || tos > 255 {
+			return fmt.Errorf("packet TOS must be between 0 and 255")
+		}
+		p.tos = tos
+		return nil
+	}
+}
+
 // DestinationIP sets the ping destination IP address.
 // Override destination host name settings, if any.
 func DestinationIP(ipAddr net.IP) Option {
@@ -178,6 +195,32 @@ func InstanceName(instanceName string) Option {
 	}
 }
 
+// Element runs the ping from the given element instead of the element of the RBFS context.
+// Use this option to run pings from several elements with RunAll or RunBatch.
+func Element(elementName string) Option {
+	return func(p *Ping) error {
+		if elementName == "" {
+			return fmt.Errorf("element name must not be empty")
+		}
+		p.element = elementName
+		return nil
+	}
+}
+
+// context returns the RBFS context of the element to run the ping from.
+func (p *Ping) context(ctx rbfs.RbfsContext) rbfs.RbfsContext {
+	if p.element == "" {
+		return ctx
+	}
+	return rbfs.WithElement(ctx, p.element)
+}
+
+// intervalSeconds returns the interval in seconds with millisecond precision.
+func (p *Ping) intervalSeconds() float32 {
+	const scaleToMilliPrecision = 1000
+	return float32(math.Round(p.interval.Seconds()*scaleToMilliPrecision) / scaleToMilliPrecision)
+}
+
 func (p *Ping) SourceInterface() string {
 	return p.sourceInterface
 }
@@ -194,6 +237,10 @@ func (p *Ping) TTL() int {
 	return p.ttl
 }
 
+func (p *Ping) TOS() int {
+	return p.tos
+}
+
 func (p *Ping) Count() int {
 	return p.count
 }
@@ -202,6 +249,10 @@ func (p *Ping) Interval() time.Duration {
 	return p.interval
 }
 
+func (p *Ping) InstanceName() string {
+	return p.instanceName
+}
+
 func (p *Ping) DestinationIP() net.IP {
 	return p.destinationIP
 }
@@ -213,3 +264,7 @@ func (p *Ping) DestinationHostNameA() string {
 func (p *Ping) DestinationHostNameAAAA() string {
 	return p.destinationAAAA
 }
+
+func (p *Ping) Element() string {
+	return p.element
+}
@@ -50,6 +50,21 @@ func TestNewPing(t *testing.T) {
 				size:            56,
 				ttl:             64,
 			},
+		}, {
+			name: "type of service",
+			options: []Option{
+				DestinationIP(net.ParseIP("8.8.8.8")),
+				TOS(0xb8),
+			},
+			want: &Ping{
+				destinationIP: net.ParseIP("8.8.8.8"),
+				instanceName:  "default",
+				count:         5,
+				interval:      time.Second,
+				size:          56,
+				ttl:           64,
+				tos:           0xb8,
+			},
 		}, {
 			name: "source interface",
 			options: []Option{
@@ -116,6 +131,13 @@ func TestNewPing(t *testing.T) {
 				InstanceName(""),
 			},
 			wantErr: "instance name must not be empty",
+		}, {
+			name: "element name must not be empty",
+			options: []Option{
+				DestinationHostNameA("www.rtbrick.com"),
+				Element(""),
+			},
+			wantErr: "element name must not be empty",
 		},
 	}
 	for _, tt := range tests {
@@ -7,10 +7,10 @@ package ping
 
 import (
 	"context"
-	"math"
 	"net/http"
 	"net/url"
 
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/parallel"
 	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
 	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
 )
@@ -20,7 +20,8 @@ var getActionsAPIFunc = getActionsAPI
 
 type (
 	defaultService struct {
-		client *http.Client
+		client    *http.Client
+		preflight bool
 	}
 
 	ActionsAPI interface {
@@ -33,19 +34,38 @@ type (
 		Run(rbfs.RbfsContext, *Ping) (state.PingStatus, error)
 
 		// RunAll runs all given pings in parallel go routines.
+		// The statuses of the successful pings are returned in the order of the given pings.
+		// All pending pings are cancelled on the first error.
 		RunAll(rbfs.RbfsContext, ...*Ping) ([]state.PingStatus, error)
+
+		// RunBatch runs all given pings in parallel go routines and returns one outcome per ping in the order of
+		// the given pings. By default, all pings run at once and all pending pings are cancelled on the first error.
+		RunBatch(ctx rbfs.RbfsContext, pings []*Ping, options ...BatchOption) []Outcome
+
+		// Stream executes the given ping using the streams API and emits an event per ping reply,
+		// followed by the final statistics. The event channel is closed when the ping terminates or
+		// the context is cancelled.
+		Stream(rbfs.RbfsContext, *Ping) (<-chan Event, error)
+
+		// Validate verifies that the instance, the source interface and the source IP address of the given ping
+		// exist on the element. A *ValidationError is returned if a ping argument is invalid.
+		Validate(rbfs.RbfsContext, *Ping) error
 	}
 )
 
 func (s *defaultService) Run(ctx rbfs.RbfsContext, ping *Ping) (state.PingStatus, error) {
+	ctx = ping.context(ctx)
+	if s.preflight {
+		if err := s.Validate(ctx, ping); err != nil {
+			return state.PingStatus{}, err
+		}
+	}
+
 	api, err := s.getActionsAPI(ctx)
 	if err != nil {
 		return state.PingStatus{}, err
 	}
 
-	const scaleToMilliPrecision = 1000
-	interval := float32(math.Round(ping.interval.Seconds()*scaleToMilliPrecision) / scaleToMilliPrecision)
-
 	optionalPingPostArgs := &state.ActionsApiPingOpts{
 		DestinationIp:   rbfs.OptionalIP(ping.destinationIP),
 		DestinationAaaa: rbfs.OptionalString(ping.destinationAAAA),
@@ -53,10 +73,11 @@ func (s *defaultService) Run(ctx rbfs.RbfsContext, ping *Ping) (state.PingStatus
 		SourceIp:        rbfs.OptionalIP(ping.sourceIP),
 		SourceIfl:       rbfs.OptionalString(ping.sourceInterface),
 		Count:           rbfs.OptionalInt(ping.count),
-		Interval:        rbfs.OptionalFloat32(interval),
+		Interval:        rbfs.OptionalFloat32(ping.intervalSeconds()),
 		InstanceName:    rbfs.OptionalString(ping.instanceName),
 		Size:            rbfs.OptionalInt(ping.size),
 		Ttl:             rbfs.OptionalInt(ping.ttl),
+		Tos:             rbfs.OptionalInt(ping.tos),
 	}
 
 	//nolint:bodyclose //generated code
@@ -69,37 +90,14 @@ func (s *defaultService) Run(ctx rbfs.RbfsContext, ping *Ping) (state.PingStatus
 
 func (s *defaultService) RunAll(ctx rbfs.RbfsContext, pings ...*Ping) ([]state.PingStatus, error) {
 	var r []state.PingStatus
-	dataDataChannel := make(chan state.PingStatus)
-	errChannel := make(chan error)
-
-	c, cancle := context.WithCancel(ctx)
-	defer cancle()
-
-	rc := rbfs.MustRbfsContext(c)
-	for _, ping := range pings {
-		go func(p *Ping) {
-			s, err := s.Run(rc, p)
-			if err != nil {
-				errChannel <- err
-				return
-			}
-			dataDataChannel <- s
-		}(ping)
-	}
-
-	var err error
-	for range pings {
-		select {
-		case data := <-dataDataChannel:
-			r = append(r, data)
-		case e := <-errChannel:
-			if err == nil {
-				err = e
-				cancle()
-			}
+	errs := make([]error, 0, len(pings))
+	for _, outcome := range s.RunBatch(ctx, pings) {
+		if outcome.Err == nil {
+			r = append(r, outcome.Status)
 		}
+		errs = append(errs, outcome.Err)
 	}
-	return r, err
+	return r, parallel.FirstError(errs)
 }
 
 func (s *defaultService) getActionsAPI(ctx rbfs.RbfsContext) (ActionsAPI, error) {
@@ -117,6 +115,10 @@ func getActionsAPI(c *http.Client, endpoint *url.URL) (ActionsAPI, error) {
 }
 
 // NewPingService creates a new ping defaultService.
-func NewPingService(client *http.Client) Service {
-	return &defaultService{client}
+func NewPingService(client *http.Client, options ...ServiceOption) Service {
+	s := &defaultService{client: client}
+	for _, option := range options {
+		option(s)
+	}
+	return s
 }
@@ -166,7 +166,7 @@ func Test_defaultService_RunAll(t *testing.T) {
 				return
 			}
 			require.NoError(t, err)
-			require.ElementsMatch(t, tt.want, got)
+			require.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// These function variables we need to be able to mock the API Services
+var (
+	getInterfacesAPIFunc = getInterfacesAPI
+	getRoutesAPIFunc     = getRoutesAPI
+)
+
+type (
+	// ServiceOption applies a ping service option.
+	ServiceOption func(*defaultService)
+
+	// ValidationError is returned by the pre-flight validation if a ping argument does not match the element's
+	// configuration.
+	ValidationError struct {
+		// Argument holds the name of the invalid ping argument.
+		Argument string
+		// Value holds the invalid argument value.
+		Value string
+		// Reason describes why the value is invalid.
+		Reason string
+	}
+)
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid ping %s %s: %s", e.Argument, e.Value, e.Reason)
+}
+
+// Preflight validates the instance name, the source interface and the source IP address of every ping against the
+// element's configuration before the ping is sent.
+func Preflight() ServiceOption {
+	return func(s *defaultService) {
+		s.preflight = true
+	}
+}
+
+func (s *defaultService) Validate(ctx rbfs.RbfsContext, ping *Ping) error {
+	ctx = ping.context(ctx)
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return err
+	}
+	routesAPI, err := getRoutesAPIFunc(s.client, endpoint)
+	if err != nil {
+		return err
+	}
+
+	//nolint:bodyclose //generated code
+	summary, _, err := routesAPI.GetRoutesSummary(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := summary[ping.instanceName]; !ok {
+		return &ValidationError{Argument: "instance", Value: ping.instanceName, Reason: "instance does not exist"}
+	}
+
+	if ping.sourceInterface != "" {
+		interfacesAPI, err := getInterfacesAPIFunc(s.client, endpoint)
+		if err != nil {
+			return err
+		}
+		if err := validateSourceInterface(ctx, interfacesAPI, ping); err != nil {
+			return err
+		}
+	}
+
+	if ping.sourceIP != nil {
+		return validateSourceIP(ctx, routesAPI, ping)
+	}
+	return nil
+}
+
+// validateSourceInterface verifies that the source interface exists, is up and belongs to the ping instance.
+func validateSourceInterface(ctx context.Context, api state.InterfacesApi, ping *Ping) error {
+	invalid := func(reason string) error {
+		return &ValidationError{Argument: "source interface", Value: ping.sourceInterface, Reason: reason}
+	}
+
+	//nolint:bodyclose //generated code
+	ifl, resp, err := api.GetLogicalInterface(ctx, ping.sourceInterface)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return invalid("logical interface does not exist")
+		}
+		return err
+	}
+	if !strings.EqualFold(ifl.OperationalState, "up") {
+		return invalid(fmt.Sprintf("logical interface is not up (operational state %s)", ifl.OperationalState))
+	}
+	if ifl.InstanceName != "" && ifl.InstanceName != ping.instanceName {
+		return invalid(fmt.Sprintf("logical interface belongs to instance %s", ifl.InstanceName))
+	}
+	return nil
+}
+
+// validateSourceIP verifies that the source IP address is configured in the ping instance, i.e. that the instance
+// has a local host route for the source IP address. The prefix filter of the routes API matches prefixes exactly,
+// hence only the host route itself is returned.
+func validateSourceIP(ctx context.Context, api state.RoutesApi, ping *Ping) error {
+	afi, prefix := "ipv4", ping.sourceIP.String()+"/32"
+	opts := &state.RoutesApiGetInstanceUnicastRoutesOpts{Prefix4: rbfs.OptionalString(prefix)}
+	if ping.sourceIP.To4() == nil {
+		afi, prefix = "ipv6", ping.sourceIP.String()+"/128"
+		opts = &state.RoutesApiGetInstanceUnicastRoutesOpts{Prefix6: rbfs.OptionalString(prefix)}
+	}
+
+	//nolint:bodyclose //generated code
+	routes, resp, err := api.GetInstanceUnicastRoutes(ctx, ping.instanceName, afi, "unicast", opts)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return err
+	}
+	for _, route := range routes.Routes {
+		if (route.Prefix4 == prefix || route.Prefix6 == prefix) && strings.EqualFold(route.Source, "local") {
+			return nil
+		}
+	}
+	return &ValidationError{
+		Argument: "source IP",
+		Value:    ping.sourceIP.String(),
+		Reason:   fmt.Sprintf("address is not configured in instance %s", ping.instanceName),
+	}
+}
+
+func getInterfacesAPI(c *http.Client, endpoint *url.URL) (state.InterfacesApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.InterfacesApi, nil
+}
+
+func getRoutesAPI(c *http.Client, endpoint *url.URL) (state.RoutesApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.RoutesApi, nil
+}
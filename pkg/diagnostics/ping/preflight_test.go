@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state/statemock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_defaultService_Preflight(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	summary := map[string]state.RoutesSummary{"default": {}, "services": {}}
+	notFound := &http.Response{StatusCode: http.StatusNotFound}
+
+	tests := []struct {
+		name    string
+		options []Option
+		setup   func(interfacesAPI *statemock.InterfacesApi, routesAPI *statemock.RoutesApi, actionAPI *mockActionsAPI)
+		wantErr string
+	}{
+		{
+			name:    "unknown instance",
+			options: []Option{InstanceName("voice")},
+			wantErr: "invalid ping instance voice: instance does not exist",
+		},
+		{
+			name:    "unknown source interface",
+			options: []Option{SourceInterface("ifl-0/0/9/1")},
+			setup: func(interfacesAPI *statemock.InterfacesApi, _ *statemock.RoutesApi, _ *mockActionsAPI) {
+				interfacesAPI.On("GetLogicalInterface", mock.Anything, "ifl-0/0/9/1").
+					Return(nil, notFound, fmt.Errorf("404 Not Found"))
+			},
+			wantErr: "invalid ping source interface ifl-0/0/9/1: logical interface does not exist",
+		},
+		{
+			name:    "source interface down",
+			options: []Option{SourceInterface("ifl-0/0/1/1")},
+			setup: func(interfacesAPI *statemock.InterfacesApi, _ *statemock.RoutesApi, _ *mockActionsAPI) {
+				interfacesAPI.On("GetLogicalInterface", mock.Anything, "ifl-0/0/1/1").
+					Return(state.LogicalInterface{OperationalState: "DOWN", InstanceName: "default"}, nil, nil)
+			},
+			wantErr: "invalid ping source interface ifl-0/0/1/1: logical interface is not up (operational state DOWN)",
+		},
+		{
+			name:    "source interface in other instance",
+			options: []Option{SourceInterface("ifl-0/0/1/1")},
+			setup: func(interfacesAPI *statemock.InterfacesApi, _ *statemock.RoutesApi, _ *mockActionsAPI) {
+				interfacesAPI.On("GetLogicalInterface", mock.Anything, "ifl-0/0/1/1").
+					Return(state.LogicalInterface{OperationalState: "UP", InstanceName: "services"}, nil, nil)
+			},
+			wantErr: "invalid ping source interface ifl-0/0/1/1: logical interface belongs to instance services",
+		},
+		{
+			name:    "source IP not configured",
+			options: []Option{SourceIP(net.ParseIP("192.0.2.9"))},
+			setup: func(_ *statemock.InterfacesApi, routesAPI *statemock.RoutesApi, _ *mockActionsAPI) {
+				routesAPI.On("GetInstanceUnicastRoutes", mock.Anything, "default", "ipv4", "unicast",
+					&state.RoutesApiGetInstanceUnicastRoutesOpts{Prefix4: rbfs.OptionalString("192.0.2.9/32")}).
+					Return(state.InstanceUnicastRoutes{Routes: []state.Routes{{Prefix4: "192.0.2.9/32", Source: "static"}}}, nil, nil)
+			},
+			wantErr: "invalid ping source IP 192.0.2.9: address is not configured in instance default",
+		},
+		{
+			name:    "valid",
+			options: []Option{SourceIP(net.ParseIP("2001:db8::1")), InstanceName("services")},
+			setup: func(_ *statemock.InterfacesApi, routesAPI *statemock.RoutesApi, actionAPI *mockActionsAPI) {
+				routesAPI.On("GetInstanceUnicastRoutes", mock.Anything, "services", "ipv6", "unicast",
+					&state.RoutesApiGetInstanceUnicastRoutesOpts{Prefix6: rbfs.OptionalString("2001:db8::1/128")}).
+					Return(state.InstanceUnicastRoutes{Routes: []state.Routes{{Prefix6: "2001:db8::1/128", Source: "local"}}}, nil, nil)
+				actionAPI.On("Ping", mock.Anything, mock.Anything).Return(state.PingStatus{Command: "ping"}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interfacesAPI, routesAPI, actionAPI := &statemock.InterfacesApi{}, &statemock.RoutesApi{}, &mockActionsAPI{}
+			getInterfacesAPIFuncOrig, getRoutesAPIFuncOrig, getActionsAPIFuncOrig := getInterfacesAPIFunc, getRoutesAPIFunc, getActionsAPIFunc
+			defer func() {
+				getInterfacesAPIFunc, getRoutesAPIFunc, getActionsAPIFunc = getInterfacesAPIFuncOrig, getRoutesAPIFuncOrig, getActionsAPIFuncOrig
+			}()
+			getInterfacesAPIFunc = func(c *http.Client, endpoint *url.URL) (state.InterfacesApi, error) { return interfacesAPI, nil }
+			getRoutesAPIFunc = func(c *http.Client, endpoint *url.URL) (state.RoutesApi, error) { return routesAPI, nil }
+			getActionsAPIFunc = func(c *http.Client, endpoint *url.URL) (ActionsAPI, error) { return actionAPI, nil }
+			interfacesAPI.Test(t)
+			routesAPI.Test(t)
+			actionAPI.Test(t)
+			routesAPI.On("GetRoutesSummary", mock.Anything).Return(summary, nil, nil)
+			if tt.setup != nil {
+				tt.setup(interfacesAPI, routesAPI, actionAPI)
+			}
+			defer interfacesAPI.AssertExpectations(t)
+			defer routesAPI.AssertExpectations(t)
+			defer actionAPI.AssertExpectations(t)
+
+			p, err := NewPing(append([]Option{DestinationIP(net.ParseIP("198.51.100.1"))}, tt.options...)...)
+			require.NoError(t, err)
+			s := NewPingService(nil, Preflight())
+			status, err := s.Run(ctx, p)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "ping", status.Command)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+type (
+	// LossBurst describes consecutive lost echo requests.
+	LossBurst struct {
+		// Sequence holds the sequence number of the first lost echo request.
+		Sequence int
+		// Length holds the number of consecutive lost echo requests.
+		Length int
+	}
+
+	// Statistics holds the latency and loss statistics computed from the individual echo requests.
+	Statistics struct {
+		// Sent holds the number of echo requests sent.
+		Sent int
+		// Received holds the number of echo replies received.
+		Received int
+		// Lost holds the number of lost echo requests.
+		Lost int
+		// Loss holds the loss ratio between 0 and 1.
+		Loss float64
+		// Min holds the minimum round-trip time.
+		Min time.Duration
+		// Avg holds the average round-trip time.
+		Avg time.Duration
+		// Max holds the maximum round-trip time.
+		Max time.Duration
+		// Mdev holds the mean deviation of the round-trip times as reported by the Linux ping command.
+		Mdev time.Duration
+		// Jitter holds the mean difference of the round-trip times of consecutive echo replies.
+		Jitter time.Duration
+		// P50 holds the median round-trip time.
+		P50 time.Duration
+		// P90 holds the 90th percentile of the round-trip times.
+		P90 time.Duration
+		// P95 holds the 95th percentile of the round-trip times.
+		P95 time.Duration
+		// P99 holds the 99th percentile of the round-trip times.
+		P99 time.Duration
+		// LossBursts holds all sequences of consecutive lost echo requests.
+		LossBursts []LossBurst
+	}
+
+	// Result holds the ping status together with the echo requests parsed from the ping output and the
+	// statistics computed from them.
+	Result struct {
+		// Status holds the original ping status.
+		Status state.PingStatus
+		// Replies holds one reply per echo request ordered by sequence number.
+		// Echo requests without a reply are reported as timed out.
+		// Replies is empty if the ping output does not report any echo reply.
+		Replies []Reply
+		// Statistics holds the statistics computed from the replies or, if there are none, the statistics
+		// reported by the ping status.
+		Statistics Statistics
+	}
+)
+
+// NewResult parses the output of the given ping status and computes the statistics. If the output is missing or
+// does not report any echo reply, the statistics reported by the ping status are used instead. Jitter, percentiles
+// and loss bursts cannot be computed in this case.
+func NewResult(status state.PingStatus) *Result {
+	var replies []Reply
+	for _, line := range status.Output {
+		if reply, ok := parseReply(line); ok {
+			replies = append(replies, reply)
+		}
+	}
+	if len(replies) == 0 && status.Statistics != nil {
+		return &Result{Status: status, Statistics: aggregate(status.Statistics)}
+	}
+
+	sent := 0
+	if status.Statistics != nil {
+		sent = status.Statistics.Sent
+	}
+	replies = completeReplies(replies, sent)
+	return &Result{
+		Status:     status,
+		Replies:    replies,
+		Statistics: Analyze(replies),
+	}
+}
+
+// completeReplies orders the replies by sequence number, drops duplicate replies and adds a timed out reply for
+// each echo request not reported by the ping output. The Linux ping command reports lost echo requests only if
+// requested explicitly.
+func completeReplies(replies []Reply, sent int) []Reply {
+	if len(replies) == 0 && sent == 0 {
+		return nil
+	}
+
+	first, bySequence := 1, make(map[int]Reply, len(replies))
+	for _, reply := range replies {
+		if reply.Sequence < first {
+			first = reply.Sequence
+		}
+		if existing, ok := bySequence[reply.Sequence]; !ok || existing.Timeout {
+			bySequence[reply.Sequence] = reply
+		}
+	}
+
+	last := first + sent - 1
+	for sequence := range bySequence {
+		if sequence > last {
+			last = sequence
+		}
+	}
+
+	completed := make([]Reply, 0, last-first+1)
+	for sequence := first; sequence <= last; sequence++ {
+		reply, ok := bySequence[sequence]
+		if !ok {
+			reply = Reply{Sequence: sequence, Timeout: true}
+		}
+		completed = append(completed, reply)
+	}
+	return completed
+}
+
+// aggregate returns the statistics reported by opsd. The round-trip times are reported in milliseconds.
+func aggregate(statistics *state.PingStatistics) Statistics {
+	s := Statistics{Sent: statistics.Sent, Received: statistics.Received, Lost: statistics.Sent - statistics.Received}
+	if s.Lost < 0 {
+		s.Lost = 0
+	}
+	if s.Sent > 0 {
+		s.Loss = float64(s.Lost) / float64(s.Sent)
+	}
+	if rtt := statistics.Rtt; rtt != nil && s.Received > 0 {
+		milliseconds := func(ms float64) time.Duration {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+		s.Min = milliseconds(rtt.Min)
+		s.Avg = milliseconds(rtt.Avg)
+		s.Max = milliseconds(rtt.Max)
+		s.Mdev = milliseconds(rtt.Mdev)
+		if rtt.Mdev == 0 {
+			s.Mdev = milliseconds(rtt.Stddev)
+		}
+	}
+	return s
+}
+
+// Analyze computes the statistics from the given replies ordered by sequence number.
+func Analyze(replies []Reply) Statistics {
+	s := Statistics{Sent: len(replies)}
+
+	var rtts []time.Duration
+	var burst *LossBurst
+	for _, reply := range replies {
+		if reply.Timeout {
+			if burst == nil {
+				s.LossBursts = append(s.LossBursts, LossBurst{Sequence: reply.Sequence})
+				burst = &s.LossBursts[len(s.LossBursts)-1]
+			}
+			burst.Length++
+			continue
+		}
+		burst = nil
+		rtts = append(rtts, reply.RTT)
+	}
+
+	s.Received = len(rtts)
+	s.Lost = s.Sent - s.Received
+	if s.Sent > 0 {
+		s.Loss = float64(s.Lost) / float64(s.Sent)
+	}
+	if len(rtts) == 0 {
+		return s
+	}
+
+	var sum, sumOfSquares, sumOfDifferences float64
+	for i, rtt := range rtts {
+		sum += float64(rtt)
+		sumOfSquares += float64(rtt) * float64(rtt)
+		if i > 0 {
+			sumOfDifferences += math.Abs(float64(rtt - rtts[i-1]))
+		}
+	}
+	n := float64(len(rtts))
+	mean := sum / n
+	s.Avg = time.Duration(mean)
+	s.Mdev = time.Duration(math.Sqrt(math.Max(sumOfSquares/n-mean*mean, 0)))
+	if len(rtts) > 1 {
+		s.Jitter = time.Duration(sumOfDifferences / (n - 1))
+	}
+
+	sorted := append([]time.Duration(nil), rtts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	s.Min = sorted[0]
+	s.Max = sorted[len(sorted)-1]
+	s.P50 = percentile(sorted, 50)
+	s.P90 = percentile(sorted, 90)
+	s.P95 = percentile(sorted, 95)
+	s.P99 = percentile(sorted, 99)
+	return s
+}
+
+// Percentile returns the round-trip time percentile (nearest rank) of the received echo replies.
+// Zero is returned if no echo reply was received.
+func (r *Result) Percentile(p float64) time.Duration {
+	var sorted []time.Duration
+	for _, reply := range r.Replies {
+		if !reply.Timeout {
+			sorted = append(sorted, reply.RTT)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return percentile(sorted, p)
+}
+
+// percentile returns the nearest rank percentile of the given sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	const hundred = 100
+	rank := int(math.Ceil(p / hundred * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
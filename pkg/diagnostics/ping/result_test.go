@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResult(t *testing.T) {
+	status := state.PingStatus{
+		Statistics: &state.PingStatistics{Sent: 8, Received: 5, Lost: 3},
+		Output: []string{
+			"PING 198.51.100.1 (198.51.100.1) 56(84) bytes of data.",
+			"64 bytes from 198.51.100.1: icmp_seq=1 ttl=64 time=1.00 ms",
+			"64 bytes from 198.51.100.1: icmp_seq=2 ttl=64 time=3.00 ms",
+			"64 bytes from 198.51.100.1: icmp_seq=2 ttl=64 time=3.50 ms (DUP!)",
+			"64 bytes from 198.51.100.1: icmp_seq=5 ttl=64 time=2.00 ms",
+			"no answer yet for icmp_seq=6",
+			"64 bytes from 198.51.100.1: icmp_seq=7 ttl=64 time=2.00 ms",
+			"64 bytes from 198.51.100.1: icmp_seq=8 ttl=63 time=4.00 ms",
+			"",
+			"--- 198.51.100.1 ping statistics ---",
+			"8 packets transmitted, 5 received, 37.5% packet loss, time 7010ms",
+		},
+	}
+
+	result := NewResult(status)
+	require.Equal(t, status, result.Status)
+	require.Len(t, result.Replies, 8)
+	for i, reply := range result.Replies {
+		require.Equal(t, i+1, reply.Sequence)
+	}
+	require.Equal(t, Reply{Sequence: 2, Bytes: 64, From: "198.51.100.1", TTL: 64, RTT: 3 * time.Millisecond}, result.Replies[1])
+	require.Equal(t, Reply{Sequence: 3, Timeout: true}, result.Replies[2])
+	require.Equal(t, 63, result.Replies[7].TTL)
+
+	s := result.Statistics
+	require.Equal(t, 8, s.Sent)
+	require.Equal(t, 5, s.Received)
+	require.Equal(t, 3, s.Lost)
+	require.InDelta(t, 0.375, s.Loss, 1e-9)
+	require.Equal(t, 1*time.Millisecond, s.Min)
+	require.Equal(t, 2400*time.Microsecond, s.Avg)
+	require.Equal(t, 4*time.Millisecond, s.Max)
+	// RTTs 1, 3, 2, 2, 4 ms: differences 2, 1, 0, 2 ms
+	require.Equal(t, 1250*time.Microsecond, s.Jitter)
+	require.InDelta(t, float64(1019803*time.Nanosecond), float64(s.Mdev), float64(time.Microsecond))
+	require.Equal(t, 2*time.Millisecond, s.P50)
+	require.Equal(t, 4*time.Millisecond, s.P95)
+	require.Equal(t, []LossBurst{{Sequence: 3, Length: 2}, {Sequence: 6, Length: 1}}, s.LossBursts)
+	require.Equal(t, 3*time.Millisecond, result.Percentile(80))
+}
+
+func TestNewResult_WithoutOutput(t *testing.T) {
+	status := state.PingStatus{
+		Statistics: &state.PingStatistics{
+			Sent:     4,
+			Received: 3,
+			Lost:     1,
+			Rtt:      &state.PingStatisticsRtt{Min: 0.5, Avg: 1.25, Max: 2, Mdev: 0.25},
+		},
+		Output: []string{"unexpected output"},
+	}
+
+	result := NewResult(status)
+	require.Empty(t, result.Replies)
+	require.Equal(t, Statistics{
+		Sent:     4,
+		Received: 3,
+		Lost:     1,
+		Loss:     0.25,
+		Min:      500 * time.Microsecond,
+		Avg:      1250 * time.Microsecond,
+		Max:      2 * time.Millisecond,
+		Mdev:     250 * time.Microsecond,
+	}, result.Statistics)
+
+	status.Output = nil
+	require.Equal(t, result.Statistics, NewResult(status).Statistics)
+}
+
+func TestAnalyze(t *testing.T) {
+	require.Equal(t, Statistics{}, Analyze(nil))
+
+	s := Analyze([]Reply{{Sequence: 1, Timeout: true}, {Sequence: 2, Timeout: true}})
+	require.Equal(t, Statistics{
+		Sent:       2,
+		Lost:       2,
+		Loss:       1,
+		LossBursts: []LossBurst{{Sequence: 1, Length: 2}},
+	}, s)
+
+	s = Analyze([]Reply{{Sequence: 1, RTT: time.Millisecond}})
+	require.Equal(t, time.Duration(0), s.Jitter)
+	require.Equal(t, time.Millisecond, s.P99)
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/stream"
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/summary"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+type (
+	// Reply describes the outcome of a single echo request as reported by the ping output.
+	Reply struct {
+		// Sequence holds the ICMP sequence number.
+		Sequence int
+		// Bytes holds the number of bytes received.
+		Bytes int
+		// From holds the address of the responding host.
+		From string
+		// TTL holds the IP time-to-live value of the reply.
+		TTL int
+		// RTT holds the round-trip time.
+		RTT time.Duration
+		// Timeout indicates that no reply was received for the echo request.
+		Timeout bool
+	}
+
+	// Event is emitted by a streaming ping. Exactly one of the event fields is set.
+	Event struct {
+		// Reply holds a single ping reply.
+		Reply *Reply
+		// Statistics holds the final ping statistics. The statistics are the last event of a successful ping.
+		Statistics *state.PingStatistics
+		// Err holds the error that terminated the stream.
+		Err error
+	}
+)
+
+func (s *defaultService) Stream(ctx rbfs.RbfsContext, ping *Ping) (<-chan Event, error) {
+	ctx = ping.context(ctx)
+	if s.preflight {
+		if err := s.Validate(ctx, ping); err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := stream.Open(ctx, s.client, "/streams/ping", ping.query())
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		send := func(e Event) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var statistics state.PingStatistics
+		var sent, received int
+		err := stream.Lines(ctx, output, func(line string) bool {
+			if reply, ok := parseReply(line); ok {
+				sent++
+				if !reply.Timeout {
+					received++
+				}
+				return send(Event{Reply: &reply})
+			}
+			summary.Parse(line, &statistics)
+			return true
+		})
+		if err != nil {
+			send(Event{Err: err})
+			return
+		}
+
+		summary.Complete(&statistics, sent, received)
+		send(Event{Statistics: &statistics})
+	}()
+	return events, nil
+}
+
+// query returns the query parameters to invoke the streaming ping.
+func (p *Ping) query() url.Values {
+	query := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if p.destinationIP != nil {
+		set("destination_ip", p.destinationIP.String())
+	}
+	set("destination_a", p.destinationA)
+	set("destination_aaaa", p.destinationAAAA)
+	if p.sourceIP != nil {
+		set("source_ip", p.sourceIP.String())
+	}
+	set("source_ifl", p.sourceInterface)
+	set("instance_name", p.instanceName)
+	set("count", strconv.Itoa(p.count))
+	set("interval", strconv.FormatFloat(float64(p.intervalSeconds()), 'f', -1, 32))
+	set("size", strconv.Itoa(p.size))
+	set("ttl", strconv.Itoa(p.ttl))
+	if p.tos != 0 {
+		set("tos", strconv.Itoa(p.tos))
+	}
+	return query
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package ping
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/stretchr/testify/require"
+)
+
+const streamPingPath = "/api/v1/rbfs/elements/test/services/opsd/proxy/streams/ping"
+
+var pingOutput = []string{
+	"PING 198.51.100.1 (198.51.100.1) 56(84) bytes of data.",
+	"64 bytes from 198.51.100.1: icmp_seq=1 ttl=64 time=0.512 ms",
+	"no answer yet for icmp_seq=2",
+	"64 bytes from 198.51.100.1: icmp_seq=3 ttl=64 time=1.25 ms",
+	"",
+	"--- 198.51.100.1 ping statistics ---",
+	"3 packets transmitted, 2 received, 33.3333% packet loss, time 2003ms",
+	"rtt min/avg/max/mdev = 0.512/0.881/1.250/0.369 ms",
+}
+
+func newStreamServer(t *testing.T, handler http.HandlerFunc) rbfs.RbfsContext {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test", rbfs.RbfsAccessToken("token"))
+	require.NoError(t, err)
+	return ctx
+}
+
+func writeLines(w http.ResponseWriter, lines ...string) {
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+		w.(http.Flusher).Flush()
+	}
+}
+
+func TestService_Stream(t *testing.T) {
+	ctx := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, streamPingPath, r.URL.Path)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.Equal(t, url.Values{
+			"destination_ip": {"198.51.100.1"},
+			"instance_name":  {"default"},
+			"count":          {"3"},
+			"interval":       {"0.2"},
+			"size":           {"56"},
+			"ttl":            {"64"},
+		}, r.URL.Query())
+		writeLines(w, pingOutput...)
+	})
+
+	p, err := NewPing(DestinationIP(net.ParseIP("198.51.100.1")), Count(3), Interval(200*time.Millisecond))
+	require.NoError(t, err)
+
+	events, err := NewPingService(nil).Stream(ctx, p)
+	require.NoError(t, err)
+
+	var got []Event
+	for e := range events {
+		got = append(got, e)
+	}
+	require.Equal(t, []Event{
+		{Reply: &Reply{Sequence: 1, Bytes: 64, From: "198.51.100.1", TTL: 64, RTT: 512 * time.Microsecond}},
+		{Reply: &Reply{Sequence: 2, Timeout: true}},
+		{Reply: &Reply{Sequence: 3, Bytes: 64, From: "198.51.100.1", TTL: 64, RTT: 1250 * time.Microsecond}},
+		{Statistics: &state.PingStatistics{
+			Sent:     3,
+			Received: 2,
+			Lost:     1,
+			Rtt:      &state.PingStatisticsRtt{Min: 0.512, Avg: 0.881, Max: 1.25, Mdev: 0.369, Total: 2003},
+		}},
+	}, got)
+}
+
+func TestService_StreamWithoutSummary(t *testing.T) {
+	ctx := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeLines(w, pingOutput[:4]...)
+	})
+	p, err := NewPing(DestinationHostNameA("www.rtbrick.com"))
+	require.NoError(t, err)
+
+	events, err := NewPingService(nil).Stream(ctx, p)
+	require.NoError(t, err)
+
+	var last Event
+	for e := range events {
+		last = e
+	}
+	require.Equal(t, &state.PingStatistics{Sent: 3, Received: 2, Lost: 1}, last.Statistics)
+}
+
+func TestService_StreamCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	ctx := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeLines(w, pingOutput[:2]...)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	c, cancel := context.WithCancel(ctx)
+	p, err := NewPing(DestinationHostNameA("www.rtbrick.com"))
+	require.NoError(t, err)
+
+	events, err := NewPingService(nil).Stream(rbfs.MustRbfsContext(c), p)
+	require.NoError(t, err)
+
+	e := <-events
+	require.Equal(t, 1, e.Reply.Sequence)
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		for ok {
+			_, ok = <-events
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event channel not closed after cancellation")
+	}
+}
+
+func TestService_StreamError(t *testing.T) {
+	ctx := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid destination", http.StatusBadRequest)
+	})
+	p, err := NewPing(DestinationHostNameA("www.rtbrick.com"))
+	require.NoError(t, err)
+
+	_, err = NewPingService(nil).Stream(ctx, p)
+	require.EqualError(t, err, "cannot open stream /streams/ping. Status: 400 invalid destination")
+}
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		line string
+		want Reply
+		ok   bool
+	}{
+		{
+			line: "64 bytes from 2001:db8::1: icmp_seq=7 ttl=58 time=10.5 ms",
+			want: Reply{Sequence: 7, Bytes: 64, From: "2001:db8::1", TTL: 58, RTT: 10500 * time.Microsecond},
+			ok:   true,
+		}, {
+			line: "64 bytes from dns.google (8.8.8.8): icmp_seq=2 ttl=117 time=9.52 ms",
+			want: Reply{Sequence: 2, Bytes: 64, From: "dns.google (8.8.8.8)", TTL: 117, RTT: 9520 * time.Microsecond},
+			ok:   true,
+		}, {
+			line: "Request timeout for icmp_seq 4",
+			want: Reply{Sequence: 4, Timeout: true},
+			ok:   true,
+		}, {
+			line: "From 192.0.2.1 icmp_seq=5 Destination Host Unreachable",
+			want: Reply{Sequence: 5, From: "192.0.2.1", Timeout: true},
+			ok:   true,
+		}, {
+			line: "PING 198.51.100.1 (198.51.100.1) 56(84) bytes of data.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := parseReply(tt.line)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package pmtu discovers the path MTU towards a destination by pinging the destination with different packet sizes.
+//
+// The opsd ping API does not allow setting the don't fragment flag. Echo requests exceeding the MTU of an outgoing
+// interface may be fragmented instead of dropped, by the source element and, for IPv4, by the routers along the
+// path. Fragmented packets pass, so that the discovered path MTU is too large. The result can be trusted only if
+// the probed packets are not fragmented, e.g. on paths across MPLS cores whose routers do not fragment labelled
+// packets. Use CompareInterfaceMTU to detect a path MTU exceeding the IP MTU of the source interface, which
+// indicates fragmentation at the source element.
+package pmtu
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// minSize and maxSize hold the ping packet size limits.
+	minSize = 8
+	maxSize = 32768
+
+	// ipv4HeaderSize and ipv6HeaderSize hold the IP header size plus the ICMP header size added to the ping packet
+	// size.
+	ipv4HeaderSize = 28
+	ipv6HeaderSize = 48
+)
+
+type (
+	// Probe contains all arguments to discover the path MTU towards a destination IP address.
+	Probe struct {
+		destinationIP   net.IP
+		sourceIP        net.IP
+		sourceInterface string
+		instanceName    string
+		minSize         int
+		maxSize         int
+		count           int
+		interval        time.Duration
+		compareMTU      bool
+	}
+
+	// Option applies a probe argument.
+	Option func(*Probe) error
+)
+
+// NewProbe creates a new path MTU probe.
+// By default, the probe searches packet sizes between 1200 and 9000 bytes with two echo requests per step.
+func NewProbe(options ...Option) (*Probe, error) {
+	p := &Probe{
+		instanceName: "default",
+		minSize:      1200,
+		maxSize:      9000,
+		count:        2,
+		interval:     200 * time.Millisecond,
+	}
+
+	for _, option := range options {
+		if err := option(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.destinationIP == nil {
+		return nil, fmt.Errorf("probe destination not specified")
+	}
+	if p.compareMTU && p.sourceInterface == "" {
+		return nil, fmt.Errorf("source interface required to compare the path MTU with the interface MTU")
+	}
+	return p, nil
+}
+
+// DestinationIP sets the destination IP address.
+func DestinationIP(ipAddr net.IP) Option {
+	return func(p *Probe) error {
+		p.destinationIP = ipAddr
+		return nil
+	}
+}
+
+// SourceIP specifies the source IP address.
+// Source interface and source IP are mutual exclusive!
+func SourceIP(ipAddress net.IP) Option {
+	return func(p *Probe) error {
+		if p.sourceInterface != "" {
+			return fmt.Errorf("source interface and source IP are mutual exclusive")
+		}
+		p.sourceIP = ipAddress
+		return nil
+	}
+}
+
+// SourceInterface sets the source logical interface name.
+// Source interface and source IP are mutual exclusive!
+func SourceInterface(name string) Option {
+	return func(p *Probe) error {
+		if p.sourceIP != nil {
+			return fmt.Errorf("source interface and source IP are mutual exclusive")
+		}
+		p.sourceInterface = name
+		return nil
+	}
+}
+
+// InstanceName sets the routing instance name.
+func InstanceName(instanceName string) Option {
+	return func(p *Probe) error {
+		if instanceName == "" {
+			return fmt.Errorf("instance name must not be empty")
+		}
+		p.instanceName = instanceName
+		return nil
+	}
+}
+
+// SizeRange sets the range of ping packet sizes to be searched.
+// The sizes must be between 8 and 32768 bytes.
+func SizeRange(min, max int) Option {
+	return func(p *Probe) error {
+		if min < minSize || max > maxSize || min > max {
+			return fmt.Errorf("packet size range must be between %d and %d bytes", minSize, maxSize)
+		}
+		p.minSize = min
+		p.maxSize = max
+		return nil
+	}
+}
+
+// Count sets the number of echo requests sent per packet size.
+// A packet size passes if at least one echo reply is received.
+func Count(count int) Option {
+	return func(p *Probe) error {
+		const maxCount = 100
+		if count <= 0 || count > maxCount {
+			return fmt.Errorf("count value must be between 1 and %d", maxCount)
+		}
+		p.count = count
+		return nil
+	}
+}
+
+// Interval sets the interval between two echo requests.
+func Interval(interval time.Duration) Option {
+	return func(p *Probe) error {
+		if interval < time.Millisecond || interval > 5*time.Second {
+			return fmt.Errorf("interval must be between 1ms and 5s")
+		}
+		p.interval = interval
+		return nil
+	}
+}
+
+// CompareInterfaceMTU compares the discovered path MTU with the MTU of the source interface.
+// The source interface must be set.
+func CompareInterfaceMTU() Option {
+	return func(p *Probe) error {
+		p.compareMTU = true
+		return nil
+	}
+}
+
+func (p *Probe) DestinationIP() net.IP {
+	return p.destinationIP
+}
+
+func (p *Probe) SourceIP() net.IP {
+	return p.sourceIP
+}
+
+func (p *Probe) SourceInterface() string {
+	return p.sourceInterface
+}
+
+func (p *Probe) InstanceName() string {
+	return p.instanceName
+}
+
+func (p *Probe) SizeRange() (int, int) {
+	return p.minSize, p.maxSize
+}
+
+func (p *Probe) Count() int {
+	return p.count
+}
+
+// headerSize returns the size of the IP and ICMP headers added to the ping packet size.
+func (p *Probe) headerSize() int {
+	if p.destinationIP.To4() == nil {
+		return ipv6HeaderSize
+	}
+	return ipv4HeaderSize
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package pmtu
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// This function variable we need to be able to mock the API Service
+var getInterfacesAPIFunc = getInterfacesAPI
+
+type (
+	defaultService struct {
+		client *http.Client
+		ping   ping.Service
+	}
+
+	// Step describes a single packet size tried by the probe.
+	Step struct {
+		// Size holds the ping packet size in bytes.
+		Size int
+		// Sent holds the number of echo requests sent.
+		Sent int
+		// Received holds the number of echo replies received.
+		Received int
+	}
+
+	// Result holds the discovered path MTU.
+	Result struct {
+		// Size holds the largest ping packet size that passed.
+		Size int
+		// PathMTU holds the IP packet size of the largest packet that passed, i.e. the size plus the IP and ICMP
+		// header size. Since the echo requests are sent without don't fragment flag, the path MTU is too large if
+		// the packets are fragmented along the path (see the package documentation).
+		PathMTU int
+		// Limited indicates that the largest searched packet size passed and the path MTU may be larger.
+		// Fragmentation along the path also lets the largest packet size pass.
+		Limited bool
+		// Steps holds all tried packet sizes in the order of execution.
+		Steps []Step
+		// Interface holds the source logical interface the path MTU was compared with, if requested.
+		Interface *InterfaceMTU
+	}
+
+	// InterfaceMTU holds the MTU settings of the source interface.
+	InterfaceMTU struct {
+		// LogicalInterface holds the logical interface name.
+		LogicalInterface string
+		// PhysicalInterface holds the physical interface name.
+		PhysicalInterface string
+		// MTU holds the MTU of the physical interface.
+		MTU int
+		// IPMTU holds the IP MTU of the logical interface for the address family of the destination.
+		IPMTU int
+	}
+
+	// Service discovers path MTUs.
+	Service interface {
+		// Discover binary-searches the largest packet size passing the path to the probe destination.
+		Discover(rbfs.RbfsContext, *Probe) (*Result, error)
+	}
+)
+
+// Mismatch reports whether the discovered path MTU is less than the IP MTU of the source interface,
+// i.e. whether a router along the path does not forward packets the source interface is allowed to send.
+func (r *Result) Mismatch() bool {
+	return r.Interface != nil && r.Interface.IPMTU > 0 && r.PathMTU < r.Interface.IPMTU
+}
+
+func (s *defaultService) Discover(ctx rbfs.RbfsContext, probe *Probe) (*Result, error) {
+	result := &Result{}
+	passes := func(size int) (bool, error) {
+		step, err := s.try(ctx, probe, size)
+		if err != nil {
+			return false, err
+		}
+		result.Steps = append(result.Steps, step)
+		return step.Received > 0, nil
+	}
+
+	low, high := probe.minSize, probe.maxSize
+	ok, err := passes(low)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("destination %s not reachable with packet size %d", probe.destinationIP, low)
+	}
+	if ok, err = passes(high); err != nil {
+		return nil, err
+	}
+	if ok {
+		low, result.Limited = high, true
+	}
+	// Invariant: low passed, high failed.
+	for high-low > 1 {
+		mid := low + (high-low)/2
+		if ok, err = passes(mid); err != nil {
+			return nil, err
+		}
+		if ok {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	result.Size = low
+	result.PathMTU = low + probe.headerSize()
+
+	if probe.compareMTU {
+		if result.Interface, err = s.interfaceMTU(ctx, probe); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// try pings the probe destination with the given packet size.
+func (s *defaultService) try(ctx rbfs.RbfsContext, probe *Probe, size int) (Step, error) {
+	p, err := ping.NewPing(
+		ping.DestinationIP(probe.destinationIP),
+		ping.SourceIP(probe.sourceIP),
+		ping.SourceInterface(probe.sourceInterface),
+		ping.InstanceName(probe.instanceName),
+		ping.Size(size),
+		ping.Count(probe.count),
+		ping.Interval(probe.interval),
+	)
+	if err != nil {
+		return Step{}, err
+	}
+
+	status, err := s.ping.Run(ctx, p)
+	if err != nil {
+		return Step{}, err
+	}
+	step := Step{Size: size, Sent: probe.count}
+	if status.Statistics != nil {
+		step.Sent = status.Statistics.Sent
+		step.Received = status.Statistics.Received
+	}
+	return step, nil
+}
+
+// interfaceMTU reads the MTU settings of the probe source interface.
+func (s *defaultService) interfaceMTU(ctx rbfs.RbfsContext, probe *Probe) (*InterfaceMTU, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return nil, err
+	}
+	api, err := getInterfacesAPIFunc(s.client, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:bodyclose //generated code
+	ifl, _, err := api.GetLogicalInterface(ctx, probe.sourceInterface)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:bodyclose //generated code
+	ifp, _, err := api.GetPhysicalInterface(ctx, ifl.IfpName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	mtu := &InterfaceMTU{
+		LogicalInterface:  probe.sourceInterface,
+		PhysicalInterface: ifl.IfpName,
+		MTU:               ifp.MtuSize,
+		IPMTU:             ifl.Ipv4Mtu,
+	}
+	if probe.destinationIP.To4() == nil {
+		mtu.IPMTU = ifl.Ipv6Mtu
+	}
+	return mtu, nil
+}
+
+func getInterfacesAPI(c *http.Client, endpoint *url.URL) (state.InterfacesApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.InterfacesApi, nil
+}
+
+// NewPMTUService creates a new path MTU defaultService.
+func NewPMTUService(client *http.Client) Service {
+	return &defaultService{client: client, ping: ping.NewPingService(client)}
+}
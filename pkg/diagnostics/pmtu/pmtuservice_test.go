@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package pmtu
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state/statemock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePingService passes all packets not exceeding the path MTU.
+type fakePingService struct {
+	ping.Service
+	pathMTU int
+	sizes   []int
+}
+
+func (s *fakePingService) Run(_ rbfs.RbfsContext, p *ping.Ping) (state.PingStatus, error) {
+	s.sizes = append(s.sizes, p.Size())
+	if s.pathMTU < 0 {
+		return state.PingStatus{}, fmt.Errorf("test")
+	}
+	received := 0
+	if p.Size()+ipv4HeaderSize <= s.pathMTU {
+		received = p.Count()
+	}
+	return state.PingStatus{Statistics: &state.PingStatistics{Sent: p.Count(), Received: received}}, nil
+}
+
+func TestNewProbe(t *testing.T) {
+	_, err := NewProbe()
+	require.EqualError(t, err, "probe destination not specified")
+	_, err = NewProbe(DestinationIP(net.ParseIP("192.0.2.1")), SizeRange(1500, 1000))
+	require.Error(t, err)
+	_, err = NewProbe(DestinationIP(net.ParseIP("192.0.2.1")), CompareInterfaceMTU())
+	require.Error(t, err)
+	_, err = NewProbe(SourceIP(net.ParseIP("192.0.2.2")), SourceInterface("ifl-0/0/1/1"))
+	require.Error(t, err)
+
+	p, err := NewProbe(DestinationIP(net.ParseIP("2001:db8::1")))
+	require.NoError(t, err)
+	require.Equal(t, ipv6HeaderSize, p.headerSize())
+}
+
+func Test_defaultService_Discover(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		pathMTU   int
+		options   []Option
+		setup     func(api *statemock.InterfacesApi)
+		want      *Result
+		wantSteps int
+		wantErr   string
+	}{
+		{
+			name:      "mpls core",
+			pathMTU:   1500,
+			want:      &Result{Size: 1472, PathMTU: 1500},
+			wantSteps: 15,
+		},
+		{
+			name:    "limited by search range",
+			pathMTU: 9216,
+			options: []Option{SizeRange(1000, 2000)},
+			want: &Result{Size: 2000, PathMTU: 2028, Limited: true, Steps: []Step{
+				{Size: 1000, Sent: 2, Received: 2},
+				{Size: 2000, Sent: 2, Received: 2},
+			}},
+		},
+		{
+			name:    "unreachable",
+			pathMTU: 1000,
+			wantErr: "destination 192.0.2.1 not reachable with packet size 1200",
+		},
+		{
+			name:    "ping error",
+			pathMTU: -1,
+			wantErr: "test",
+		},
+		{
+			name:    "compare interface MTU",
+			pathMTU: 1500,
+			options: []Option{SizeRange(1400, 1500), SourceInterface("ifl-0/0/1/1"), CompareInterfaceMTU()},
+			setup: func(api *statemock.InterfacesApi) {
+				api.On("GetLogicalInterface", mock.Anything, "ifl-0/0/1/1").
+					Return(state.LogicalInterface{IfpName: "ifp-0/0/1", Ipv4Mtu: 9000}, nil, nil)
+				api.On("GetPhysicalInterface", mock.Anything, "ifp-0/0/1", (*state.InterfacesApiGetPhysicalInterfaceOpts)(nil)).
+					Return(state.PhysicalInterfaceDetail{MtuSize: 9216}, nil, nil)
+			},
+			want: &Result{Size: 1472, PathMTU: 1500, Interface: &InterfaceMTU{
+				LogicalInterface:  "ifl-0/0/1/1",
+				PhysicalInterface: "ifp-0/0/1",
+				MTU:               9216,
+				IPMTU:             9000,
+			}},
+			wantSteps: 9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interfacesAPI := &statemock.InterfacesApi{}
+			getInterfacesAPIFuncOrig := getInterfacesAPIFunc
+			defer func() { getInterfacesAPIFunc = getInterfacesAPIFuncOrig }()
+			getInterfacesAPIFunc = func(c *http.Client, endpoint *url.URL) (state.InterfacesApi, error) {
+				return interfacesAPI, nil
+			}
+			interfacesAPI.Test(t)
+			if tt.setup != nil {
+				tt.setup(interfacesAPI)
+			}
+			defer interfacesAPI.AssertExpectations(t)
+
+			probe, err := NewProbe(append([]Option{DestinationIP(net.ParseIP("192.0.2.1"))}, tt.options...)...)
+			require.NoError(t, err)
+			pinger := &fakePingService{pathMTU: tt.pathMTU}
+			s := &defaultService{ping: pinger}
+			got, err := s.Discover(ctx, probe)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantSteps > 0 {
+				require.Len(t, got.Steps, tt.wantSteps)
+				got.Steps = nil
+			}
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.want.Interface != nil, got.Mismatch())
+		})
+	}
+}
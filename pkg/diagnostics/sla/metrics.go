@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package sla
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/prometheus"
+)
+
+// metric describes a single metric family of the Prometheus text exposition.
+type metric struct {
+	name  string
+	kind  string
+	help  string
+	value func(Status) (float64, bool)
+}
+
+var metrics = []metric{
+	{
+		name:  "rbfs_sla_probe_runs_total",
+		kind:  "counter",
+		help:  "Number of probe runs.",
+		value: func(s Status) (float64, bool) { return float64(s.Runs), true },
+	},
+	{
+		name:  "rbfs_sla_probe_errors_total",
+		kind:  "counter",
+		help:  "Number of failed probe runs.",
+		value: func(s Status) (float64, bool) { return float64(s.Errors), true },
+	},
+	{
+		name: "rbfs_sla_probe_last_run_timestamp_seconds",
+		kind: "gauge",
+		help: "Start time of the most recent probe run in seconds since the epoch.",
+		value: func(s Status) (float64, bool) {
+			if s.Last == nil {
+				return 0, false
+			}
+			return float64(s.Last.Time.UnixMilli()) / float64(time.Second/time.Millisecond), true
+		},
+	},
+	{
+		name: "rbfs_sla_probe_loss_ratio",
+		kind: "gauge",
+		help: "Loss ratio of the most recent successful probe run.",
+		value: func(s Status) (float64, bool) {
+			return lastValue(s, func(l *Sample) float64 { return l.Statistics.Loss })
+		},
+	},
+	{
+		name: "rbfs_sla_probe_rtt_avg_seconds",
+		kind: "gauge",
+		help: "Average round-trip time of the most recent successful probe run.",
+		value: func(s Status) (float64, bool) {
+			return lastValue(s, func(l *Sample) float64 { return seconds(l.Statistics.Avg) })
+		},
+	},
+	{
+		name: "rbfs_sla_probe_rtt_max_seconds",
+		kind: "gauge",
+		help: "Maximum round-trip time of the most recent successful probe run.",
+		value: func(s Status) (float64, bool) {
+			return lastValue(s, func(l *Sample) float64 { return seconds(l.Statistics.Max) })
+		},
+	},
+	{
+		name: "rbfs_sla_probe_jitter_seconds",
+		kind: "gauge",
+		help: "Jitter of the most recent successful probe run.",
+		value: func(s Status) (float64, bool) {
+			return lastValue(s, func(l *Sample) float64 { return seconds(l.Statistics.Jitter) })
+		},
+	},
+	{
+		name:  "rbfs_sla_window_loss_ratio",
+		kind:  "gauge",
+		help:  "Loss ratio over the probe history.",
+		value: func(s Status) (float64, bool) { return s.Evaluation.Loss, s.Evaluation.Samples > 0 },
+	},
+	{
+		name:  "rbfs_sla_window_rtt_avg_seconds",
+		kind:  "gauge",
+		help:  "Average round-trip time over the probe history.",
+		value: func(s Status) (float64, bool) { return seconds(s.Evaluation.Avg), s.Evaluation.Samples > 0 },
+	},
+	{
+		name:  "rbfs_sla_window_rtt_p95_seconds",
+		kind:  "gauge",
+		help:  "Highest 95th percentile round-trip time over the probe history.",
+		value: func(s Status) (float64, bool) { return seconds(s.Evaluation.P95), s.Evaluation.Samples > 0 },
+	},
+	{
+		name:  "rbfs_sla_window_jitter_seconds",
+		kind:  "gauge",
+		help:  "Average jitter over the probe history.",
+		value: func(s Status) (float64, bool) { return seconds(s.Evaluation.Jitter), s.Evaluation.Samples > 0 },
+	},
+	{
+		name: "rbfs_sla_compliant",
+		kind: "gauge",
+		help: "Whether the probe history complies with the SLA (1) or not (0).",
+		value: func(s Status) (float64, bool) {
+			if !s.Evaluation.Compliant() {
+				return 0, true
+			}
+			return 1, s.Evaluation.Samples > 0
+		},
+	},
+}
+
+// WriteMetrics writes the state of all probes in the Prometheus text exposition format.
+// Metrics without a value, e.g. the latency of a probe that never succeeded, are omitted.
+func (s *Scheduler) WriteMetrics(w io.Writer) error {
+	statuses := s.Status()
+	out := bufio.NewWriter(w)
+	for _, m := range metrics {
+		prometheus.WriteHeader(out, m.name, m.kind, m.help)
+		for _, status := range statuses {
+			value, ok := m.value(status)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(out, "%s{%s} %s\n", m.name, labels(status.Probe), prometheus.FormatValue(value))
+		}
+	}
+	return out.Flush()
+}
+
+// Handler returns an HTTP handler serving the probe metrics.
+func (s *Scheduler) Handler() http.Handler {
+	return prometheus.Handler(s.WriteMetrics)
+}
+
+// lastValue returns the value of the most recent sample, if the sample is successful.
+func lastValue(s Status, value func(*Sample) float64) (float64, bool) {
+	if s.Last == nil || s.Last.Err != nil {
+		return 0, false
+	}
+	return value(s.Last), true
+}
+
+func seconds(d time.Duration) float64 {
+	return d.Seconds()
+}
+
+// labels returns the label set of the given probe.
+func labels(p Probe) string {
+	instance := p.Instance
+	if instance == "" {
+		instance = "default"
+	}
+	return fmt.Sprintf(`probe="%s",element="%s",destination="%s",instance="%s",tos="%d"`,
+		prometheus.EscapeLabel(p.Name), prometheus.EscapeLabel(p.Element), prometheus.EscapeLabel(p.Destination),
+		prometheus.EscapeLabel(instance), p.TOS)
+}
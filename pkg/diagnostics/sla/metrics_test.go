@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package sla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_WriteMetrics(t *testing.T) {
+	service := &fakePingService{outputs: [][]string{{
+		"64 bytes from 192.0.2.1: icmp_seq=1 ttl=64 time=1.00 ms",
+		"64 bytes from 192.0.2.1: icmp_seq=2 ttl=64 time=3.00 ms",
+	}}}
+	s, err := NewScheduler(service, &Config{Probes: []Probe{
+		{Name: `leaf01 "voice"`, Element: "leaf01", Destination: "192.0.2.1", TOS: 184},
+		{Name: "idle", Element: "leaf02", Destination: "192.0.2.2"},
+	}})
+	require.NoError(t, err)
+	s.now = func() time.Time { return time.Unix(1600000000, 500*int64(time.Millisecond)) }
+	s.run(testContext(t), 0)
+
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	labels := `{probe="leaf01 \"voice\"",element="leaf01",destination="192.0.2.1",instance="default",tos="184"}`
+	idle := `{probe="idle",element="leaf02",destination="192.0.2.2",instance="default",tos="0"}`
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"# HELP rbfs_sla_probe_runs_total Number of probe runs.\n# TYPE rbfs_sla_probe_runs_total counter\n",
+		"rbfs_sla_probe_runs_total" + labels + " 1\n",
+		"rbfs_sla_probe_runs_total" + idle + " 0\n",
+		"rbfs_sla_probe_errors_total" + labels + " 0\n",
+		"rbfs_sla_probe_last_run_timestamp_seconds" + labels + " 1.6000000005e+09\n",
+		"rbfs_sla_probe_loss_ratio" + labels + " 0\n",
+		"rbfs_sla_probe_rtt_avg_seconds" + labels + " 0.002\n",
+		"rbfs_sla_probe_rtt_max_seconds" + labels + " 0.003\n",
+		"rbfs_sla_probe_jitter_seconds" + labels + " 0.002\n",
+		"rbfs_sla_window_rtt_p95_seconds" + labels + " 0.003\n",
+		"rbfs_sla_compliant" + labels + " 1\n",
+	} {
+		require.Contains(t, body, line)
+	}
+	require.NotContains(t, body, "rbfs_sla_compliant"+idle)
+	require.NotContains(t, body, "rbfs_sla_probe_loss_ratio"+idle)
+}
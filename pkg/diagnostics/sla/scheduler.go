@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package sla
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+)
+
+const defaultHistory = 60
+
+type (
+	// Sample holds the outcome of a single probe run.
+	Sample struct {
+		// Time holds the start time of the run.
+		Time time.Time
+		// Statistics holds the statistics of a successful run.
+		Statistics ping.Statistics
+		// Err holds the error of a failed run.
+		Err error
+	}
+
+	// Status holds the current state of a probe.
+	Status struct {
+		// Probe holds the probe configuration.
+		Probe Probe
+		// Runs holds the number of runs since the scheduler was started.
+		Runs uint64
+		// Errors holds the number of failed runs since the scheduler was started.
+		Errors uint64
+		// Last holds the most recent sample, if any.
+		Last *Sample
+		// Evaluation holds the SLA evaluation of the probe history.
+		Evaluation Evaluation
+	}
+
+	// Scheduler runs the configured probes at their intervals.
+	Scheduler struct {
+		service ping.Service
+		probes  []Probe
+		pings   []*ping.Ping
+		history int
+		now     func() time.Time
+
+		mu      sync.RWMutex
+		results []*history
+	}
+
+	// history holds the rolling history of a probe.
+	history struct {
+		samples []Sample
+		next    int
+		runs    uint64
+		errors  uint64
+	}
+)
+
+// NewScheduler creates a new scheduler running the configured probes using the given ping service.
+func NewScheduler(service ping.Service, config *Config) (*Scheduler, error) {
+	s := &Scheduler{
+		service: service,
+		probes:  config.Probes,
+		history: config.History,
+		now:     time.Now,
+	}
+	if s.history <= 0 {
+		s.history = defaultHistory
+	}
+
+	names := map[string]bool{}
+	for i := range s.probes {
+		probe := &s.probes[i]
+		if probe.Name == "" || probe.Element == "" {
+			return nil, fmt.Errorf("probe %d: name and element must be specified", i)
+		}
+		if names[probe.Name] {
+			return nil, fmt.Errorf("probe %s: duplicate probe name", probe.Name)
+		}
+		names[probe.Name] = true
+
+		p, err := probe.ping()
+		if err != nil {
+			return nil, fmt.Errorf("probe %s: %w", probe.Name, err)
+		}
+		s.pings = append(s.pings, p)
+		s.results = append(s.results, &history{samples: make([]Sample, 0, s.history)})
+	}
+	return s, nil
+}
+
+// Run runs every probe immediately and then at its interval until the context is cancelled.
+// Each probe runs on its element, which is addressed through the given CTRLD context.
+func (s *Scheduler) Run(ctx rbfs.RbfsContext) {
+	var wg sync.WaitGroup
+	for i := range s.probes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ticker := time.NewTicker(s.probes[i].every())
+			defer ticker.Stop()
+			for {
+				s.run(ctx, i)
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// run executes the probe with the given index once and records the sample.
+func (s *Scheduler) run(ctx rbfs.RbfsContext, i int) {
+	sample := Sample{Time: s.now()}
+	status, err := s.service.Run(rbfs.WithElement(ctx, s.probes[i].Element), s.pings[i])
+	if err != nil {
+		if ctx.Err() != nil {
+			// The scheduler is stopped, the run was interrupted.
+			return
+		}
+		sample.Err = err
+	} else {
+		sample.Statistics = ping.NewResult(status).Statistics
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[i].add(sample, s.history)
+}
+
+func (h *history) add(sample Sample, size int) {
+	h.runs++
+	if sample.Err != nil {
+		h.errors++
+	}
+	if len(h.samples) < size {
+		h.samples = append(h.samples, sample)
+		return
+	}
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % size
+}
+
+// ordered returns a copy of the samples from the oldest to the most recent sample.
+func (h *history) ordered() []Sample {
+	samples := make([]Sample, 0, len(h.samples))
+	samples = append(samples, h.samples[h.next:]...)
+	return append(samples, h.samples[:h.next]...)
+}
+
+// History returns the samples of the probe with the given name from the oldest to the most recent sample.
+func (s *Scheduler) History(name string) []Sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i, probe := range s.probes {
+		if probe.Name == name {
+			return s.results[i].ordered()
+		}
+	}
+	return nil
+}
+
+// Status returns the current state of all probes in the configured order.
+func (s *Scheduler) Status() []Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	statuses := make([]Status, 0, len(s.probes))
+	for i, probe := range s.probes {
+		h := s.results[i]
+		samples := h.ordered()
+		status := Status{
+			Probe:      probe,
+			Runs:       h.runs,
+			Errors:     h.errors,
+			Evaluation: probe.SLA.Evaluate(samples),
+		}
+		if len(samples) > 0 {
+			status.Last = &samples[len(samples)-1]
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
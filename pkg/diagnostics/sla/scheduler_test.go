@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package sla
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePingService returns the configured outputs round-robin and records the element of every ping.
+type fakePingService struct {
+	ping.Service
+	mu       sync.Mutex
+	elements []string
+	outputs  [][]string
+	err      error
+}
+
+func (s *fakePingService) Run(ctx rbfs.RbfsContext, p *ping.Ping) (state.PingStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.elements = append(s.elements, rbfs.ElementName(ctx))
+	if s.err != nil {
+		return state.PingStatus{}, s.err
+	}
+	output := s.outputs[(len(s.elements)-1)%len(s.outputs)]
+	return state.PingStatus{Statistics: &state.PingStatistics{Sent: len(output)}, Output: output}, nil
+}
+
+func testContext(t *testing.T) rbfs.RbfsContext {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "ctrld")
+	require.NoError(t, err)
+	return ctx
+}
+
+func TestNewScheduler(t *testing.T) {
+	_, err := NewScheduler(nil, &Config{Probes: []Probe{{Name: "a"}}})
+	require.EqualError(t, err, "probe 0: name and element must be specified")
+	_, err = NewScheduler(nil, &Config{Probes: []Probe{
+		{Name: "a", Element: "leaf01", Destination: "192.0.2.1"},
+		{Name: "a", Element: "leaf02", Destination: "192.0.2.1"},
+	}})
+	require.EqualError(t, err, "probe a: duplicate probe name")
+	_, err = NewScheduler(nil, &Config{Probes: []Probe{{Name: "a", Element: "leaf01", Destination: "192.0.2.1", TOS: 256}}})
+	require.EqualError(t, err, "probe a: packet TOS must be between 0 and 255")
+}
+
+func TestScheduler_History(t *testing.T) {
+	service := &fakePingService{outputs: [][]string{
+		{"64 bytes from 192.0.2.1: icmp_seq=1 ttl=64 time=1.00 ms"},
+		{"64 bytes from 192.0.2.1: icmp_seq=1 ttl=64 time=2.00 ms"},
+		{"64 bytes from 192.0.2.1: icmp_seq=1 ttl=64 time=3.00 ms"},
+	}}
+	s, err := NewScheduler(service, &Config{History: 2, Probes: []Probe{
+		{Name: "a", Element: "leaf01", Destination: "192.0.2.1", SLA: SLA{MaxAvg: 2 * time.Millisecond}},
+	}})
+	require.NoError(t, err)
+	start := time.Unix(1600000000, 0)
+	now := start
+	s.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+
+	ctx := testContext(t)
+	for i := 0; i < 3; i++ {
+		s.run(ctx, 0)
+	}
+	require.Equal(t, []string{"leaf01", "leaf01", "leaf01"}, service.elements)
+
+	history := s.History("a")
+	require.Len(t, history, 2)
+	require.Equal(t, start.Add(2*time.Minute), history[0].Time)
+	require.Equal(t, 2*time.Millisecond, history[0].Statistics.Avg)
+	require.Equal(t, 3*time.Millisecond, history[1].Statistics.Avg)
+	require.Nil(t, s.History("unknown"))
+
+	status := s.Status()[0]
+	require.Equal(t, uint64(3), status.Runs)
+	require.Equal(t, 3*time.Millisecond, status.Last.Statistics.Avg)
+	require.Equal(t, 2500*time.Microsecond, status.Evaluation.Avg)
+	require.False(t, status.Evaluation.Compliant())
+}
+
+func TestScheduler_Run(t *testing.T) {
+	service := &fakePingService{err: fmt.Errorf("test")}
+	s, err := NewScheduler(service, &Config{Probes: []Probe{
+		{Name: "a", Element: "leaf01", Destination: "192.0.2.1", Every: 10 * time.Millisecond},
+		{Name: "b", Element: "leaf02", Destination: "192.0.2.2", Every: 10 * time.Millisecond},
+	}})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(testContext(t), 55*time.Millisecond)
+	defer cancel()
+	s.Run(rbfs.MustRbfsContext(ctx))
+
+	for _, status := range s.Status() {
+		require.GreaterOrEqual(t, status.Runs, uint64(2))
+		require.Equal(t, status.Runs, status.Errors)
+		require.EqualError(t, status.Last.Err, "test")
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package sla continuously monitors latency and loss from the point of view of the elements by running configured
+// pings at regular intervals, keeping a rolling history of the results and evaluating loss and latency SLAs.
+package sla
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Config holds the SLA monitoring configuration.
+	Config struct {
+		// History holds the number of samples kept per probe. The default history size is 60.
+		History int `yaml:"history"`
+		// Probes holds the configured probes.
+		Probes []Probe `yaml:"probes"`
+	}
+
+	// Probe describes a ping executed periodically on an element.
+	Probe struct {
+		// Name holds the unique probe name.
+		Name string `yaml:"name"`
+		// Element holds the name of the element executing the ping.
+		Element string `yaml:"element"`
+		// Destination holds the destination IP address or host name.
+		Destination string `yaml:"destination"`
+		// IPv6 resolves a destination host name to an IPv6 address.
+		IPv6 bool `yaml:"ipv6"`
+		// SourceIP holds the optional source IP address.
+		SourceIP string `yaml:"source-ip"`
+		// SourceInterface holds the optional source logical interface.
+		SourceInterface string `yaml:"source-interface"`
+		// Instance holds the routing instance name. The default instance is used if no instance is set.
+		Instance string `yaml:"instance"`
+		// Count holds the number of echo requests per run.
+		Count int `yaml:"count"`
+		// PingInterval holds the interval between two echo requests.
+		PingInterval time.Duration `yaml:"ping-interval"`
+		// Size holds the ping packet size.
+		Size int `yaml:"size"`
+		// TOS holds the IP type of service byte.
+		TOS int `yaml:"tos"`
+		// Every holds the interval between two runs. The default interval is one minute.
+		Every time.Duration `yaml:"every"`
+		// SLA holds the service level agreement evaluated over the probe history.
+		SLA SLA `yaml:"sla"`
+	}
+
+	// SLA defines the loss and latency limits of a probe. Zero durations disable the respective latency check,
+	// whereas a zero loss limit tolerates no loss at all.
+	SLA struct {
+		// MaxLoss holds the maximum tolerated loss ratio between 0 and 1.
+		MaxLoss float64 `yaml:"max-loss"`
+		// MaxAvg holds the maximum tolerated average round-trip time.
+		MaxAvg time.Duration `yaml:"max-avg"`
+		// MaxP95 holds the maximum tolerated 95th percentile of the round-trip times.
+		MaxP95 time.Duration `yaml:"max-p95"`
+		// MaxJitter holds the maximum tolerated jitter.
+		MaxJitter time.Duration `yaml:"max-jitter"`
+	}
+
+	// Evaluation holds the SLA evaluation of a probe history.
+	Evaluation struct {
+		// Samples holds the number of successful samples evaluated.
+		Samples int
+		// Loss holds the loss ratio over all evaluated samples.
+		Loss float64
+		// Avg holds the average round-trip time over all evaluated samples.
+		Avg time.Duration
+		// P95 holds the highest 95th percentile of all evaluated samples.
+		P95 time.Duration
+		// Jitter holds the average jitter of all evaluated samples.
+		Jitter time.Duration
+		// Violations holds a description of every violated SLA limit.
+		Violations []string
+	}
+)
+
+// LoadConfig reads the SLA monitoring configuration from the given YAML file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("cannot parse SLA configuration %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// every returns the interval between two probe runs.
+func (p *Probe) every() time.Duration {
+	if p.Every <= 0 {
+		return time.Minute
+	}
+	return p.Every
+}
+
+// ping creates the ping command of the probe.
+func (p *Probe) ping() (*ping.Ping, error) {
+	var options []ping.Option
+	if ip := net.ParseIP(p.Destination); ip != nil {
+		options = append(options, ping.DestinationIP(ip))
+	} else if p.IPv6 {
+		options = append(options, ping.DestinationHostNameAAAA(p.Destination))
+	} else if p.Destination != "" {
+		options = append(options, ping.DestinationHostNameA(p.Destination))
+	}
+	if p.SourceIP != "" {
+		ip := net.ParseIP(p.SourceIP)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid source IP address %q", p.SourceIP)
+		}
+		options = append(options, ping.SourceIP(ip))
+	}
+	options = append(options, ping.SourceInterface(p.SourceInterface))
+	if p.Instance != "" {
+		options = append(options, ping.InstanceName(p.Instance))
+	}
+	if p.Count != 0 {
+		options = append(options, ping.Count(p.Count))
+	}
+	if p.PingInterval != 0 {
+		options = append(options, ping.Interval(p.PingInterval))
+	}
+	if p.Size != 0 {
+		options = append(options, ping.Size(p.Size))
+	}
+	options = append(options, ping.TOS(p.TOS))
+	return ping.NewPing(options...)
+}
+
+// Evaluate evaluates the SLA over the given samples. Failed samples are ignored.
+func (s SLA) Evaluate(samples []Sample) Evaluation {
+	var e Evaluation
+	var sent, received int
+	var rtt, jitter time.Duration
+	for _, sample := range samples {
+		if sample.Err != nil {
+			continue
+		}
+		e.Samples++
+		statistics := sample.Statistics
+		sent += statistics.Sent
+		received += statistics.Received
+		rtt += statistics.Avg * time.Duration(statistics.Received)
+		jitter += statistics.Jitter
+		if statistics.P95 > e.P95 {
+			e.P95 = statistics.P95
+		}
+	}
+	if e.Samples == 0 {
+		return e
+	}
+	if sent > 0 {
+		e.Loss = float64(sent-received) / float64(sent)
+	}
+	if received > 0 {
+		e.Avg = rtt / time.Duration(received)
+	}
+	e.Jitter = jitter / time.Duration(e.Samples)
+
+	if e.Loss > s.MaxLoss {
+		e.Violations = append(e.Violations, fmt.Sprintf("loss %.4g exceeds %.4g", e.Loss, s.MaxLoss))
+	}
+	if s.MaxAvg > 0 && e.Avg > s.MaxAvg {
+		e.Violations = append(e.Violations, fmt.Sprintf("average round-trip time %s exceeds %s", e.Avg, s.MaxAvg))
+	}
+	if s.MaxP95 > 0 && e.P95 > s.MaxP95 {
+		e.Violations = append(e.Violations, fmt.Sprintf("95th percentile round-trip time %s exceeds %s", e.P95, s.MaxP95))
+	}
+	if s.MaxJitter > 0 && e.Jitter > s.MaxJitter {
+		e.Violations = append(e.Violations, fmt.Sprintf("jitter %s exceeds %s", e.Jitter, s.MaxJitter))
+	}
+	return e
+}
+
+// Compliant reports whether no SLA limit is violated.
+func (e Evaluation) Compliant() bool {
+	return len(e.Violations) == 0
+}
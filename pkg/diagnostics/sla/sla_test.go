@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package sla
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sla.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+history: 10
+probes:
+  - name: leaf01-voice
+    element: leaf01
+    destination: 198.51.100.1
+    instance: voice
+    count: 10
+    ping-interval: 100ms
+    tos: 184
+    every: 30s
+    sla:
+      max-loss: 0.01
+      max-avg: 20ms
+`), 0o600))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, &Config{
+		History: 10,
+		Probes: []Probe{{
+			Name:         "leaf01-voice",
+			Element:      "leaf01",
+			Destination:  "198.51.100.1",
+			Instance:     "voice",
+			Count:        10,
+			PingInterval: 100 * time.Millisecond,
+			TOS:          184,
+			Every:        30 * time.Second,
+			SLA:          SLA{MaxLoss: 0.01, MaxAvg: 20 * time.Millisecond},
+		}},
+	}, config)
+
+	p, err := config.Probes[0].ping()
+	require.NoError(t, err)
+	require.Equal(t, 184, p.TOS())
+	require.Equal(t, 10, p.Count())
+}
+
+func TestSLA_Evaluate(t *testing.T) {
+	ms := time.Millisecond
+	samples := []Sample{
+		{Statistics: ping.Statistics{Sent: 10, Received: 10, Avg: 10 * ms, P95: 12 * ms, Jitter: 2 * ms}},
+		{Err: fmt.Errorf("test")},
+		{Statistics: ping.Statistics{Sent: 10, Received: 5, Avg: 40 * ms, P95: 50 * ms, Jitter: 4 * ms}},
+	}
+
+	e := SLA{MaxLoss: 0.5}.Evaluate(samples)
+	require.True(t, e.Compliant())
+	require.Equal(t, 2, e.Samples)
+	require.InDelta(t, 0.25, e.Loss, 1e-9)
+	require.Equal(t, 20*ms, e.Avg)
+	require.Equal(t, 50*ms, e.P95)
+	require.Equal(t, 3*ms, e.Jitter)
+
+	e = SLA{MaxLoss: 0.1, MaxAvg: 15 * ms, MaxP95: 50 * ms, MaxJitter: 2 * ms}.Evaluate(samples)
+	require.Equal(t, []string{
+		"loss 0.25 exceeds 0.1",
+		"average round-trip time 20ms exceeds 15ms",
+		"jitter 3ms exceeds 2ms",
+	}, e.Violations)
+
+	require.Equal(t, Evaluation{}, SLA{}.Evaluate(nil))
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package subscriber checks whether a BNG reaches a subscriber by pinging the addresses assigned to the subscriber
+// session from the routing instance of the session.
+package subscriber
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+)
+
+type (
+	// Check contains all arguments to check the connectivity of a subscriber.
+	Check struct {
+		subscriberID int
+		userName     string
+		clientMAC    net.HardwareAddr
+		traceroute   bool
+		pingOptions  []ping.Option
+	}
+
+	// Option applies a check argument.
+	Option func(*Check) error
+)
+
+// NewCheck creates a new subscriber connectivity check.
+// Exactly one of the subscriber ID, the username or the client MAC address must be specified.
+func NewCheck(options ...Option) (*Check, error) {
+	c := &Check{}
+	for _, option := range options {
+		if err := option(c); err != nil {
+			return nil, err
+		}
+	}
+
+	selectors := 0
+	if c.subscriberID != 0 {
+		selectors++
+	}
+	if c.userName != "" {
+		selectors++
+	}
+	if c.clientMAC != nil {
+		selectors++
+	}
+	if selectors != 1 {
+		return nil, fmt.Errorf("exactly one of subscriber ID, username or client MAC address must be specified")
+	}
+	return c, nil
+}
+
+// SubscriberID selects the subscriber session by its subscriber ID.
+func SubscriberID(id int) Option {
+	return func(c *Check) error {
+		if id <= 0 {
+			return fmt.Errorf("subscriber ID must be greater than 0")
+		}
+		c.subscriberID = id
+		return nil
+	}
+}
+
+// UserName selects the subscriber session by the subscriber's username.
+func UserName(name string) Option {
+	return func(c *Check) error {
+		c.userName = name
+		return nil
+	}
+}
+
+// ClientMAC selects the subscriber session by the client MAC address.
+func ClientMAC(mac string) Option {
+	return func(c *Check) error {
+		addr, err := net.ParseMAC(mac)
+		if err != nil {
+			return fmt.Errorf("invalid client MAC address %q", mac)
+		}
+		c.clientMAC = addr
+		return nil
+	}
+}
+
+// WithTraceroute traces the route to every subscriber address in addition to the ping.
+func WithTraceroute() Option {
+	return func(c *Check) error {
+		c.traceroute = true
+		return nil
+	}
+}
+
+// PingOptions sets additional options applied to every ping, e.g. the number of echo requests.
+// Destination and routing instance are derived from the subscriber session.
+func PingOptions(options ...ping.Option) Option {
+	return func(c *Check) error {
+		c.pingOptions = append(c.pingOptions, options...)
+		return nil
+	}
+}
+
+func (c *Check) SubscriberID() int {
+	return c.subscriberID
+}
+
+func (c *Check) UserName() string {
+	return c.userName
+}
+
+func (c *Check) ClientMAC() net.HardwareAddr {
+	return c.clientMAC
+}
@@ -0,0 +1,256 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package subscriber
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/traceroute"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// This function variable we need to be able to mock the API Service
+var getSubscriberAPIFunc = getSubscriberAPI
+
+const (
+	// IPv4 identifies the IPv4 address family.
+	IPv4 = Family("ipv4")
+	// IPv6 identifies the IPv6 address family.
+	IPv6 = Family("ipv6")
+)
+
+type (
+	defaultService struct {
+		client     *http.Client
+		ping       ping.Service
+		traceroute traceroute.Service
+	}
+
+	// Family identifies an address family.
+	Family string
+
+	// Reachability describes the reachability of a single subscriber address.
+	Reachability struct {
+		// Family holds the address family.
+		Family Family
+		// Address holds the pinged subscriber address, if any.
+		Address net.IP
+		// Instance holds the routing instance the subscriber address was pinged from.
+		Instance string
+		// Skipped holds the reason why the address was not pinged, if so.
+		Skipped string
+		// Ping holds the ping status.
+		Ping *state.PingStatus
+		// Statistics holds the statistics computed from the ping output.
+		Statistics *ping.Statistics
+		// Traceroute holds the traceroute result, if requested.
+		Traceroute *state.Traceroute
+		// Err holds the error of a failed ping or traceroute.
+		Err error
+	}
+
+	// Report holds the outcome of a subscriber connectivity check.
+	Report struct {
+		// Session holds the subscriber session details.
+		Session state.SubscriberSession
+		// State holds the subscriber FSM state.
+		State string
+		// QoSProfile holds the name of the QoS profile applied to the subscriber, if any.
+		QoSProfile string
+		// IPv4 holds the reachability of the framed IPv4 address.
+		IPv4 Reachability
+		// IPv6 holds the reachability of the framed IPv6 address.
+		IPv6 Reachability
+	}
+
+	// Service checks the connectivity of subscribers.
+	Service interface {
+		// Check looks up the subscriber session and pings the framed subscriber addresses.
+		// Failed pings are reported per address family; an error is only returned if the session lookup fails.
+		Check(rbfs.RbfsContext, *Check) (*Report, error)
+	}
+)
+
+// Reachable reports whether at least one echo reply was received from any subscriber address.
+func (r *Report) Reachable() bool {
+	return r.IPv4.reachable() || r.IPv6.reachable()
+}
+
+func (r *Reachability) reachable() bool {
+	return r.Statistics != nil && r.Statistics.Received > 0
+}
+
+func (s *defaultService) Check(ctx rbfs.RbfsContext, check *Check) (*Report, error) {
+	api, err := s.getSubscriberAPI(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session, err := lookup(ctx, api, check)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Session: session, State: session.SubscriberState}
+	if session.Qos != nil {
+		report.QoSProfile = session.Qos.QosProfileName
+	}
+	report.IPv4 = s.reach(ctx, check, ipv4Target(session))
+	report.IPv6 = s.reach(ctx, check, ipv6Target(session))
+	return report, nil
+}
+
+// lookup returns the subscriber session selected by the given check.
+func lookup(ctx rbfs.RbfsContext, api state.SubscriberApi, check *Check) (state.SubscriberSession, error) {
+	id := check.subscriberID
+	if id == 0 {
+		opts := &state.SubscriberApiGetSubscribersOpts{SubscriberUserName: rbfs.OptionalString(check.userName)}
+		//nolint:bodyclose //generated code
+		refs, _, err := api.GetSubscribers(ctx, opts)
+		if err != nil {
+			return state.SubscriberSession{}, err
+		}
+
+		var matches []int
+		for _, ref := range refs {
+			if check.clientMAC != nil && !sameMAC(check.clientMAC, ref.ClientMac) {
+				continue
+			}
+			if check.userName != "" && ref.SubscriberUserName != check.userName {
+				continue
+			}
+			matches = append(matches, ref.SubscriberId)
+		}
+		switch len(matches) {
+		case 0:
+			return state.SubscriberSession{}, fmt.Errorf("subscriber session not found")
+		case 1:
+			id = matches[0]
+		default:
+			return state.SubscriberSession{}, fmt.Errorf("subscriber session not unique, matching subscriber IDs: %v", matches)
+		}
+	}
+
+	//nolint:bodyclose //generated code
+	session, _, err := api.GetSubscriber(ctx, id)
+	return session, err
+}
+
+// ipv4Target returns the reachability target of the framed IPv4 address.
+func ipv4Target(session state.SubscriberSession) Reachability {
+	target := Reachability{Family: IPv4}
+	if session.Ipv4 == nil || session.Ipv4.Framed == nil || session.Ipv4.Framed.Address == "" {
+		target.Skipped = "no framed IPv4 address assigned"
+		return target
+	}
+	target.Instance = session.Ipv4.InstanceName
+	if target.Address = net.ParseIP(session.Ipv4.Framed.Address); target.Address == nil {
+		target.Skipped = fmt.Sprintf("invalid framed IPv4 address %q", session.Ipv4.Framed.Address)
+	}
+	return target
+}
+
+// ipv6Target returns the reachability target of the framed IPv6 prefix.
+// Only host prefixes can be pinged, since the address the subscriber assigned from a shorter prefix is unknown.
+func ipv6Target(session state.SubscriberSession) Reachability {
+	target := Reachability{Family: IPv6}
+	if session.Ipv6 == nil || session.Ipv6.Framed == nil || session.Ipv6.Framed.Prefix == "" {
+		target.Skipped = "no framed IPv6 prefix assigned"
+		return target
+	}
+	target.Instance = session.Ipv6.InstanceName
+	prefix := session.Ipv6.Framed.Prefix
+	ip, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		if ip = net.ParseIP(prefix); ip == nil {
+			target.Skipped = fmt.Sprintf("invalid framed IPv6 prefix %q", prefix)
+			return target
+		}
+	} else if ones, bits := network.Mask.Size(); ones != bits {
+		target.Skipped = fmt.Sprintf("subscriber address within framed IPv6 prefix %s unknown", prefix)
+		return target
+	}
+	target.Address = ip
+	return target
+}
+
+// reach pings and optionally traces the route to the given target.
+func (s *defaultService) reach(ctx rbfs.RbfsContext, check *Check, target Reachability) Reachability {
+	if target.Skipped != "" {
+		return target
+	}
+	if target.Instance == "" {
+		target.Instance = "default"
+	}
+
+	p, err := ping.NewPing(append([]ping.Option{
+		ping.DestinationIP(target.Address),
+		ping.InstanceName(target.Instance),
+	}, check.pingOptions...)...)
+	if err != nil {
+		target.Err = err
+		return target
+	}
+	status, err := s.ping.Run(ctx, p)
+	if err != nil {
+		target.Err = err
+		return target
+	}
+	target.Ping = &status
+	target.Statistics = &ping.NewResult(status).Statistics
+
+	if check.traceroute {
+		t, err := traceroute.NewTraceroute(
+			traceroute.DestinationIP(target.Address),
+			traceroute.InstanceName(target.Instance),
+		)
+		if err != nil {
+			target.Err = err
+			return target
+		}
+		result, err := s.traceroute.Run(ctx, t)
+		if err != nil {
+			target.Err = err
+			return target
+		}
+		target.Traceroute = &result
+	}
+	return target
+}
+
+// sameMAC reports whether the given MAC address string denotes the given MAC address.
+func sameMAC(mac net.HardwareAddr, s string) bool {
+	other, err := net.ParseMAC(s)
+	return err == nil && bytes.Equal(mac, other)
+}
+
+func (s *defaultService) getSubscriberAPI(ctx rbfs.RbfsContext) (state.SubscriberApi, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return getSubscriberAPIFunc(s.client, endpoint)
+}
+
+func getSubscriberAPI(c *http.Client, endpoint *url.URL) (state.SubscriberApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.SubscriberApi, nil
+}
+
+// NewSubscriberService creates a new subscriber connectivity defaultService.
+func NewSubscriberService(client *http.Client) Service {
+	return &defaultService{
+		client:     client,
+		ping:       ping.NewPingService(client),
+		traceroute: traceroute.NewTracerouteService(client),
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package subscriber
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/ping"
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/traceroute"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state/statemock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// fakePingService replies to all pinged addresses except unreachable ones.
+	fakePingService struct {
+		ping.Service
+		unreachable map[string]bool
+		instances   map[string]string
+	}
+
+	fakeTracerouteService struct {
+		traceroute.Service
+	}
+)
+
+func (s *fakePingService) Run(_ rbfs.RbfsContext, p *ping.Ping) (state.PingStatus, error) {
+	s.instances[p.DestinationIP().String()] = p.InstanceName()
+	if s.unreachable[p.DestinationIP().String()] {
+		return state.PingStatus{}, fmt.Errorf("network unreachable")
+	}
+	return state.PingStatus{
+		Statistics: &state.PingStatistics{Sent: 1, Received: 1},
+		Output:     []string{fmt.Sprintf("64 bytes from %s: icmp_seq=1 ttl=64 time=1.00 ms", p.DestinationIP())},
+	}, nil
+}
+
+func (s *fakeTracerouteService) Run(_ rbfs.RbfsContext, t *traceroute.Traceroute) (state.Traceroute, error) {
+	return state.Traceroute{Hops: []state.TracerouteHop{{Seq: 1, Hop: t.DestinationIP().String()}}}, nil
+}
+
+func TestNewCheck(t *testing.T) {
+	_, err := NewCheck()
+	require.Error(t, err)
+	_, err = NewCheck(SubscriberID(1), UserName("user@example.net"))
+	require.Error(t, err)
+	_, err = NewCheck(ClientMAC("invalid"))
+	require.EqualError(t, err, `invalid client MAC address "invalid"`)
+
+	c, err := NewCheck(ClientMAC("00:04:0E:00:00:01"))
+	require.NoError(t, err)
+	require.Equal(t, "00:04:0e:00:00:01", c.ClientMAC().String())
+}
+
+func Test_defaultService_Check(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "bng")
+	require.NoError(t, err)
+
+	session := state.SubscriberSession{
+		SubscriberId:    72339069014638600,
+		SubscriberState: "ESTABLISHED",
+		Ipv4: &state.SubscriberSessionIpv4{
+			InstanceName: "internet",
+			Framed:       &state.SubscriberSessionIpv4Framed{Address: "100.64.0.10"},
+		},
+		Ipv6: &state.SubscriberSessionIpv6{
+			InstanceName: "internet",
+			Framed:       &state.SubscriberSessionIpv6Framed{Prefix: "2001:db8:0:1::/64"},
+		},
+		Qos: &state.SubscriberQosProfile{QosProfileName: "residential-100m"},
+	}
+	refs := []state.SubscriberSessionRef{
+		{SubscriberId: 1, SubscriberUserName: "other@example.net", ClientMac: "00:04:0e:00:00:02"},
+		{SubscriberId: 72339069014638600, SubscriberUserName: "user@example.net", ClientMac: "00:04:0e:00:00:01"},
+	}
+
+	tests := []struct {
+		name        string
+		options     []Option
+		unreachable map[string]bool
+		setup       func(api *statemock.SubscriberApi)
+		want        func(t *testing.T, report *Report, instances map[string]string)
+		wantErr     string
+	}{
+		{
+			name:    "by client MAC with traceroute",
+			options: []Option{ClientMAC("00-04-0E-00-00-01"), WithTraceroute()},
+			setup: func(api *statemock.SubscriberApi) {
+				api.On("GetSubscribers", mock.Anything, &state.SubscriberApiGetSubscribersOpts{SubscriberUserName: rbfs.OptionalString("")}).
+					Return(refs, nil, nil)
+				api.On("GetSubscriber", mock.Anything, 72339069014638600).Return(session, nil, nil)
+			},
+			want: func(t *testing.T, report *Report, instances map[string]string) {
+				require.Equal(t, "ESTABLISHED", report.State)
+				require.Equal(t, "residential-100m", report.QoSProfile)
+				require.True(t, report.Reachable())
+				require.Equal(t, net.ParseIP("100.64.0.10"), report.IPv4.Address)
+				require.Equal(t, "internet", report.IPv4.Instance)
+				require.Equal(t, map[string]string{"100.64.0.10": "internet"}, instances)
+				require.Equal(t, 1, report.IPv4.Statistics.Received)
+				require.Equal(t, "100.64.0.10", report.IPv4.Traceroute.Hops[0].Hop)
+				require.Equal(t, "subscriber address within framed IPv6 prefix 2001:db8:0:1::/64 unknown", report.IPv6.Skipped)
+			},
+		},
+		{
+			name:        "by username unreachable",
+			options:     []Option{UserName("user@example.net")},
+			unreachable: map[string]bool{"100.64.0.10": true},
+			setup: func(api *statemock.SubscriberApi) {
+				api.On("GetSubscribers", mock.Anything, &state.SubscriberApiGetSubscribersOpts{SubscriberUserName: rbfs.OptionalString("user@example.net")}).
+					Return(refs[1:], nil, nil)
+				api.On("GetSubscriber", mock.Anything, 72339069014638600).Return(session, nil, nil)
+			},
+			want: func(t *testing.T, report *Report, instances map[string]string) {
+				require.False(t, report.Reachable())
+				require.EqualError(t, report.IPv4.Err, "network unreachable")
+				require.Nil(t, report.IPv4.Traceroute)
+			},
+		},
+		{
+			name:    "by ID with IPv6 host prefix",
+			options: []Option{SubscriberID(5)},
+			setup: func(api *statemock.SubscriberApi) {
+				api.On("GetSubscriber", mock.Anything, 5).Return(state.SubscriberSession{
+					Ipv6: &state.SubscriberSessionIpv6{Framed: &state.SubscriberSessionIpv6Framed{Prefix: "2001:db8::5/128"}},
+				}, nil, nil)
+			},
+			want: func(t *testing.T, report *Report, instances map[string]string) {
+				require.Equal(t, "no framed IPv4 address assigned", report.IPv4.Skipped)
+				require.Equal(t, net.ParseIP("2001:db8::5"), report.IPv6.Address)
+				require.Equal(t, "default", report.IPv6.Instance)
+				require.Equal(t, map[string]string{"2001:db8::5": "default"}, instances)
+				require.True(t, report.Reachable())
+			},
+		},
+		{
+			name:    "not found",
+			options: []Option{ClientMAC("00:04:0e:00:00:03")},
+			setup: func(api *statemock.SubscriberApi) {
+				api.On("GetSubscribers", mock.Anything, mock.Anything).Return(refs, nil, nil)
+			},
+			wantErr: "subscriber session not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscriberAPI := &statemock.SubscriberApi{}
+			getSubscriberAPIFuncOrig := getSubscriberAPIFunc
+			defer func() { getSubscriberAPIFunc = getSubscriberAPIFuncOrig }()
+			getSubscriberAPIFunc = func(c *http.Client, endpoint *url.URL) (state.SubscriberApi, error) {
+				return subscriberAPI, nil
+			}
+			subscriberAPI.Test(t)
+			tt.setup(subscriberAPI)
+			defer subscriberAPI.AssertExpectations(t)
+
+			check, err := NewCheck(tt.options...)
+			require.NoError(t, err)
+			pinger := &fakePingService{unreachable: tt.unreachable, instances: map[string]string{}}
+			s := &defaultService{ping: pinger, traceroute: &fakeTracerouteService{}}
+			report, err := s.Check(ctx, check)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.want(t, report, pinger.instances)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package traceroute
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+var (
+	// 2  router.example.net (192.0.2.1)  0.512 ms  0.410 ms *
+	hopPattern = regexp.MustCompile(`^\s*(\d+)\s+(.*)$`)
+	// (192.0.2.1)
+	addressPattern = regexp.MustCompile(`^\((.+)\)$`)
+)
+
+// parseHop parses a traceroute output line describing a single hop.
+// The round-trip times are converted to seconds. Probes without a response are reported with a zero RTT.
+// False is returned if the line does not describe a hop.
+func parseHop(line string) (state.TracerouteHop, bool) {
+	m := hopPattern.FindStringSubmatch(line)
+	if m == nil {
+		return state.TracerouteHop{}, false
+	}
+
+	hop := state.TracerouteHop{}
+	hop.Seq, _ = strconv.Atoi(m[1])
+
+	var rtts []float64
+	fields := strings.Fields(m[2])
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case field == "*":
+			rtts = append(rtts, 0)
+		case i+1 < len(fields) && fields[i+1] == "ms":
+			ms, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return state.TracerouteHop{}, false
+			}
+			const millisecondsPerSecond = 1000
+			rtts = append(rtts, ms/millisecondsPerSecond)
+			i++
+		case addressPattern.MatchString(field):
+			// The address follows the resolved host name
+			hop.Hop = addressPattern.FindStringSubmatch(field)[1]
+		case strings.HasPrefix(field, "!"):
+			// Annotations like !H (host unreachable) are not reported
+		default:
+			hop.Hop = field
+		}
+	}
+
+	for i, rtt := range rtts {
+		switch i {
+		case 0:
+			hop.Rtt1 = rtt
+		case 1:
+			hop.Rtt2 = rtt
+		case 2:
+			hop.Rtt3 = rtt
+		}
+	}
+	return hop, true
+}
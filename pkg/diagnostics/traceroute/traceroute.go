@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package traceroute
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"time"
+)
+
+type (
+	// Traceroute contains all arguments to trace the route to a destination IP address or hostname
+	Traceroute struct {
+		destinationIP   net.IP
+		destinationA    string
+		destinationAAAA string
+		sourceInterface string
+		sourceIP        net.IP
+		instanceName    string
+		maxHops         int
+		interval        time.Duration
+		size            int
+	}
+
+	// Option applies a traceroute command argument
+	Option func(*Traceroute) error
+)
+
+// NewTraceroute creates a new traceroute command
+func NewTraceroute(options ...Option) (*Traceroute, error) {
+	t := &Traceroute{
+		instanceName: "default",
+		maxHops:      30,
+		interval:     1 * time.Second,
+		size:         56,
+	}
+
+	// Apply all given traceroute option
+	for _, option := range options {
+		if err := option(t); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.destinationIP == nil && t.destinationA == "" && t.destinationAAAA == "" {
+		return nil, fmt.Errorf("traceroute destination not specified")
+	}
+
+	return t, nil
+}
+
+// Size sets the probe packet size in bytes.
+// The size must be between 8 and 32768 bytes.
+func Size(size int) Option {
+	return func(t *Traceroute) error {
+		if size < 8 || size > 32768 {
+			return fmt.Errorf("packet size must be between 8 and 32768 bytes")
+		}
+		t.size = size
+		return nil
+	}
+}
+
+// MaxHops sets the maximum number of hops to reach the destination.
+func MaxHops(maxHops int) Option {
+	return func(t *Traceroute) error {
+		if maxHops < 1 || maxHops > 255 {
+			return fmt.Errorf("max hops must be between 1 and 255")
+		}
+		t.maxHops = maxHops
+		return nil
+	}
+}
+
+// DestinationIP sets the traceroute destination IP address.
+// Override destination host name settings, if any.
+func DestinationIP(ipAddr net.IP) Option {
+	return func(t *Traceroute) error {
+		t.destinationIP = ipAddr
+		t.destinationA = ""
+		t.destinationAAAA = ""
+		return nil
+	}
+}
+
+// DestinationHostNameA sets the destination hostname that shall be translated to an IPv4 address (DNS A record)
+func DestinationHostNameA(hostname string) Option {
+	return func(t *Traceroute) error {
+		t.destinationIP = nil
+		t.destinationA = hostname
+		t.destinationAAAA = ""
+		return nil
+	}
+}
+
+// DestinationHostNameAAAA sets the destination hostname that shall be translated to an IPv6 address (DNS AAAA record)
+func DestinationHostNameAAAA(hostname string) Option {
+	return func(t *Traceroute) error {
+		t.destinationIP = nil
+		t.destinationA = ""
+		t.destinationAAAA = hostname
+		return nil
+	}
+}
+
+// SourceIP specifies the source IP address
+func SourceIP(ipAddress net.IP) Option {
+	return func(t *Traceroute) error {
+		if ipAddress != nil {
+			if t.sourceInterface != "" {
+				return fmt.Errorf("source interface and source IP are mutual exclusive")
+			}
+			t.sourceIP = ipAddress
+		}
+		return nil
+	}
+}
+
+// SourceInterface sets the traceroute source interface name.
+// Source interface and source IP are mutual exclusive!
+func SourceInterface(name string) Option {
+	return func(t *Traceroute) error {
+		if name != "" {
+			if t.sourceIP != nil {
+				return fmt.Errorf("source interface and source IP are mutual exclusive")
+			}
+			t.sourceInterface = name
+		}
+		return nil
+	}
+}
+
+// Interval sets the interval between two probes.
+// The accepted interval range is between 1ms and 5 seconds.
+func Interval(interval time.Duration) Option {
+	return func(t *Traceroute) error {
+		if interval < 1*time.Millisecond {
+			return fmt.Errorf("interval must not be less than 1ms")
+		}
+		if interval > 5*time.Second {
+			return fmt.Errorf("interval must not exceed 5s")
+		}
+		t.interval = interval
+		return nil
+	}
+}
+
+// InstanceName sets the routing instance name to run the traceroute command.
+func InstanceName(instanceName string) Option {
+	return func(t *Traceroute) error {
+		if instanceName == "" {
+			return fmt.Errorf("instance name must not be empty")
+		}
+		t.instanceName = instanceName
+		return nil
+	}
+}
+
+// intervalSeconds returns the interval in seconds with millisecond precision.
+func (t *Traceroute) intervalSeconds() float32 {
+	const scaleToMilliPrecision = 1000
+	return float32(math.Round(t.interval.Seconds()*scaleToMilliPrecision) / scaleToMilliPrecision)
+}
+
+func (t *Traceroute) SourceInterface() string {
+	return t.sourceInterface
+}
+
+func (t *Traceroute) SourceIP() net.IP {
+	return t.sourceIP
+}
+
+func (t *Traceroute) Size() int {
+	return t.size
+}
+
+func (t *Traceroute) MaxHops() int {
+	return t.maxHops
+}
+
+func (t *Traceroute) Interval() time.Duration {
+	return t.interval
+}
+
+func (t *Traceroute) InstanceName() string {
+	return t.instanceName
+}
+
+func (t *Traceroute) DestinationIP() net.IP {
+	return t.destinationIP
+}
+
+func (t *Traceroute) DestinationHostNameA() string {
+	return t.destinationA
+}
+
+func (t *Traceroute) DestinationHostNameAAAA() string {
+	return t.destinationAAAA
+}
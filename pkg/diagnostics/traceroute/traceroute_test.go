@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package traceroute
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTraceroute(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    *Traceroute
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			options: []Option{
+				DestinationHostNameA("www.rtbrick.com"),
+			},
+			want: &Traceroute{
+				destinationA: "www.rtbrick.com",
+				instanceName: "default",
+				maxHops:      30,
+				interval:     time.Second,
+				size:         56,
+			},
+		}, {
+			name: "options",
+			options: []Option{
+				DestinationIP(net.ParseIP("8.8.8.8")),
+				SourceIP(net.ParseIP("192.0.2.1")), MaxHops(16), Size(128),
+				Interval(500 * time.Millisecond), InstanceName("instance"),
+			},
+			want: &Traceroute{
+				destinationIP: net.ParseIP("8.8.8.8"),
+				sourceIP:      net.ParseIP("192.0.2.1"),
+				instanceName:  "instance",
+				maxHops:       16,
+				interval:      500 * time.Millisecond,
+				size:          128,
+			},
+		}, {
+			name: "source interface",
+			options: []Option{
+				DestinationHostNameAAAA("www.rtbrick.com"),
+				SourceInterface("ifl-0/0/1/0"),
+			},
+			want: &Traceroute{
+				destinationAAAA: "www.rtbrick.com",
+				sourceInterface: "ifl-0/0/1/0",
+				instanceName:    "default",
+				maxHops:         30,
+				interval:        time.Second,
+				size:            56,
+			},
+		}, {
+			name:    "destination not specified",
+			options: []Option{MaxHops(5)},
+			wantErr: "traceroute destination not specified",
+		}, {
+			name: "source interface and source IP are mutual exclusive",
+			options: []Option{
+				DestinationHostNameA("www.rtbrick.com"),
+				SourceInterface("ma1"),
+				SourceIP(net.ParseIP("192.0.2.1")),
+			},
+			wantErr: "source interface and source IP are mutual exclusive",
+		}, {
+			name: "max hops must be between 1 and 255",
+			options: []Option{
+				DestinationHostNameA("www.rtbrick.com"),
+				MaxHops(256),
+			},
+			wantErr: "max hops must be between 1 and 255",
+		}, {
+			name: "packet size must be between 8 and 32768 bytes",
+			options: []Option{
+				DestinationHostNameA("www.rtbrick.com"),
+				Size(4),
+			},
+			wantErr: "packet size must be between 8 and 32768 bytes",
+		}, {
+			name: "interval must not exceed 5s",
+			options: []Option{
+				DestinationHostNameA("www.rtbrick.com"),
+				Interval(6 * time.Second),
+			},
+			wantErr: "interval must not exceed 5s",
+		}, {
+			name: "instance name must not be empty",
+			options: []Option{
+				DestinationHostNameA("www.rtbrick.com"),
+				InstanceName(""),
+			},
+			wantErr: "instance name must not be empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTraceroute(tt.options...)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
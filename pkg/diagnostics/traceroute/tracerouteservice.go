@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package traceroute
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/parallel"
+	"github.com/rsys-sk/go-rbfs-client/pkg/diagnostics/internal/stream"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// This function variable we need to be able to mock the API Service
+var getActionsAPIFunc = getActionsAPI
+
+type (
+	defaultService struct {
+		client *http.Client
+	}
+
+	// Event is emitted by a streaming traceroute. Exactly one of the event fields is set.
+	Event struct {
+		// Hop holds the next hop on the path to the destination.
+		Hop *state.TracerouteHop
+		// Err holds the error that terminated the stream.
+		Err error
+	}
+
+	// Service traces the routes to given destinations.
+	Service interface {
+		// Run executes the given traceroute.
+		Run(rbfs.RbfsContext, *Traceroute) (state.Traceroute, error)
+
+		// RunAll runs all given traceroutes in parallel go routines.
+		// The results are returned in the order of the given traceroutes. All traceroutes are cancelled on the
+		// first error.
+		RunAll(rbfs.RbfsContext, ...*Traceroute) ([]state.Traceroute, error)
+
+		// Stream executes the given traceroute using the streams API and emits an event per hop.
+		// The event channel is closed when the traceroute terminates or the context is cancelled.
+		Stream(rbfs.RbfsContext, *Traceroute) (<-chan Event, error)
+	}
+)
+
+func (s *defaultService) Run(ctx rbfs.RbfsContext, traceroute *Traceroute) (state.Traceroute, error) {
+	api, err := s.getActionsAPI(ctx)
+	if err != nil {
+		return state.Traceroute{}, err
+	}
+
+	optionalTracerouteArgs := &state.ActionsApiTracerouteOpts{
+		DestinationIp:   rbfs.OptionalIP(traceroute.destinationIP),
+		DestinationAaaa: rbfs.OptionalString(traceroute.destinationAAAA),
+		DestinationA:    rbfs.OptionalString(traceroute.destinationA),
+		SourceIp:        rbfs.OptionalIP(traceroute.sourceIP),
+		SourceIfl:       rbfs.OptionalString(traceroute.sourceInterface),
+		InstanceName:    rbfs.OptionalString(traceroute.instanceName),
+		MaxHops:         rbfs.OptionalInt(traceroute.maxHops),
+		Size:            rbfs.OptionalInt(traceroute.size),
+		Interval:        rbfs.OptionalFloat32(traceroute.intervalSeconds()),
+	}
+
+	//nolint:bodyclose //generated code
+	result, _, err := api.Traceroute(ctx, optionalTracerouteArgs)
+	if err != nil {
+		return state.Traceroute{}, err
+	}
+	return result, nil
+}
+
+func (s *defaultService) RunAll(ctx rbfs.RbfsContext, traceroutes ...*Traceroute) ([]state.Traceroute, error) {
+	results := make([]state.Traceroute, len(traceroutes))
+	errs := parallel.Run(ctx, len(traceroutes), parallel.Options{}, func(ctx rbfs.RbfsContext, i int) (err error) {
+		results[i], err = s.Run(ctx, traceroutes[i])
+		return err
+	})
+	if err := parallel.FirstError(errs); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *defaultService) Stream(ctx rbfs.RbfsContext, traceroute *Traceroute) (<-chan Event, error) {
+	output, err := stream.Open(ctx, s.client, "/streams/traceroute", traceroute.query())
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		send := func(e Event) bool {
+			select {
+			case events <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		err := stream.Lines(ctx, output, func(line string) bool {
+			if hop, ok := parseHop(line); ok {
+				return send(Event{Hop: &hop})
+			}
+			return true
+		})
+		if err != nil {
+			send(Event{Err: err})
+		}
+	}()
+	return events, nil
+}
+
+// query returns the query parameters to invoke the streaming traceroute.
+func (t *Traceroute) query() url.Values {
+	query := url.Values{}
+	set := func(name, value string) {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if t.destinationIP != nil {
+		set("destination_ip", t.destinationIP.String())
+	}
+	set("destination_a", t.destinationA)
+	set("destination_aaaa", t.destinationAAAA)
+	if t.sourceIP != nil {
+		set("source_ip", t.sourceIP.String())
+	}
+	set("source_ifl", t.sourceInterface)
+	set("instance_name", t.instanceName)
+	set("max_hops", strconv.Itoa(t.maxHops))
+	set("size", strconv.Itoa(t.size))
+	set("interval", strconv.FormatFloat(float64(t.intervalSeconds()), 'f', -1, 32))
+	return query
+}
+
+func (s *defaultService) getActionsAPI(ctx rbfs.RbfsContext) (state.ActionsApi, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return getActionsAPIFunc(s.client, endpoint)
+}
+
+func getActionsAPI(c *http.Client, endpoint *url.URL) (state.ActionsApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.ActionsApi, nil
+}
+
+// NewTracerouteService creates a new traceroute defaultService.
+func NewTracerouteService(client *http.Client) Service {
+	return &defaultService{client}
+}
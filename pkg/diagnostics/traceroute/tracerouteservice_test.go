@@ -0,0 +1,201 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package traceroute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state/statemock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func expectedTraceroute(hostname string) *state.ActionsApiTracerouteOpts {
+	return &state.ActionsApiTracerouteOpts{
+		DestinationIp:   rbfs.OptionalIP(nil),
+		DestinationAaaa: rbfs.OptionalString(""),
+		DestinationA:    rbfs.OptionalString(hostname),
+		SourceIp:        rbfs.OptionalIP(nil),
+		SourceIfl:       rbfs.OptionalString(""),
+		InstanceName:    rbfs.OptionalString("default"),
+		MaxHops:         rbfs.OptionalInt(30),
+		Size:            rbfs.OptionalInt(56),
+		Interval:        rbfs.OptionalFloat32(1.0),
+	}
+}
+
+func mustTraceroute(t *testing.T, options ...Option) *Traceroute {
+	t.Helper()
+	tr, err := NewTraceroute(options...)
+	require.NoError(t, err)
+	return tr
+}
+
+func setupMock(t *testing.T, setup func(actionAPI *statemock.ActionsApi)) {
+	t.Helper()
+	actionAPI := &statemock.ActionsApi{}
+	getActionsAPIFuncOrig := getActionsAPIFunc
+	t.Cleanup(func() { getActionsAPIFunc = getActionsAPIFuncOrig })
+	getActionsAPIFunc = func(c *http.Client, endpoint *url.URL) (state.ActionsApi, error) {
+		return actionAPI, nil
+	}
+	actionAPI.Test(t)
+	setup(actionAPI)
+	t.Cleanup(func() { actionAPI.AssertExpectations(t) })
+}
+
+func Test_defaultService_Run(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+
+		traceroute func(t *testing.T) *Traceroute
+		setup      func(actionApi *statemock.ActionsApi)
+		want       state.Traceroute
+		wantErr    bool
+	}{
+		{
+			traceroute: func(t *testing.T) *Traceroute {
+				return mustTraceroute(t, DestinationHostNameA("www.rtbrick.com"))
+			},
+			setup: func(actionApi *statemock.ActionsApi) {
+				actionApi.On("Traceroute", mock.Anything, expectedTraceroute("www.rtbrick.com")).
+					Return(state.Traceroute{Command: "www.rtbrick.com"}, nil, nil)
+			},
+			want: state.Traceroute{Command: "www.rtbrick.com"},
+		},
+		{
+			traceroute: func(t *testing.T) *Traceroute {
+				return mustTraceroute(t, DestinationHostNameA("www.rtbrick.com"))
+			},
+			setup: func(actionApi *statemock.ActionsApi) {
+				actionApi.On("Traceroute", mock.Anything, expectedTraceroute("www.rtbrick.com")).
+					Return(state.Traceroute{}, nil, fmt.Errorf("test"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMock(t, tt.setup)
+
+			s := NewTracerouteService(nil)
+			got, err := s.Run(ctx, tt.traceroute(t))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_defaultService_RunAll(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+
+		traceroutes func(t *testing.T) []*Traceroute
+		setup       func(actionApi *statemock.ActionsApi)
+		want        []state.Traceroute
+		wantErr     bool
+	}{
+		{
+			name: "results in input order",
+			traceroutes: func(t *testing.T) []*Traceroute {
+				return []*Traceroute{
+					mustTraceroute(t, DestinationHostNameA("www.rtbrick.com")),
+					mustTraceroute(t, DestinationHostNameA("www.google.de")),
+				}
+			},
+			setup: func(actionApi *statemock.ActionsApi) {
+				actionApi.On("Traceroute", mock.Anything, expectedTraceroute("www.rtbrick.com")).
+					Return(state.Traceroute{Command: "www.rtbrick.com"}, nil, nil).
+					Run(func(args mock.Arguments) { time.Sleep(200 * time.Millisecond) })
+				actionApi.On("Traceroute", mock.Anything, expectedTraceroute("www.google.de")).
+					Return(state.Traceroute{Command: "www.google.de"}, nil, nil)
+			},
+			want: []state.Traceroute{{Command: "www.rtbrick.com"}, {Command: "www.google.de"}},
+		},
+		{
+			name: "error",
+			traceroutes: func(t *testing.T) []*Traceroute {
+				return []*Traceroute{mustTraceroute(t, DestinationHostNameA("www.rtbrick.com"))}
+			},
+			setup: func(actionApi *statemock.ActionsApi) {
+				actionApi.On("Traceroute", mock.Anything, expectedTraceroute("www.rtbrick.com")).
+					Return(state.Traceroute{}, nil, fmt.Errorf("test"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMock(t, tt.setup)
+
+			s := NewTracerouteService(nil)
+			got, err := s.RunAll(ctx, tt.traceroutes(t)...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_defaultService_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/rbfs/elements/test/services/opsd/proxy/streams/traceroute", r.URL.Path)
+		require.Equal(t, "16", r.URL.Query().Get("max_hops"))
+		for _, line := range []string{
+			"traceroute to 198.51.100.1 (198.51.100.1), 16 hops max, 56 byte packets",
+			" 1  192.0.2.1  0.500 ms  0.250 ms  0.125 ms",
+			" 2  * * *",
+			" 3  core.example.net (198.51.100.1)  2.000 ms * 1.000 ms",
+		} {
+			fmt.Fprintln(w, line)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "test")
+	require.NoError(t, err)
+
+	events, err := NewTracerouteService(nil).Stream(ctx, mustTraceroute(t, DestinationHostNameA("www.rtbrick.com"), MaxHops(16)))
+	require.NoError(t, err)
+
+	var hops []state.TracerouteHop
+	for e := range events {
+		require.NoError(t, e.Err)
+		hops = append(hops, *e.Hop)
+	}
+	require.Equal(t, []state.TracerouteHop{
+		{Seq: 1, Hop: "192.0.2.1", Rtt1: 0.0005, Rtt2: 0.00025, Rtt3: 0.000125},
+		{Seq: 2},
+		{Seq: 3, Hop: "198.51.100.1", Rtt1: 0.002, Rtt3: 0.001},
+	}, hops)
+}
@@ -0,0 +1,376 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package traceroute
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// This function variable we need to be able to mock the API Service
+var getRoutesAPIFunc = getRoutesAPI
+
+const (
+	// HopAdded indicates a hop not present on the previous path.
+	HopAdded = ChangeType("added")
+	// HopRemoved indicates a hop of the previous path missing on the current path.
+	HopRemoved = ChangeType("removed")
+	// HopReordered indicates a hop present on both paths at a different position relative to the other hops.
+	HopReordered = ChangeType("reordered")
+	// HopRepeated indicates a repeated occurrence of a hop present on one of the paths only, e.g. caused by a
+	// routing loop. The previous sequence number is zero if the repetition appeared on the current path, the
+	// sequence number is zero if the repetition disappeared from the current path.
+	HopRepeated = ChangeType("repeated")
+	// RTTShift indicates a hop present on both paths whose round-trip time changed more than the tolerated shift.
+	RTTShift = ChangeType("rtt-shift")
+)
+
+type (
+	// Resolver resolves IP addresses to host names. The net.Resolver implements this interface.
+	Resolver interface {
+		LookupAddr(ctx context.Context, addr string) ([]string, error)
+	}
+
+	// PathKey identifies a tracked path.
+	PathKey struct {
+		// Element holds the name of the element executing the traceroute.
+		Element string
+		// Destination holds the destination IP address or host name.
+		Destination string
+		// Instance holds the routing instance name.
+		Instance string
+	}
+
+	// Hop describes an annotated hop of a path.
+	Hop struct {
+		// Seq holds the hop sequence number.
+		Seq int
+		// Address holds the hop IP address or an empty string, if the hop did not respond.
+		Address string
+		// Names holds the host names the hop address resolves to.
+		Names []string
+		// RTT holds the average round-trip time of all answered probes.
+		RTT time.Duration
+		// Route holds the route of the source element matching the hop address, if any.
+		Route *state.Routes
+	}
+
+	// Path holds the hops of a single traceroute run.
+	Path struct {
+		// Key identifies the path.
+		Key PathKey
+		// Time holds the time the path was traced.
+		Time time.Time
+		// Hops holds the hops ordered by sequence number.
+		Hops []Hop
+	}
+
+	// ChangeType describes the kind of change between two paths.
+	ChangeType string
+
+	// Change describes a single change of a hop between two paths.
+	Change struct {
+		// Type holds the change type.
+		Type ChangeType
+		// Address holds the hop address.
+		Address string
+		// PreviousSeq holds the hop sequence number on the previous path. Zero for added hops.
+		PreviousSeq int
+		// Seq holds the hop sequence number on the current path. Zero for removed hops.
+		Seq int
+		// PreviousRTT holds the round-trip time on the previous path.
+		PreviousRTT time.Duration
+		// RTT holds the round-trip time on the current path.
+		RTT time.Duration
+	}
+
+	// Diff holds the changes between the previous and the current path.
+	Diff struct {
+		// Previous holds the previous path or nil, if the path is traced for the first time.
+		Previous *Path
+		// Current holds the current path.
+		Current *Path
+		// Changes holds all hop changes.
+		Changes []Change
+	}
+
+	// Tracker traces paths repeatedly and reports the changes between consecutive runs.
+	Tracker struct {
+		service  Service
+		client   *http.Client
+		resolver Resolver
+		rttShift time.Duration
+		now      func() time.Time
+
+		mu    sync.Mutex
+		paths map[PathKey]*Path
+	}
+
+	// TrackerOption applies a tracker option.
+	TrackerOption func(*Tracker)
+)
+
+// WithResolver sets the resolver used to annotate hops with host names.
+// By default, hops are not resolved.
+func WithResolver(resolver Resolver) TrackerOption {
+	return func(t *Tracker) {
+		t.resolver = resolver
+	}
+}
+
+// RTTShiftThreshold sets the round-trip time change reported as RTT shift. The default threshold is 10ms.
+func RTTShiftThreshold(threshold time.Duration) TrackerOption {
+	return func(t *Tracker) {
+		t.rttShift = threshold
+	}
+}
+
+// NewTracker creates a new path tracker.
+func NewTracker(client *http.Client, options ...TrackerOption) *Tracker {
+	t := &Tracker{
+		service:  NewTracerouteService(client),
+		client:   client,
+		rttShift: 10 * time.Millisecond,
+		now:      time.Now,
+		paths:    map[PathKey]*Path{},
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// PathChanged reports whether hops were added, removed or reordered.
+func (d *Diff) PathChanged() bool {
+	for _, change := range d.Changes {
+		if change.Type != RTTShift {
+			return true
+		}
+	}
+	return false
+}
+
+// Track traces the path from the given element, annotates the hops and compares the path with the previously
+// tracked path of the same element, destination and instance.
+// Annotating hops is best effort: Addresses that cannot be resolved or routed remain without annotation.
+func (t *Tracker) Track(ctx rbfs.RbfsContext, element string, traceroute *Traceroute) (*Diff, error) {
+	elementCtx := rbfs.WithElement(ctx, element)
+	startTime := t.now()
+	result, err := t.service.Run(elementCtx, traceroute)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &Path{Key: traceroute.key(element), Time: startTime}
+	for _, hop := range result.Hops {
+		current.Hops = append(current.Hops, Hop{Seq: hop.Seq, Address: hop.Hop, RTT: averageRTT(hop)})
+	}
+	t.annotate(elementCtx, traceroute.instanceName, current)
+
+	t.mu.Lock()
+	previous := t.paths[current.Key]
+	t.paths[current.Key] = current
+	t.mu.Unlock()
+
+	diff := &Diff{Previous: previous, Current: current}
+	if previous != nil {
+		diff.Changes = Compare(previous, current, t.rttShift)
+	}
+	return diff, nil
+}
+
+// Path returns the most recently tracked path of the given key.
+func (t *Tracker) Path(key PathKey) (*Path, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path, ok := t.paths[key]
+	return path, ok
+}
+
+// Compare returns the hop changes between the previous and the current path. Hops without a response are ignored.
+// Round-trip time shifts are reported if the RTT of a hop changed by more than the given threshold. Repeated
+// occurrences of a hop present on one of the paths only are reported as HopRepeated.
+func Compare(previous, current *Path, rttShift time.Duration) []Change {
+	previousHops, currentHops := respondingHops(previous), respondingHops(current)
+	var changes []Change
+	var previousCommon, currentCommon []Hop
+	for _, hop := range previousHops {
+		if _, ok := findHop(currentHops, hop.Address); !ok {
+			changes = append(changes, Change{Type: HopRemoved, Address: hop.Address, PreviousSeq: hop.Seq, PreviousRTT: hop.RTT})
+		} else {
+			previousCommon = append(previousCommon, hop)
+		}
+	}
+	for _, hop := range currentHops {
+		if _, ok := findHop(previousHops, hop.Address); !ok {
+			changes = append(changes, Change{Type: HopAdded, Address: hop.Address, Seq: hop.Seq, RTT: hop.RTT})
+		} else {
+			currentCommon = append(currentCommon, hop)
+		}
+	}
+
+	// The common hops are compared by position. Their number differs, if an address is repeated on one of the
+	// paths (e.g. a routing loop).
+	for i, hop := range currentCommon {
+		before, _ := findHop(previousCommon, hop.Address)
+		positional := i < len(previousCommon) && previousCommon[i].Address == hop.Address
+		if positional {
+			before = previousCommon[i]
+		} else if _, repeated := findHop(currentCommon[:i], hop.Address); repeated {
+			changes = append(changes, Change{Type: HopRepeated, Address: hop.Address, Seq: hop.Seq, RTT: hop.RTT})
+			continue
+		}
+		change := Change{Address: hop.Address, PreviousSeq: before.Seq, Seq: hop.Seq, PreviousRTT: before.RTT, RTT: hop.RTT}
+		if !positional {
+			change.Type = HopReordered
+			changes = append(changes, change)
+		}
+		if shift := hop.RTT - before.RTT; shift > rttShift || -shift > rttShift {
+			change.Type = RTTShift
+			changes = append(changes, change)
+		}
+	}
+	for i := len(currentCommon); i < len(previousCommon); i++ {
+		hop := previousCommon[i]
+		change := Change{Type: HopRemoved, Address: hop.Address, PreviousSeq: hop.Seq, PreviousRTT: hop.RTT}
+		if _, repeated := findHop(previousCommon[:i], hop.Address); repeated {
+			change.Type = HopRepeated
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// annotate resolves the host names and the routes of all responding hops. The unicast routes of the instance are
+// fetched once per address family, because the prefix filter of the routes API matches prefixes exactly and does
+// not perform a longest prefix match.
+func (t *Tracker) annotate(ctx rbfs.RbfsContext, instance string, path *Path) {
+	var api state.RoutesApi
+	if endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName); err == nil {
+		api, _ = getRoutesAPIFunc(t.client, endpoint)
+	}
+	tables := make(map[string][]state.Routes)
+
+	for i := range path.Hops {
+		hop := &path.Hops[i]
+		if hop.Address == "" {
+			continue
+		}
+		if t.resolver != nil {
+			if names, err := t.resolver.LookupAddr(ctx, hop.Address); err == nil {
+				for _, name := range names {
+					hop.Names = append(hop.Names, strings.TrimSuffix(name, "."))
+				}
+			}
+		}
+		ip := net.ParseIP(hop.Address)
+		if api == nil || ip == nil {
+			continue
+		}
+		afi := "ipv4"
+		if ip.To4() == nil {
+			afi = "ipv6"
+		}
+		routes, ok := tables[afi]
+		if !ok {
+			routes = getUnicastRoutes(ctx, api, instance, afi)
+			tables[afi] = routes
+		}
+		hop.Route = longestPrefixMatch(routes, ip)
+	}
+}
+
+// getUnicastRoutes returns all unicast routes of the given instance and address family or nil, if the routes
+// cannot be fetched.
+func getUnicastRoutes(ctx context.Context, api state.RoutesApi, instance, afi string) []state.Routes {
+	//nolint:bodyclose //generated code
+	routes, _, err := api.GetInstanceUnicastRoutes(ctx, instance, afi, "unicast", nil)
+	if err != nil {
+		return nil
+	}
+	return routes.Routes
+}
+
+// longestPrefixMatch returns the longest prefix route matching the given address or nil, if no route matches.
+func longestPrefixMatch(routes []state.Routes, ip net.IP) *state.Routes {
+	var best *state.Routes
+	bestLength := -1
+	for i := range routes {
+		route := &routes[i]
+		prefix := route.Prefix4
+		if ip.To4() == nil {
+			prefix = route.Prefix6
+		}
+		_, network, err := net.ParseCIDR(prefix)
+		if err != nil || !network.Contains(ip) {
+			continue
+		}
+		if length, _ := network.Mask.Size(); length > bestLength {
+			best, bestLength = route, length
+		}
+	}
+	return best
+}
+
+// key returns the key of the path traced from the given element.
+func (t *Traceroute) key(element string) PathKey {
+	destination := t.destinationA
+	switch {
+	case t.destinationIP != nil:
+		destination = t.destinationIP.String()
+	case t.destinationAAAA != "":
+		destination = t.destinationAAAA
+	}
+	return PathKey{Element: element, Destination: destination, Instance: t.instanceName}
+}
+
+// averageRTT returns the average round-trip time of all answered probes of the given hop.
+func averageRTT(hop state.TracerouteHop) time.Duration {
+	var sum float64
+	var n int
+	for _, rtt := range []float64{hop.Rtt1, hop.Rtt2, hop.Rtt3} {
+		if rtt > 0 {
+			sum += rtt
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return time.Duration(sum / float64(n) * float64(time.Second))
+}
+
+func respondingHops(path *Path) []Hop {
+	var hops []Hop
+	for _, hop := range path.Hops {
+		if hop.Address != "" {
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+func findHop(hops []Hop, address string) (Hop, bool) {
+	for _, hop := range hops {
+		if hop.Address == address {
+			return hop, true
+		}
+	}
+	return Hop{}, false
+}
+
+func getRoutesAPI(c *http.Client, endpoint *url.URL) (state.RoutesApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.RoutesApi, nil
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package traceroute
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state/statemock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	if names, ok := r[addr]; ok {
+		return names, nil
+	}
+	return nil, fmt.Errorf("no such host")
+}
+
+func TestTracker_Track(t *testing.T) {
+	endpoint, err := url.Parse("http://localhost:8080")
+	require.NoError(t, err)
+	ctx, err := rbfs.NewRbfsContext(context.Background(), endpoint, "ctrld")
+	require.NoError(t, err)
+
+	actionAPI, routesAPI := &statemock.ActionsApi{}, &statemock.RoutesApi{}
+	getActionsAPIFuncOrig, getRoutesAPIFuncOrig := getActionsAPIFunc, getRoutesAPIFunc
+	defer func() { getActionsAPIFunc, getRoutesAPIFunc = getActionsAPIFuncOrig, getRoutesAPIFuncOrig }()
+	getActionsAPIFunc = func(c *http.Client, endpoint *url.URL) (state.ActionsApi, error) {
+		require.Equal(t, "/api/v1/rbfs/elements/leaf01/services/opsd/proxy", endpoint.Path)
+		return actionAPI, nil
+	}
+	getRoutesAPIFunc = func(c *http.Client, endpoint *url.URL) (state.RoutesApi, error) {
+		require.Equal(t, "/api/v1/rbfs/elements/leaf01/services/opsd/proxy", endpoint.Path)
+		return routesAPI, nil
+	}
+	actionAPI.Test(t)
+	routesAPI.Test(t)
+	defer actionAPI.AssertExpectations(t)
+	defer routesAPI.AssertExpectations(t)
+
+	traceroute, err := NewTraceroute(DestinationIP(net.ParseIP("198.51.100.1")))
+	require.NoError(t, err)
+	actionAPI.On("Traceroute", mock.Anything, mock.Anything).
+		Return(state.Traceroute{Hops: []state.TracerouteHop{
+			{Seq: 1, Hop: "192.0.2.1", Rtt1: 0.001, Rtt2: 0.003},
+			{Seq: 2, Hop: "192.0.2.2", Rtt1: 0.010},
+			{Seq: 3},
+			{Seq: 4, Hop: "198.51.100.1", Rtt1: 0.020},
+		}}, nil, nil).Once()
+	actionAPI.On("Traceroute", mock.Anything, mock.Anything).
+		Return(state.Traceroute{Hops: []state.TracerouteHop{
+			{Seq: 1, Hop: "192.0.2.1", Rtt1: 0.002},
+			{Seq: 2, Hop: "192.0.2.3", Rtt1: 0.010},
+			{Seq: 3, Hop: "198.51.100.1", Rtt1: 0.040},
+		}}, nil, nil).Once()
+	// The routes are fetched without prefix filter once per tracked path, since the filter matches exactly
+	routesAPI.On("GetInstanceUnicastRoutes", mock.Anything, "default", "ipv4", "unicast", (*state.RoutesApiGetInstanceUnicastRoutesOpts)(nil)).
+		Return(state.InstanceUnicastRoutes{Routes: []state.Routes{
+			{Prefix4: "0.0.0.0/0", Source: "static"},
+			{Prefix4: "192.0.2.0/24", Source: "isis"},
+			{Prefix4: "192.0.2.128/25", Source: "bgp"},
+		}}, nil, nil).Twice()
+
+	tracker := NewTracker(nil, WithResolver(fakeResolver{"192.0.2.1": {"leaf01.example.net."}}))
+	diff, err := tracker.Track(ctx, "leaf01", traceroute)
+	require.NoError(t, err)
+	require.Nil(t, diff.Previous)
+	require.Empty(t, diff.Changes)
+	require.Equal(t, PathKey{Element: "leaf01", Destination: "198.51.100.1", Instance: "default"}, diff.Current.Key)
+	require.Equal(t, Hop{
+		Seq:     1,
+		Address: "192.0.2.1",
+		Names:   []string{"leaf01.example.net"},
+		RTT:     2 * time.Millisecond,
+		Route:   &state.Routes{Prefix4: "192.0.2.0/24", Source: "isis"},
+	}, diff.Current.Hops[0])
+	require.Equal(t, Hop{Seq: 3}, diff.Current.Hops[2])
+	require.Equal(t, "static", diff.Current.Hops[3].Route.Source)
+
+	diff, err = tracker.Track(ctx, "leaf01", traceroute)
+	require.NoError(t, err)
+	require.NotNil(t, diff.Previous)
+	require.True(t, diff.PathChanged())
+	require.Equal(t, []Change{
+		{Type: HopRemoved, Address: "192.0.2.2", PreviousSeq: 2, PreviousRTT: 10 * time.Millisecond},
+		{Type: HopAdded, Address: "192.0.2.3", Seq: 2, RTT: 10 * time.Millisecond},
+		{Type: RTTShift, Address: "198.51.100.1", PreviousSeq: 4, Seq: 3, PreviousRTT: 20 * time.Millisecond, RTT: 40 * time.Millisecond},
+	}, diff.Changes)
+
+	path, ok := tracker.Path(diff.Current.Key)
+	require.True(t, ok)
+	require.Same(t, diff.Current, path)
+}
+
+func TestCompare_Reordered(t *testing.T) {
+	previous := &Path{Hops: []Hop{{Seq: 1, Address: "a"}, {Seq: 2, Address: "b"}, {Seq: 3, Address: "c"}}}
+	current := &Path{Hops: []Hop{{Seq: 1, Address: "a"}, {Seq: 2, Address: "c"}, {Seq: 3, Address: "b"}}}
+	require.Equal(t, []Change{
+		{Type: HopReordered, Address: "c", PreviousSeq: 3, Seq: 2},
+		{Type: HopReordered, Address: "b", PreviousSeq: 2, Seq: 3},
+	}, Compare(previous, current, time.Millisecond))
+
+	diff := &Diff{Changes: []Change{{Type: RTTShift}}}
+	require.False(t, diff.PathChanged())
+}
+
+func TestCompare_RepeatedHop(t *testing.T) {
+	previous := &Path{Hops: []Hop{{Seq: 1, Address: "10.0.0.1"}, {Seq: 2, Address: "10.0.0.2"}}}
+	looping := &Path{Hops: []Hop{{Seq: 1, Address: "10.0.0.1"}, {Seq: 2, Address: "10.0.0.2"}, {Seq: 3, Address: "10.0.0.1"}}}
+	require.Equal(t, []Change{
+		{Type: HopRepeated, Address: "10.0.0.1", Seq: 3},
+	}, Compare(previous, looping, time.Millisecond))
+	require.Equal(t, []Change{
+		{Type: HopRepeated, Address: "10.0.0.1", PreviousSeq: 3},
+	}, Compare(looping, previous, time.Millisecond))
+	require.Empty(t, Compare(looping, looping, time.Millisecond))
+
+	diff := &Diff{Changes: Compare(previous, looping, time.Millisecond)}
+	require.True(t, diff.PathChanged())
+}
+
+func TestLongestPrefixMatch(t *testing.T) {
+	routes := []state.Routes{
+		{Prefix4: "0.0.0.0/0", Source: "static"},
+		{Prefix4: "192.0.2.0/24", Source: "isis"},
+		{Prefix4: "192.0.2.128/25", Source: "bgp"},
+		{Prefix6: "2001:db8::/32", Source: "isis"},
+	}
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"192.0.2.1", "isis"},
+		{"192.0.2.200", "bgp"},
+		{"198.51.100.1", "static"},
+		{"2001:db8::1", "isis"},
+		{"2001:db9::1", ""},
+	}
+	for _, test := range tests {
+		route := longestPrefixMatch(routes, net.ParseIP(test.address))
+		if test.want == "" {
+			require.Nil(t, route, test.address)
+			continue
+		}
+		require.NotNil(t, route, test.address)
+		require.Equal(t, test.want, route.Source, test.address)
+	}
+}
@@ -38,6 +38,7 @@ type (
 )
 
 // NewClient creates a new client to query switch alerts.
+// Create the HTTP client with rbfs.NewHTTPClient to apply TLS, logging or tracing options.
 func NewClient(c *http.Client) Client {
 	return &client{c}
 }
@@ -0,0 +1,275 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+type (
+	// ResponseCache caches the successful GET responses of the requests sent by HTTP clients with the Caching
+	// option per element. The responses are cached for the lifetime defined by the Cache-Control or Expires
+	// headers of the response or, if the response defines none, for the TTL configured for the operation.
+	// A ResponseCache can be shared by multiple API clients.
+	ResponseCache struct {
+		defaultTTL time.Duration
+		ttls       map[string]time.Duration
+
+		mu      sync.Mutex
+		entries map[string]*cacheEntry
+		// generation holds the number of invalidations of all elements and generations the number of
+		// invalidations per element to discard responses of requests overlapping with an invalidation.
+		generation  uint64
+		generations map[string]uint64
+		calls       map[string]*cacheCall
+	}
+
+	// CacheOption applies an optional response cache setting.
+	CacheOption func(*ResponseCache)
+
+	cacheEntry struct {
+		element    string
+		status     string
+		statusCode int
+		header     http.Header
+		body       []byte
+		expires    time.Time
+	}
+
+	// cacheCall describes a request in flight, which is shared by concurrent identical requests.
+	cacheCall struct {
+		done  chan struct{}
+		entry *cacheEntry
+		err   error
+	}
+
+	// cachingTransport serves GET requests from a response cache.
+	cachingTransport struct {
+		next     http.RoundTripper
+		cache    *ResponseCache
+		basePath string
+	}
+)
+
+// DefaultTTL caches the responses of all operations without Cache-Control and Expires headers for the given
+// time. By default, these responses are not cached.
+func DefaultTTL(ttl time.Duration) CacheOption {
+	return func(c *ResponseCache) {
+		c.defaultTTL = ttl
+	}
+}
+
+// OperationTTL caches the responses of the given operation (e.g. getInterfaces) without Cache-Control and
+// Expires headers for the given time.
+func OperationTTL(operation string, ttl time.Duration) CacheOption {
+	return func(c *ResponseCache) {
+		c.ttls[operation] = ttl
+	}
+}
+
+// NewResponseCache creates a new empty response cache.
+func NewResponseCache(options ...CacheOption) *ResponseCache {
+	c := &ResponseCache{
+		ttls:        make(map[string]time.Duration),
+		entries:     make(map[string]*cacheEntry),
+		generations: make(map[string]uint64),
+		calls:       make(map[string]*cacheCall),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Invalidate drops all cached responses of the given element.
+func (c *ResponseCache) Invalidate(elementName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[elementName]++
+	for key, entry := range c.entries {
+		if entry.element == elementName {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateAll drops all cached responses.
+func (c *ResponseCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = make(map[string]*cacheEntry)
+}
+
+// Caching returns an option to serve GET requests from the given response cache. Concurrent identical GET requests
+// are sent once and share the response. All other requests invalidate the cached responses of the addressed
+// element when they complete, except for the opsd actions and streams (e.g. ping and traceroute), which do not change
+// the state of the element. Requests with a Cache-Control: no-cache header bypass the cache.
+func Caching(cache *ResponseCache) Option {
+	return func(c *state.Configuration) {
+		wrapTransport(c, func(next http.RoundTripper) http.RoundTripper {
+			return &cachingTransport{next: next, cache: cache, basePath: basePath(c)}
+		})
+	}
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := describeCall(req, t.basePath)
+	switch {
+	case req.Method == http.MethodHead:
+		return t.next.RoundTrip(req)
+	case req.Method != http.MethodGet:
+		resp, err := t.next.RoundTrip(req)
+		if !call.readOnly {
+			t.cache.Invalidate(call.element)
+		}
+		return resp, err
+	case cacheDirectives(req.Header)["no-cache"]:
+		return t.next.RoundTrip(req)
+	}
+
+	key := requestKey(req, call.element)
+	for {
+		t.cache.mu.Lock()
+		if entry, ok := t.cache.entries[key]; ok {
+			if time.Now().Before(entry.expires) {
+				t.cache.mu.Unlock()
+				return entry.response(req), nil
+			}
+			delete(t.cache.entries, key)
+		}
+		if shared, ok := t.cache.calls[key]; ok {
+			t.cache.mu.Unlock()
+			select {
+			case <-shared.done:
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+			if shared.err != nil && isContextError(shared.err) && req.Context().Err() == nil {
+				// The request shared was canceled by its caller, retry
+				continue
+			}
+			if shared.err != nil {
+				return nil, shared.err
+			}
+			return shared.entry.response(req), nil
+		}
+
+		shared := &cacheCall{done: make(chan struct{})}
+		t.cache.calls[key] = shared
+		generation, elementGeneration := t.cache.generation, t.cache.generations[call.element]
+		t.cache.mu.Unlock()
+
+		shared.entry, shared.err = t.fetch(req, call)
+
+		t.cache.mu.Lock()
+		delete(t.cache.calls, key)
+		if shared.err == nil && time.Now().Before(shared.entry.expires) && generation == t.cache.generation &&
+			elementGeneration == t.cache.generations[call.element] {
+			t.cache.entries[key] = shared.entry
+		}
+		t.cache.mu.Unlock()
+		close(shared.done)
+
+		if shared.err != nil {
+			return nil, shared.err
+		}
+		return shared.entry.response(req), nil
+	}
+}
+
+// fetch sends the request and reads the response into a cache entry. The entry expires immediately, if the
+// response must not be cached.
+func (t *cachingTransport) fetch(req *http.Request, call apiCall) (*cacheEntry, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{
+		element:    call.element,
+		status:     resp.Status,
+		statusCode: resp.StatusCode,
+		header:     resp.Header,
+		body:       body,
+	}
+	if resp.StatusCode == http.StatusOK {
+		entry.expires = t.cache.expires(resp, call.operation)
+	}
+	return entry, nil
+}
+
+// expires returns the expiry time of the given response. The zero time is returned, if the response must not be
+// cached.
+func (c *ResponseCache) expires(resp *http.Response, operation string) time.Time {
+	directives := cacheDirectives(resp.Header)
+	switch {
+	case directives["no-store"] || directives["no-cache"]:
+		return time.Time{}
+	case directives["max-age"] || resp.Header.Get("Expires") != "":
+		return state.CacheExpires(resp)
+	}
+	ttl, ok := c.ttls[operation]
+	if !ok {
+		ttl = c.defaultTTL
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// response returns a new response of the cache entry.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        e.status,
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// requestKey identifies a request by the element, the URL and the credentials.
+func requestKey(req *http.Request, elementName string) string {
+	credentials := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return elementName + " " + req.URL.String() + " " + hex.EncodeToString(credentials[:8])
+}
+
+// cacheDirectives returns the names of the Cache-Control directives of the given header.
+func cacheDirectives(header http.Header) map[string]bool {
+	directives := make(map[string]bool)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			directives[strings.ToLower(name)] = true
+		}
+	}
+	return directives
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
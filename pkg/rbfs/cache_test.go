@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// countingServer answers all requests with an empty JSON list and the given Cache-Control header and counts
+// the GET requests per path.
+func countingServer(t *testing.T, cacheControl string, delay time.Duration) (*httptest.Server, func(path string) int) {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			mu.Lock()
+			counts[r.URL.Path]++
+			mu.Unlock()
+		}
+		time.Sleep(delay)
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return counts[path]
+	}
+}
+
+func cachingClient(t *testing.T, server *httptest.Server, elementName string, cache *ResponseCache) (RbfsContext, *state.APIClient) {
+	ctx, err := NewRbfsContext(context.Background(), mustParse(t, server.URL), elementName)
+	require.NoError(t, err)
+	endpoint, err := ctx.GetServiceEndpoint(OpsdServiceName)
+	require.NoError(t, err)
+	return ctx, GetAPIClient(http.DefaultClient, endpoint, Caching(cache))
+}
+
+func TestCaching(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		options      []CacheOption
+		want         int
+	}{
+		{name: "max-age", cacheControl: "max-age=60", want: 1},
+		{name: "operation TTL", options: []CacheOption{OperationTTL("getBGPInstances", time.Minute)}, want: 1},
+		{name: "default TTL", options: []CacheOption{DefaultTTL(time.Minute)}, want: 1},
+		{name: "no TTL", options: []CacheOption{OperationTTL("getBGPPeerings", time.Minute)}, want: 3},
+		{name: "no-store", cacheControl: "no-store", options: []CacheOption{DefaultTTL(time.Minute)}, want: 3},
+		{name: "expired", cacheControl: "max-age=0", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, count := countingServer(t, tt.cacheControl, 0)
+			ctx, client := cachingClient(t, server, "leaf1", NewResponseCache(tt.options...))
+			for i := 0; i < 3; i++ {
+				instances, _, err := client.BGPApi.GetBGPInstances(ctx)
+				require.NoError(t, err)
+				require.Empty(t, instances)
+			}
+			require.Equal(t, tt.want, count("/api/v1/rbfs/elements/leaf1/services/opsd/proxy/bgp/instances"))
+		})
+	}
+}
+
+func TestCaching_Invalidate(t *testing.T) {
+	server, count := countingServer(t, "", 0)
+	cache := NewResponseCache(DefaultTTL(time.Minute))
+	leaf1, client1 := cachingClient(t, server, "leaf1", cache)
+	leaf2, client2 := cachingClient(t, server, "leaf2", cache)
+	get := func(ctx RbfsContext, client *state.APIClient) {
+		_, _, err := client.BGPApi.GetBGPInstances(ctx)
+		require.NoError(t, err)
+	}
+
+	get(leaf1, client1)
+	get(leaf2, client2)
+	// Mutating calls invalidate the cached responses of the same element only
+	_, err := client1.BGPApi.ClearBGPPeering(leaf1, "default", "192.0.2.1", nil)
+	require.NoError(t, err)
+	get(leaf1, client1)
+	get(leaf2, client2)
+	require.Equal(t, 2, count("/api/v1/rbfs/elements/leaf1/services/opsd/proxy/bgp/instances"))
+	require.Equal(t, 1, count("/api/v1/rbfs/elements/leaf2/services/opsd/proxy/bgp/instances"))
+
+	// Actions and streams do not invalidate the cached responses
+	_, _, _ = client1.ActionsApi.Ping(leaf1, nil)
+	_, _, _ = client1.ActionsApi.Traceroute(leaf1, nil)
+	_, _, _ = client1.StreamsApi.StreamPing(leaf1, nil)
+	get(leaf1, client1)
+	require.Equal(t, 2, count("/api/v1/rbfs/elements/leaf1/services/opsd/proxy/bgp/instances"))
+
+	cache.Invalidate("leaf2")
+	get(leaf2, client2)
+	require.Equal(t, 2, count("/api/v1/rbfs/elements/leaf2/services/opsd/proxy/bgp/instances"))
+	cache.InvalidateAll()
+	get(leaf1, client1)
+	get(leaf2, client2)
+	require.Equal(t, 3, count("/api/v1/rbfs/elements/leaf1/services/opsd/proxy/bgp/instances"))
+	require.Equal(t, 3, count("/api/v1/rbfs/elements/leaf2/services/opsd/proxy/bgp/instances"))
+}
+
+func TestCaching_InvalidateAllInFlight(t *testing.T) {
+	var requests atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+	cache := NewResponseCache(DefaultTTL(time.Minute))
+	ctx, client := cachingClient(t, server, "leaf1", cache)
+
+	// A response requested before the invalidation is not cached
+	done := make(chan error)
+	go func() {
+		_, _, err := client.BGPApi.GetBGPInstances(ctx)
+		done <- err
+	}()
+	<-started
+	cache.InvalidateAll()
+	close(release)
+	require.NoError(t, <-done)
+	_, _, err := client.BGPApi.GetBGPInstances(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestCaching_Coalescing(t *testing.T) {
+	// Responses, which must not be cached, are shared by concurrent requests nevertheless
+	server, count := countingServer(t, "no-store", 200*time.Millisecond)
+	ctx, client := cachingClient(t, server, "leaf1", NewResponseCache())
+
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := client.BGPApi.GetBGPInstances(ctx); err != nil {
+				failed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Zero(t, failed.Load())
+	require.Equal(t, 1, count("/api/v1/rbfs/elements/leaf1/services/opsd/proxy/bgp/instances"))
+
+	_, _, err := client.BGPApi.GetBGPInstances(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count("/api/v1/rbfs/elements/leaf1/services/opsd/proxy/bgp/instances"))
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/openapi"
+)
+
+// CtrldServiceName identifies the requests sent to CTRLD itself in logs, traces and metrics.
+const CtrldServiceName = ServiceName("ctrld")
+
+const (
+	ctrldElementsPath = "/api/v1/ctrld/elements"
+	rbfsElementsPath  = "/api/v1/rbfs/elements/"
+)
+
+// apiCall describes the service, the operation and the element of a request.
+type apiCall struct {
+	service ServiceName
+	// operation holds the opsd operation ID, the CTRLD operation or the path of other services.
+	// The operation is empty if the request does not match any known operation.
+	operation string
+	element   string
+	// readOnly reports opsd operations not changing the state of the element despite their method, i.e. the
+	// actions (e.g. ping and traceroute) and streams.
+	readOnly bool
+}
+
+// readOnlyTags holds the tags of the opsd operations not changing the state of the element.
+var readOnlyTags = map[string]bool{"Actions": true, "Streams": true}
+
+// describeCall describes the given request. Requests of API clients without service proxy endpoint are considered
+// opsd requests relative to the given base path.
+func describeCall(req *http.Request, basePath string) apiCall {
+	call := apiCall{element: ElementName(req.Context())}
+	path := req.URL.Path
+
+	switch {
+	case strings.HasPrefix(path, rbfsElementsPath):
+		// /api/v1/rbfs/elements/{element_name}/services/{service_name}/proxy/{path}
+		segments := strings.SplitN(strings.TrimPrefix(path, rbfsElementsPath), "/", 5)
+		if len(segments) < 4 || segments[1] != "services" || segments[3] != "proxy" {
+			return call
+		}
+		if call.element == "" {
+			call.element = segments[0]
+		}
+		call.service = ServiceName(segments[2])
+		path = ""
+		if len(segments) == 5 {
+			path = "/" + segments[4]
+		}
+	case path == ctrldElementsPath || strings.HasPrefix(path, ctrldElementsPath+"/"):
+		// /api/v1/ctrld/elements[/{element_name}[/services]]
+		call.service = CtrldServiceName
+		segments := strings.Split(strings.TrimPrefix(path, ctrldElementsPath), "/")[1:]
+		switch {
+		case len(segments) == 0:
+			call.operation = "listElements"
+		case len(segments) == 1:
+			call.operation = "getElement"
+		case len(segments) == 2 && segments[1] == "services":
+			call.operation = "listServices"
+		}
+		if call.element == "" && len(segments) > 0 {
+			call.element = segments[0]
+		}
+		return call
+	case basePath != "" && strings.HasPrefix(path, basePath):
+		call.service = OpsdServiceName
+		path = strings.TrimPrefix(path, basePath)
+	default:
+		return call
+	}
+
+	if call.service != OpsdServiceName {
+		call.operation = path
+		return call
+	}
+	if spec, err := openapi.Opsd(); err == nil {
+		if op, _, ok := spec.Match(req.Method, path); ok {
+			call.operation = op.ID()
+			for _, tag := range op.Tags {
+				call.readOnly = call.readOnly || readOnlyTags[tag]
+			}
+		}
+	}
+	return call
+}
@@ -29,7 +29,24 @@ func UserAgent(value string) Option {
 }
 
 // GetAPIClient creates a new API client for the given endpoint.
+// If an option cannot be applied, e.g. a TLS option referring to a missing file, all requests of the API client fail
+// with the option's error. Use NewAPIClient to report the error when the API client is created.
 func GetAPIClient(client *http.Client, endpoint *url.URL, options ...Option) *state.APIClient {
+	return state.NewAPIClient(newConfiguration(client, endpoint, options))
+}
+
+// NewAPIClient creates a new API client for the given endpoint and returns an error if an option cannot be applied.
+func NewAPIClient(client *http.Client, endpoint *url.URL, options ...Option) (*state.APIClient, error) {
+	config := newConfiguration(client, endpoint, options)
+	if config.HTTPClient != nil {
+		if err := transportError(config.HTTPClient.Transport); err != nil {
+			return nil, err
+		}
+	}
+	return state.NewAPIClient(config), nil
+}
+
+func newConfiguration(client *http.Client, endpoint *url.URL, options []Option) *state.Configuration {
 	config := state.NewConfiguration()
 	config.BasePath = endpoint.String()
 	config.Host = endpoint.Host
@@ -38,5 +55,5 @@ func GetAPIClient(client *http.Client, endpoint *url.URL, options ...Option) *st
 	for _, option := range options {
 		option(config)
 	}
-	return state.NewAPIClient(config)
+	return config
 }
@@ -88,6 +88,18 @@ func MustRbfsContext(ctx context.Context) *rbfsContext {
 	return &rbfsContext{Context: ctx}
 }
 
+// ElementName returns the element name of the given RBFS context or an empty string, if the context is not an
+// RBFS context.
+func ElementName(ctx context.Context) string {
+	elementName, _ := ctx.Value(elementNameKey).(string)
+	return elementName
+}
+
+// WithElement returns a copy of the given RBFS context to access another element managed by the same CTRLD instance.
+func WithElement(ctx RbfsContext, elementName string) RbfsContext {
+	return &rbfsContext{Context: context.WithValue(ctx, elementNameKey, elementName)}
+}
+
 func (r *rbfsContext) GetServiceEndpoint(serviceName ServiceName) (*url.URL, error) {
 	if serviceName == "" {
 		return nil, fmt.Errorf("empty service name is not supported")
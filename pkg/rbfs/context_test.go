@@ -81,6 +81,22 @@ func TestMustRbfsContext(t *testing.T) {
 	require.NotPanics(t, func() { MustRbfsContext(ctx) })
 }
 
+func TestWithElement(t *testing.T) {
+	ctx, err := NewRbfsContext(context.Background(), mustParse(t, "http://192.168.0.1"), "rtbrick", RbfsAccessToken("token"))
+	require.NoError(t, err)
+	require.Equal(t, "rtbrick", ElementName(ctx))
+
+	other := WithElement(ctx, "leaf01")
+	require.Equal(t, "leaf01", ElementName(other))
+	require.Equal(t, "rtbrick", ElementName(ctx))
+	require.Equal(t, "token", other.Value(state.ContextAccessToken))
+	u, err := other.GetServiceEndpoint(OpsdServiceName)
+	require.NoError(t, err)
+	require.Equal(t, mustParse(t, "http://192.168.0.1/api/v1/rbfs/elements/leaf01/services/opsd/proxy"), u)
+
+	require.Equal(t, "", ElementName(context.Background()))
+}
+
 func mustParse(t *testing.T, v string) *url.URL {
 	t.Helper()
 	u, err := url.Parse(v)
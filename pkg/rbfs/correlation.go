@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// CorrelationIDHeader holds the HTTP header carrying the correlation ID of a request.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+const correlationIDKey = contextKey("correlationID")
+
+// RbfsCorrelationID adds a correlation ID to an RBFS context. The ID is sent with every request of the context, if
+// the Logging or Tracing option is applied, and reported in the logs and spans. Without a correlation ID, every
+// request is assigned a new random ID.
+func RbfsCorrelationID(id string) RbfsContextOption {
+	return func(ctx context.Context) (context.Context, error) {
+		return WithCorrelationID(ctx, id), nil
+	}
+}
+
+// WithCorrelationID returns a copy of the given context carrying the given correlation ID,
+// e.g. the ID of an incoming request to be propagated to RBFS.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationID returns the correlation ID of the given context or an empty string, if the context does not
+// carry a correlation ID.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
+// withCorrelationID returns the correlation ID of the given request. The request is copied to add the correlation
+// ID header, if the header is not set yet.
+func withCorrelationID(req *http.Request) (*http.Request, string) {
+	if id := req.Header.Get(CorrelationIDHeader); id != "" {
+		return req, id
+	}
+	id := CorrelationID(req.Context())
+	if id == "" {
+		id = randomID(16)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(CorrelationIDHeader, id)
+	return req, id
+}
+
+// randomID returns a random hex encoded ID of the given number of bytes.
+func randomID(size int) string {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
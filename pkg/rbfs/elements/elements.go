@@ -49,6 +49,10 @@ type (
 		OperationalState OperationalState `json:"operational_state"`
 		// ZTPEnabled indicates whether RBFS pulls the startup configuration from the ZTP server after each reboot or not.
 		ZTPEnabled bool `json:"ztp_enabled"`
+		// Version holds the RBFS version running on the element, if reported by CTRLD.
+		// The attribute name is assumed and has not been confirmed against the CTRLD API specification.
+		// The version is empty if CTRLD reports the version under another name or not at all.
+		Version string `json:"version,omitempty"`
 	}
 
 	// Client provides access to the available elements.
@@ -65,6 +69,7 @@ type (
 )
 
 // NewClient creates a new client to query managed elements.
+// Create the HTTP client with rbfs.NewHTTPClient to apply TLS, logging or tracing options.
 func NewClient(c *http.Client) Client {
 	return &client{c}
 }
@@ -0,0 +1,304 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package features detects the RBFS version and the opsd API features supported by an element.
+// RBFS releases differ in the opsd endpoints they provide, e.g. older releases provide /optics instead of
+// /transceivers and no /streams endpoints.
+package features
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// The features are decided by the RBFS version of the element and probed only if the version is unknown.
+// The probes send GET requests to POST-only operations, which are rejected with 405 Method Not Allowed if the API
+// exists, so that probing neither reads the state of the element nor changes it.
+var (
+	// FeatureTransceivers describes the transceivers API (state.TransceiversApiService).
+	FeatureTransceivers = Feature{
+		Name:  "transceivers",
+		Since: MustParseVersion("21.10"),
+		Probe: "/transceivers/probe/disable",
+	}
+	// FeatureOptics describes the optics API of older RBFS releases (state.OpticsApiService).
+	// The optics API is deprecated in favour of the transceivers API, but has not been removed yet.
+	FeatureOptics = Feature{
+		Name:  "optics",
+		Since: MustParseVersion("20.4"),
+		Probe: "/optics/probe/disable",
+	}
+	// FeatureStreams describes the streaming ping and traceroute API (state.StreamsApiService).
+	FeatureStreams = Feature{
+		Name:  "streams",
+		Since: MustParseVersion("21.10"),
+		Probe: "/streams/ping",
+	}
+)
+
+type (
+	// Feature describes an opsd API feature not provided by all RBFS releases.
+	Feature struct {
+		// Name holds the feature name used in error messages and as cache key.
+		Name string
+		// Since holds the first RBFS version providing the feature. Zero if not known.
+		Since Version
+		// Until holds the first RBFS version no longer providing the feature. Zero if the feature has not been
+		// removed.
+		Until Version
+		// Probe holds the opsd path probed with a GET request, if the element version is unknown or the feature
+		// does not declare a version range.
+		Probe string
+	}
+
+	// VersionSource returns the RBFS version of the element addressed by the context.
+	// A zero version reports an unknown version.
+	VersionSource func(ctx rbfs.RbfsContext) (Version, error)
+
+	// UnsupportedError reports a feature not supported by an element.
+	UnsupportedError struct {
+		Feature string
+		Element string
+		// Version holds the RBFS version of the element. Zero if not known.
+		Version Version
+	}
+
+	// Client detects the RBFS version and the features of elements. The results are cached per element.
+	Client interface {
+		// Version returns the RBFS version of the element addressed by the context.
+		// A zero version is returned if the version is unknown.
+		Version(ctx rbfs.RbfsContext) (Version, error)
+		// Supports reports whether the element addressed by the context supports the given feature.
+		// The decision is made by the element version, if known and the feature declares a version range,
+		// and by probing the element otherwise.
+		Supports(ctx rbfs.RbfsContext, feature Feature) (bool, error)
+		// Require returns an *UnsupportedError if the element addressed by the context does not support the given
+		// feature.
+		Require(ctx rbfs.RbfsContext, feature Feature) error
+		// Forget drops the cached version and features of the element addressed by the context,
+		// e.g. after an upgrade.
+		Forget(ctx rbfs.RbfsContext)
+	}
+
+	// Option applies an optional client setting.
+	Option func(*client)
+
+	client struct {
+		rbfs          *http.Client
+		versionSource VersionSource
+		ttl           time.Duration
+
+		mu       sync.Mutex
+		elements map[string]*element
+	}
+
+	// element holds the cached version and features of an element.
+	element struct {
+		version      Version
+		versionKnown bool
+		features     map[string]bool
+		expires      time.Time
+	}
+)
+
+func (e *UnsupportedError) Error() string {
+	if e.Version.IsZero() {
+		return fmt.Sprintf("%s is not supported on element %s (RBFS version unknown)", e.Feature, e.Element)
+	}
+	return fmt.Sprintf("%s is not supported on element %s running RBFS %s", e.Feature, e.Element, e.Version)
+}
+
+// VersionFrom obtains the RBFS version of the elements from the given source.
+// By default, the version is read from the element information reported by CTRLD (see ElementInfoVersion).
+func VersionFrom(source VersionSource) Option {
+	return func(c *client) {
+		c.versionSource = source
+	}
+}
+
+// ElementInfoVersion returns a version source reading the RBFS version from the element information reported by
+// CTRLD. The version is unknown if CTRLD does not report it in the assumed version attribute (see elements.Element),
+// in which case the features are probed. Use VersionFrom to obtain the version from another source.
+func ElementInfoVersion(c elements.Client) VersionSource {
+	return func(ctx rbfs.RbfsContext) (Version, error) {
+		element, err := c.GetElement(ctx, rbfs.ElementName(ctx))
+		if err != nil {
+			return Version{}, err
+		}
+		if element.Version == "" {
+			return Version{}, nil
+		}
+		return ParseVersion(element.Version)
+	}
+}
+
+// CacheTTL limits the time the version and features of an element are cached.
+// By default, the results are cached until the element is forgotten.
+func CacheTTL(ttl time.Duration) Option {
+	return func(c *client) {
+		c.ttl = ttl
+	}
+}
+
+// NewClient creates a new client to detect the version and features of elements.
+func NewClient(c *http.Client, options ...Option) Client {
+	fc := &client{
+		rbfs:          c,
+		versionSource: ElementInfoVersion(elements.NewClient(c)),
+		elements:      make(map[string]*element),
+	}
+	for _, option := range options {
+		option(fc)
+	}
+	return fc
+}
+
+func (c *client) Version(ctx rbfs.RbfsContext) (Version, error) {
+	key, err := cacheKey(ctx)
+	if err != nil {
+		return Version{}, err
+	}
+	c.mu.Lock()
+	e := c.element(key)
+	version, known := e.version, e.versionKnown
+	c.mu.Unlock()
+	if known {
+		return version, nil
+	}
+
+	version, err = c.versionSource(ctx)
+	if err != nil {
+		return Version{}, fmt.Errorf("cannot obtain RBFS version of element %s: %w", rbfs.ElementName(ctx), err)
+	}
+	c.mu.Lock()
+	e = c.element(key)
+	e.version, e.versionKnown = version, true
+	c.mu.Unlock()
+	return version, nil
+}
+
+func (c *client) Supports(ctx rbfs.RbfsContext, feature Feature) (bool, error) {
+	key, err := cacheKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	supported, ok := c.element(key).features[feature.Name]
+	c.mu.Unlock()
+	if ok {
+		return supported, nil
+	}
+
+	supported, err = c.detect(ctx, feature)
+	if err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	c.element(key).features[feature.Name] = supported
+	c.mu.Unlock()
+	return supported, nil
+}
+
+func (c *client) Require(ctx rbfs.RbfsContext, feature Feature) error {
+	supported, err := c.Supports(ctx, feature)
+	if err != nil || supported {
+		return err
+	}
+	// The version is informational only
+	version, _ := c.Version(ctx)
+	return &UnsupportedError{Feature: feature.Name, Element: rbfs.ElementName(ctx), Version: version}
+}
+
+func (c *client) Forget(ctx rbfs.RbfsContext) {
+	key, err := cacheKey(ctx)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.elements, key)
+}
+
+// element returns the cache entry of the given element. The caller must hold the lock.
+func (c *client) element(key string) *element {
+	e, ok := c.elements[key]
+	if ok && (e.expires.IsZero() || time.Now().Before(e.expires)) {
+		return e
+	}
+	e = &element{features: make(map[string]bool)}
+	if c.ttl > 0 {
+		e.expires = time.Now().Add(c.ttl)
+	}
+	c.elements[key] = e
+	return e
+}
+
+// detect decides by the element version, if possible, and probes the element otherwise.
+func (c *client) detect(ctx rbfs.RbfsContext, feature Feature) (bool, error) {
+	if !feature.Since.IsZero() || !feature.Until.IsZero() {
+		version, err := c.Version(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !version.IsZero() {
+			return (feature.Since.IsZero() || version.Compare(feature.Since) >= 0) &&
+				(feature.Until.IsZero() || version.Compare(feature.Until) < 0), nil
+		}
+	}
+	if feature.Probe == "" {
+		return false, fmt.Errorf("cannot detect %s support of element %s: RBFS version unknown", feature.Name, rbfs.ElementName(ctx))
+	}
+	return c.probe(ctx, feature)
+}
+
+// probe sends a GET request to the probe path of the feature. The feature is supported if the path exists, i.e.
+// the request is not rejected with 404 Not Found or 501 Not Implemented.
+func (c *client) probe(ctx rbfs.RbfsContext, feature Feature) (bool, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return false, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.JoinPath(feature.Probe).String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	if accessToken, ok := ctx.Value(state.ContextAccessToken).(string); ok {
+		request.Header.Add("Authorization", "Bearer "+accessToken)
+	}
+
+	response, err := c.rbfs.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300, response.StatusCode == http.StatusMethodNotAllowed:
+		return true, nil
+	case response.StatusCode == http.StatusNotFound, response.StatusCode == http.StatusNotImplemented:
+		return false, nil
+	default:
+		return false, fmt.Errorf("cannot probe %s support of element %s: %s", feature.Name, rbfs.ElementName(ctx), response.Status)
+	}
+}
+
+// cacheKey identifies the element addressed by the context.
+func cacheKey(ctx rbfs.RbfsContext) (string, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return "", err
+	}
+	return endpoint.String(), nil
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package features
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfstest"
+)
+
+// newTestServer starts a fake CTRLD with a current element (leaf1), an element of an older RBFS release without
+// transceivers and streams API (leaf2) and an element supporting neither transceivers nor optics API (leaf3).
+// CTRLD does not report the RBFS version of leaf3.
+func newTestServer(t *testing.T) *rbfstest.Server {
+	s := rbfstest.NewServer(rbfstest.Samples())
+	t.Cleanup(s.Close)
+	for name, version := range map[string]string{"leaf1": "22.1.2", "leaf2": "21.6.1", "leaf3": ""} {
+		s.AddElement(elements.Element{ElementName: name, Version: version})
+	}
+	for _, fault := range []rbfstest.Fault{
+		{Element: "leaf2", Service: rbfs.OpsdServiceName, Path: "/transceivers"},
+		{Element: "leaf2", Service: rbfs.OpsdServiceName, Path: "/streams"},
+		{Element: "leaf3", Service: rbfs.OpsdServiceName, Path: "/transceivers"},
+		{Element: "leaf3", Service: rbfs.OpsdServiceName, Path: "/optics"},
+	} {
+		fault.StatusCode = http.StatusNotFound
+		s.Inject(fault)
+	}
+	return s
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    Version
+		wantErr bool
+	}{
+		{version: "21.6.1", want: Version{21, 6, 1}},
+		{version: "v22.1", want: Version{22, 1, 0}},
+		{version: "21.10.0-rc2", want: Version{21, 10, 0}},
+		{version: "21", wantErr: true},
+		{version: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := ParseVersion(tt.version)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	require.Equal(t, -1, MustParseVersion("21.6.1").Compare(MustParseVersion("21.10.0")))
+	require.Equal(t, 0, MustParseVersion("21.6").Compare(MustParseVersion("21.6.0")))
+	require.Equal(t, 1, MustParseVersion("22.1.0").Compare(MustParseVersion("21.12.3")))
+	require.Equal(t, "21.6.0", MustParseVersion("v21.6").String())
+}
+
+func TestClient_Supports(t *testing.T) {
+	s := newTestServer(t)
+	c := NewClient(s.Client())
+
+	tests := []struct {
+		element string
+		feature Feature
+		want    bool
+	}{
+		{element: "leaf1", feature: FeatureTransceivers, want: true},
+		{element: "leaf1", feature: FeatureOptics, want: true},
+		{element: "leaf1", feature: FeatureStreams, want: true},
+		{element: "leaf2", feature: FeatureTransceivers, want: false},
+		{element: "leaf2", feature: FeatureOptics, want: true},
+		{element: "leaf2", feature: FeatureStreams, want: false},
+		{element: "leaf3", feature: FeatureTransceivers, want: false},
+		{element: "leaf3", feature: FeatureOptics, want: false},
+		{element: "leaf3", feature: FeatureStreams, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.element+"/"+tt.feature.Name, func(t *testing.T) {
+			supported, err := c.Supports(s.Context(context.Background(), tt.element), tt.feature)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, supported)
+		})
+	}
+
+	// The results are cached per element
+	remove := s.Inject(rbfstest.Fault{Element: "leaf1", StatusCode: http.StatusInternalServerError})
+	defer remove()
+	ctx := s.Context(context.Background(), "leaf1")
+	supported, err := c.Supports(ctx, FeatureTransceivers)
+	require.NoError(t, err)
+	require.True(t, supported)
+
+	// Failed probes are reported and not cached
+	ctx = s.Context(context.Background(), "leaf3")
+	c.Forget(ctx)
+	remove = s.Inject(rbfstest.Fault{Element: "leaf3", Service: rbfs.OpsdServiceName, Path: "/streams", StatusCode: http.StatusInternalServerError})
+	_, err = c.Supports(ctx, FeatureStreams)
+	require.EqualError(t, err, "cannot probe streams support of element leaf3: 500 Internal Server Error")
+	remove()
+	supported, err = c.Supports(ctx, FeatureStreams)
+	require.NoError(t, err)
+	require.True(t, supported)
+}
+
+func TestClient_SupportsByVersion(t *testing.T) {
+	s := newTestServer(t)
+	c := NewClient(s.Client())
+
+	// Elements of a known version are not probed
+	for _, element := range []string{"leaf1", "leaf2"} {
+		s.Inject(rbfstest.Fault{Element: element, Service: rbfs.OpsdServiceName, StatusCode: http.StatusInternalServerError})
+	}
+	for _, feature := range []Feature{FeatureTransceivers, FeatureOptics, FeatureStreams} {
+		supported, err := c.Supports(s.Context(context.Background(), "leaf1"), feature)
+		require.NoError(t, err)
+		require.True(t, supported, feature.Name)
+	}
+	supported, err := c.Supports(s.Context(context.Background(), "leaf2"), FeatureTransceivers)
+	require.NoError(t, err)
+	require.False(t, supported)
+}
+
+func TestClient_SupportsByProbe(t *testing.T) {
+	s := newTestServer(t)
+	c := NewClient(s.Client(), VersionFrom(func(rbfs.RbfsContext) (Version, error) { return Version{}, nil }))
+
+	for _, feature := range []Feature{FeatureTransceivers, FeatureOptics, FeatureStreams} {
+		supported, err := c.Supports(s.Context(context.Background(), "leaf1"), feature)
+		require.NoError(t, err)
+		require.True(t, supported, feature.Name)
+		supported, err = c.Supports(s.Context(context.Background(), "leaf3"), feature)
+		require.NoError(t, err)
+		require.Equal(t, feature.Name == "streams", supported, feature.Name)
+	}
+}
+
+func TestClient_Version(t *testing.T) {
+	s := newTestServer(t)
+	versions := map[string]string{"leaf1": "22.1.2", "leaf2": "21.6.1"}
+	lookups := 0
+	c := NewClient(s.Client(), VersionFrom(func(ctx rbfs.RbfsContext) (Version, error) {
+		lookups++
+		version, ok := versions[rbfs.ElementName(ctx)]
+		if !ok {
+			return Version{}, nil
+		}
+		return ParseVersion(version)
+	}))
+	feature := Feature{Name: "widgets", Since: MustParseVersion("22.1")}
+
+	version, err := c.Version(s.Context(context.Background(), "leaf1"))
+	require.NoError(t, err)
+	require.Equal(t, Version{22, 1, 2}, version)
+	require.NoError(t, c.Require(s.Context(context.Background(), "leaf1"), feature))
+	require.Equal(t, 1, lookups)
+
+	err = c.Require(s.Context(context.Background(), "leaf2"), feature)
+	var unsupported *UnsupportedError
+	require.True(t, errors.As(err, &unsupported))
+	require.Equal(t, UnsupportedError{Feature: "widgets", Element: "leaf2", Version: Version{21, 6, 1}}, *unsupported)
+	require.EqualError(t, err, "widgets is not supported on element leaf2 running RBFS 21.6.1")
+
+	// Features are probed if the version is unknown
+	err = c.Require(s.Context(context.Background(), "leaf3"), feature)
+	require.EqualError(t, err, "cannot detect widgets support of element leaf3: RBFS version unknown")
+	err = c.Require(s.Context(context.Background(), "leaf3"), FeatureOptics)
+	require.EqualError(t, err, "optics is not supported on element leaf3 (RBFS version unknown)")
+}
+
+func TestElementInfoVersion(t *testing.T) {
+	s := newTestServer(t)
+	c := NewClient(s.Client())
+	feature := Feature{Name: "widgets", Since: MustParseVersion("22.1")}
+
+	version, err := c.Version(s.Context(context.Background(), "leaf1"))
+	require.NoError(t, err)
+	require.Equal(t, Version{22, 1, 2}, version)
+	require.NoError(t, c.Require(s.Context(context.Background(), "leaf1"), feature))
+	err = c.Require(s.Context(context.Background(), "leaf2"), feature)
+	require.EqualError(t, err, "widgets is not supported on element leaf2 running RBFS 21.6.1")
+
+	version, err = c.Version(s.Context(context.Background(), "leaf3"))
+	require.NoError(t, err)
+	require.True(t, version.IsZero())
+	err = c.Require(s.Context(context.Background(), "leaf3"), feature)
+	require.EqualError(t, err, "cannot detect widgets support of element leaf3: RBFS version unknown")
+}
+
+func TestTransceiverService(t *testing.T) {
+	s := newTestServer(t)
+	service := NewTransceiverService(s.Client(), NewClient(s.Client()))
+
+	for _, element := range []string{"leaf1", "leaf2"} {
+		t.Run(element, func(t *testing.T) {
+			ctx := s.Context(context.Background(), element)
+			transceivers, err := service.GetTransceivers(ctx)
+			require.NoError(t, err)
+			require.Len(t, transceivers, 1)
+			require.Equal(t, "ifp-0/0/1", transceivers[0].IfpName)
+			require.Len(t, transceivers[0].Lanes, 1)
+
+			transceiver, err := service.GetTransceiver(ctx, "ifp-0/0/1")
+			require.NoError(t, err)
+			require.Equal(t, "ifp-0/0/1", transceiver.IfpName)
+			require.NoError(t, service.EnableLaser(ctx, "ifp-0/0/1"))
+			require.NoError(t, service.DisableLaser(ctx, "ifp-0/0/1"))
+		})
+	}
+
+	err := service.EnableLaser(s.Context(context.Background(), "leaf3"), "ifp-0/0/1")
+	var unsupported *UnsupportedError
+	require.True(t, errors.As(err, &unsupported))
+	require.Equal(t, "transceivers", unsupported.Feature)
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package features
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// These function variables we need to be able to mock the API Services
+var (
+	getTransceiversAPIFunc = getTransceiversAPI
+	getOpticsAPIFunc       = getOpticsAPI
+)
+
+type (
+	// TransceiverService accesses the transceivers of an element using the transceivers API, if supported by the
+	// element, and the optics API of older RBFS releases otherwise. An *UnsupportedError is returned if the
+	// element supports neither API.
+	TransceiverService interface {
+		// GetTransceivers returns all attached transceivers.
+		GetTransceivers(ctx rbfs.RbfsContext) ([]state.Transceiver, error)
+		// GetTransceiver returns the transceiver of the given physical interface.
+		GetTransceiver(ctx rbfs.RbfsContext, ifpName string) (state.Transceiver, error)
+		// EnableLaser enables the laser of the transceiver of the given physical interface.
+		EnableLaser(ctx rbfs.RbfsContext, ifpName string) error
+		// DisableLaser disables the laser of the transceiver of the given physical interface.
+		DisableLaser(ctx rbfs.RbfsContext, ifpName string) error
+	}
+
+	transceiverService struct {
+		client   *http.Client
+		features Client
+	}
+)
+
+// NewTransceiverService creates a new transceiver service, which selects the API by the given feature client.
+func NewTransceiverService(client *http.Client, features Client) TransceiverService {
+	return &transceiverService{client: client, features: features}
+}
+
+func (s *transceiverService) GetTransceivers(ctx rbfs.RbfsContext) ([]state.Transceiver, error) {
+	transceivers, optics, err := s.getAPI(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if transceivers != nil {
+		//nolint:bodyclose //generated code
+		r, _, err := transceivers.GetTransceivers(ctx)
+		return r, err
+	}
+
+	//nolint:bodyclose //generated code
+	list, _, err := optics.GetOptics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]state.Transceiver, 0, len(list))
+	for _, optic := range list {
+		r = append(r, transceiverOfOptic(optic))
+	}
+	return r, nil
+}
+
+func (s *transceiverService) GetTransceiver(ctx rbfs.RbfsContext, ifpName string) (state.Transceiver, error) {
+	transceivers, optics, err := s.getAPI(ctx)
+	if err != nil {
+		return state.Transceiver{}, err
+	}
+	if transceivers != nil {
+		//nolint:bodyclose //generated code
+		r, _, err := transceivers.GetTransceiver(ctx, ifpName)
+		return r, err
+	}
+
+	//nolint:bodyclose //generated code
+	optic, _, err := optics.GetOptic(ctx, ifpName)
+	if err != nil {
+		return state.Transceiver{}, err
+	}
+	return transceiverOfOptic(optic), nil
+}
+
+func (s *transceiverService) EnableLaser(ctx rbfs.RbfsContext, ifpName string) error {
+	transceivers, optics, err := s.getAPI(ctx)
+	if err != nil {
+		return err
+	}
+	if transceivers != nil {
+		//nolint:bodyclose //generated code
+		_, err = transceivers.EnableTransceiverLaser(ctx, ifpName)
+		return err
+	}
+	//nolint:bodyclose //generated code
+	_, err = optics.EnableLaser(ctx, ifpName)
+	return err
+}
+
+func (s *transceiverService) DisableLaser(ctx rbfs.RbfsContext, ifpName string) error {
+	transceivers, optics, err := s.getAPI(ctx)
+	if err != nil {
+		return err
+	}
+	if transceivers != nil {
+		//nolint:bodyclose //generated code
+		_, err = transceivers.DisableTransceiverLaser(ctx, ifpName)
+		return err
+	}
+	//nolint:bodyclose //generated code
+	_, err = optics.DisableLaser(ctx, ifpName)
+	return err
+}
+
+// getAPI returns either the transceivers API or the optics API, depending on the features of the element.
+func (s *transceiverService) getAPI(ctx rbfs.RbfsContext) (state.TransceiversApi, state.OpticsApi, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	supported, err := s.features.Supports(ctx, FeatureTransceivers)
+	if err != nil {
+		return nil, nil, err
+	}
+	if supported {
+		api, err := getTransceiversAPIFunc(s.client, endpoint)
+		return api, nil, err
+	}
+
+	supported, err = s.features.Supports(ctx, FeatureOptics)
+	if err != nil {
+		return nil, nil, err
+	}
+	if supported {
+		api, err := getOpticsAPIFunc(s.client, endpoint)
+		return nil, api, err
+	}
+	return nil, nil, s.features.Require(ctx, FeatureTransceivers)
+}
+
+func getTransceiversAPI(c *http.Client, endpoint *url.URL) (state.TransceiversApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.TransceiversApi, nil
+}
+
+func getOpticsAPI(c *http.Client, endpoint *url.URL) (state.OpticsApi, error) {
+	client := rbfs.GetAPIClient(c, endpoint)
+	return client.OpticsApi, nil
+}
+
+// transceiverOfOptic converts an optic of the optics API into a transceiver. The optics API reports alarm and
+// warning thresholds instead of alarm and warning states, which are not converted.
+func transceiverOfOptic(optic state.Optic) state.Transceiver {
+	transceiver := state.Transceiver{
+		IfpName:          optic.IfpName,
+		SerialNumber:     optic.SerialNumber,
+		PartNumber:       optic.PartNumber,
+		MaterialNumber:   optic.MaterialNumber,
+		VendorName:       optic.VendorName,
+		Wavelength:       optic.Wavelength,
+		TransceiverType:  optic.OpticsType,
+		ConnectorType:    optic.ConnectorType,
+		PowerClass:       optic.PowerClass,
+		PowerClassState:  optic.PowerClassState,
+		PowerClassMode:   optic.PowerClassMode,
+		DateManufactured: optic.DateManufactured,
+		VoltageV:         optic.VoltageV,
+		TemperatureC:     optic.TemperatureC,
+	}
+	for _, lane := range optic.Lanes {
+		transceiverLane := state.TransceiverLane{LaneId: lane.LaneId}
+		if lane.Tx != nil {
+			transceiverLane.Tx = &state.TransceiverLaneTx{
+				LosAlert:      lane.Tx.LosAlert,
+				PowerMw:       lane.Tx.PowerMw,
+				PowerDbm:      lane.Tx.PowerDbm,
+				BiasCurrentMa: lane.Tx.BiasCurrentMa,
+			}
+		}
+		if lane.Rx != nil {
+			transceiverLane.Rx = &state.TransceiverLaneRx{
+				LosAlert: lane.Rx.LosAlert,
+				PowerMw:  lane.Rx.PowerMw,
+				PowerDbm: lane.Rx.PowerDbm,
+			}
+		}
+		transceiver.Lanes = append(transceiver.Lanes, transceiverLane)
+	}
+	return transceiver
+}
@@ -0,0 +1,295 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+const (
+	// CircuitClosed reports an element accepting requests.
+	CircuitClosed = CircuitState("closed")
+	// CircuitOpen reports an element, whose requests are rejected after consecutive failures.
+	CircuitOpen = CircuitState("open")
+	// CircuitHalfOpen reports an element, which accepts a single probe request to decide whether the circuit is
+	// closed again.
+	CircuitHalfOpen = CircuitState("half_open")
+)
+
+// ErrCircuitOpen is reported by requests rejected by an open circuit. Use errors.Is to detect a *CircuitOpenError.
+var ErrCircuitOpen = errors.New("circuit open")
+
+type (
+	// CircuitState describes the state of the circuit breaker of an element.
+	CircuitState string
+
+	// CircuitOpenError reports a request rejected, because the circuit of the element is open.
+	CircuitOpenError struct {
+		Element string
+		// RetryAt holds the time the circuit accepts a probe request.
+		RetryAt time.Time
+	}
+
+	// ElementState describes the rate limit and circuit breaker state of an element for monitoring.
+	ElementState struct {
+		Element string
+		Circuit CircuitState
+		// ConsecutiveFailures holds the number of failed requests since the last successful request.
+		ConsecutiveFailures int
+		// RetryAt holds the time an open circuit accepts a probe request.
+		RetryAt time.Time
+		// Tokens holds the number of requests, which can be sent without delay.
+		Tokens float64
+	}
+
+	// ElementGuard limits the request rate per element and stops sending requests to elements failing
+	// consecutively. Elements are identified by the element name of the RBFS context of the requests. An
+	// ElementGuard can be shared by multiple API clients.
+	ElementGuard struct {
+		rate        float64
+		burst       int
+		failures    int
+		openTimeout time.Duration
+
+		mu       sync.Mutex
+		elements map[string]*elementGuard
+	}
+
+	// GuardOption applies an optional element guard setting.
+	GuardOption func(*ElementGuard)
+
+	elementGuard struct {
+		tokens   float64
+		refilled time.Time
+
+		circuit  CircuitState
+		failures int
+		retryAt  time.Time
+		// probing indicates a probe request in flight in half-open state.
+		probing bool
+	}
+
+	// guardTransport applies an element guard to all requests.
+	guardTransport struct {
+		next     http.RoundTripper
+		guard    *ElementGuard
+		basePath string
+	}
+)
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit of element %s is open until %s", e.Element, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is reports whether the target is ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// RateLimit limits the requests per element to the given rate per second with bursts of the given size.
+// Requests exceeding the rate are delayed. By default, the request rate is not limited.
+func RateLimit(rate float64, burst int) GuardOption {
+	return func(g *ElementGuard) {
+		g.rate = rate
+		g.burst = burst
+	}
+}
+
+// CircuitBreaker opens the circuit of an element after the given number of consecutive failures. Requests to an
+// element with open circuit are rejected with a *CircuitOpenError. After the given timeout, a single probe request
+// is sent to the element, which closes the circuit on success and opens it again on failure. Failures are
+// timeouts, transport errors other than cancellations and 502, 503 and 504 responses. By default, the circuit is
+// never opened.
+func CircuitBreaker(failures int, openTimeout time.Duration) GuardOption {
+	return func(g *ElementGuard) {
+		g.failures = failures
+		g.openTimeout = openTimeout
+	}
+}
+
+// NewElementGuard creates a new element guard.
+func NewElementGuard(options ...GuardOption) *ElementGuard {
+	g := &ElementGuard{elements: make(map[string]*elementGuard)}
+	for _, option := range options {
+		option(g)
+	}
+	return g
+}
+
+// Guard returns an option to apply the given element guard to all requests.
+func Guard(guard *ElementGuard) Option {
+	return func(c *state.Configuration) {
+		wrapTransport(c, func(next http.RoundTripper) http.RoundTripper {
+			return &guardTransport{next: next, guard: guard, basePath: basePath(c)}
+		})
+	}
+}
+
+// State returns the state of the given element.
+func (g *ElementGuard) State(elementName string) ElementState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state(elementName, g.get(elementName), time.Now())
+}
+
+// States returns the states of all elements requested so far, sorted by element name.
+func (g *ElementGuard) States() []ElementState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	states := make([]ElementState, 0, len(g.elements))
+	for name, e := range g.elements {
+		states = append(states, g.state(name, e, now))
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Element < states[j].Element
+	})
+	return states
+}
+
+// state returns the state of the given element. The caller must hold the lock.
+func (g *ElementGuard) state(elementName string, e *elementGuard, now time.Time) ElementState {
+	g.refill(e, now)
+	s := ElementState{
+		Element:             elementName,
+		Circuit:             e.circuit,
+		ConsecutiveFailures: e.failures,
+		Tokens:              e.tokens,
+	}
+	if e.circuit == CircuitOpen {
+		s.RetryAt = e.retryAt
+	}
+	if g.rate <= 0 {
+		s.Tokens = math.Inf(1)
+	}
+	return s
+}
+
+// get returns the state of the given element. The caller must hold the lock.
+func (g *ElementGuard) get(elementName string) *elementGuard {
+	e, ok := g.elements[elementName]
+	if !ok {
+		e = &elementGuard{tokens: float64(g.burst), refilled: time.Now(), circuit: CircuitClosed}
+		g.elements[elementName] = e
+	}
+	return e
+}
+
+// refill adds the tokens accrued since the last refill. The caller must hold the lock.
+func (g *ElementGuard) refill(e *elementGuard, now time.Time) {
+	if g.rate <= 0 {
+		return
+	}
+	e.tokens = math.Min(float64(g.burst), e.tokens+now.Sub(e.refilled).Seconds()*g.rate)
+	e.refilled = now
+}
+
+// admit decides whether a request may be sent to the given element and returns the delay imposed by the rate
+// limit and whether the request probes a half-open circuit.
+func (g *ElementGuard) admit(elementName string) (time.Duration, bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	e := g.get(elementName)
+
+	probe := false
+	switch e.circuit {
+	case CircuitOpen:
+		if now.Before(e.retryAt) {
+			return 0, false, &CircuitOpenError{Element: elementName, RetryAt: e.retryAt}
+		}
+		e.circuit = CircuitHalfOpen
+		e.probing, probe = true, true
+	case CircuitHalfOpen:
+		if e.probing {
+			return 0, false, &CircuitOpenError{Element: elementName, RetryAt: now.Add(g.openTimeout)}
+		}
+		e.probing, probe = true, true
+	}
+
+	if g.rate <= 0 {
+		return 0, probe, nil
+	}
+	g.refill(e, now)
+	e.tokens--
+	if e.tokens >= 0 {
+		return 0, probe, nil
+	}
+	return time.Duration(-e.tokens / g.rate * float64(time.Second)), probe, nil
+}
+
+// release returns the token of a request canceled while delayed by the rate limit.
+func (g *ElementGuard) release(elementName string, probe bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	e := g.get(elementName)
+	if g.rate > 0 {
+		e.tokens++
+	}
+	if probe {
+		e.probing = false
+	}
+}
+
+// complete updates the circuit of the given element with the outcome of a request.
+func (g *ElementGuard) complete(elementName string, probe bool, resp *http.Response, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	e := g.get(elementName)
+	if probe {
+		e.probing = false
+	}
+
+	class := classify(resp, err)
+	switch {
+	case class == ErrorClassCanceled:
+		// Cancellations do not tell anything about the element
+	case class == ErrorClassTimeout || class == ErrorClassNetwork || err == nil && isGatewayError(resp.StatusCode):
+		e.failures++
+		if g.failures > 0 && (probe || e.failures >= g.failures) {
+			e.circuit = CircuitOpen
+			e.retryAt = time.Now().Add(g.openTimeout)
+		}
+	default:
+		e.failures = 0
+		e.circuit = CircuitClosed
+	}
+}
+
+func isGatewayError(statusCode int) bool {
+	return statusCode == http.StatusBadGateway || statusCode == http.StatusServiceUnavailable ||
+		statusCode == http.StatusGatewayTimeout
+}
+
+func (t *guardTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	elementName := describeCall(req, t.basePath).element
+	delay, probe, err := t.guard.admit(elementName)
+	if err != nil {
+		return nil, err
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			t.guard.release(elementName, probe)
+			return nil, req.Context().Err()
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	t.guard.complete(elementName, probe, resp, err)
+	return resp, err
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// statusServer answers all requests with the current status and counts the requests.
+func statusServer(t *testing.T, status *atomic.Int32, requests *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(int(status.Load()))
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func guardedClient(t *testing.T, server *httptest.Server, elementName string, guard *ElementGuard) (RbfsContext, *state.APIClient) {
+	ctx, err := NewRbfsContext(context.Background(), mustParse(t, server.URL), elementName)
+	require.NoError(t, err)
+	endpoint, err := ctx.GetServiceEndpoint(OpsdServiceName)
+	require.NoError(t, err)
+	return ctx, GetAPIClient(http.DefaultClient, endpoint, Guard(guard))
+}
+
+func TestGuard_CircuitBreaker(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := statusServer(t, &status, &requests)
+	guard := NewElementGuard(CircuitBreaker(2, 100*time.Millisecond))
+	leaf1, client1 := guardedClient(t, server, "leaf1", guard)
+	leaf2, client2 := guardedClient(t, server, "leaf2", guard)
+
+	for i := 0; i < 2; i++ {
+		_, _, err := client1.BGPApi.GetBGPInstances(leaf1)
+		require.Error(t, err)
+		require.False(t, errors.Is(err, ErrCircuitOpen))
+	}
+	_, _, err := client1.BGPApi.GetBGPInstances(leaf1)
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	var circuitErr *CircuitOpenError
+	require.True(t, errors.As(err, &circuitErr))
+	require.Equal(t, "leaf1", circuitErr.Element)
+	require.Equal(t, int32(2), requests.Load())
+
+	leaf1State := guard.State("leaf1")
+	require.Equal(t, CircuitOpen, leaf1State.Circuit)
+	require.Equal(t, 2, leaf1State.ConsecutiveFailures)
+	require.Equal(t, circuitErr.RetryAt, leaf1State.RetryAt)
+
+	// Other elements are not affected
+	_, _, err = client2.BGPApi.GetBGPInstances(leaf2)
+	require.False(t, errors.Is(err, ErrCircuitOpen))
+	require.Equal(t, int32(3), requests.Load())
+
+	// A failed probe opens the circuit again
+	time.Sleep(100 * time.Millisecond)
+	_, _, err = client1.BGPApi.GetBGPInstances(leaf1)
+	require.False(t, errors.Is(err, ErrCircuitOpen))
+	require.Equal(t, CircuitOpen, guard.State("leaf1").Circuit)
+	_, _, err = client1.BGPApi.GetBGPInstances(leaf1)
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+
+	// A successful probe closes the circuit
+	status.Store(http.StatusOK)
+	time.Sleep(100 * time.Millisecond)
+	_, _, err = client1.BGPApi.GetBGPInstances(leaf1)
+	require.NoError(t, err)
+	require.Equal(t, []ElementState{
+		{Element: "leaf1", Circuit: CircuitClosed, Tokens: math.Inf(1)},
+		{Element: "leaf2", Circuit: CircuitClosed, ConsecutiveFailures: 1, Tokens: math.Inf(1)},
+	}, guard.States())
+}
+
+func TestGuard_RateLimit(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusOK)
+	server := statusServer(t, &status, &requests)
+	guard := NewElementGuard(RateLimit(20, 1))
+	leaf1, client := guardedClient(t, server, "leaf1", guard)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, _, err := client.BGPApi.GetBGPInstances(leaf1)
+		require.NoError(t, err)
+	}
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// Requests canceled while delayed return their token
+	ctx, cancel := context.WithTimeout(leaf1, time.Millisecond)
+	defer cancel()
+	_, _, err := client.BGPApi.GetBGPInstances(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int32(3), requests.Load())
+	require.Less(t, guard.State("leaf1").Tokens, float64(1))
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/prometheus"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+const (
+	// ErrorClassTimeout reports a request that timed out.
+	ErrorClassTimeout = ErrorClass("timeout")
+	// ErrorClassCanceled reports a request canceled by the caller.
+	ErrorClassCanceled = ErrorClass("canceled")
+	// ErrorClassNetwork reports a request failed for other reasons, e.g. a refused connection.
+	ErrorClassNetwork = ErrorClass("network")
+	// ErrorClassClient reports a 4xx response.
+	ErrorClassClient = ErrorClass("client_error")
+	// ErrorClassServer reports a 5xx response.
+	ErrorClassServer = ErrorClass("server_error")
+
+	// unknownLabel replaces the service and operation of requests not matching any known operation.
+	unknownLabel = "unknown"
+)
+
+// DefaultBuckets holds the default upper bounds of the latency histogram buckets in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type (
+	// ErrorClass classifies failed requests and error responses. The class is empty for successful requests.
+	ErrorClass string
+
+	// RequestLabels describes the service, the operation and the element of a request.
+	RequestLabels struct {
+		// Service holds the service name (e.g. opsd, ctrld or prometheus) or unknown.
+		Service ServiceName
+		// Operation holds the opsd operation ID (e.g. getBGPInstances), the CTRLD operation (e.g. listElements), the
+		// path of other services or unknown.
+		Operation string
+		Element   string
+	}
+
+	// Collector receives the measurements of the requests of an instrumented HTTP client.
+	Collector interface {
+		// RequestStarted is invoked before a request is sent.
+		RequestStarted(labels RequestLabels)
+		// RequestCompleted is invoked when the response headers have been received or the request failed.
+		RequestCompleted(labels RequestLabels, class ErrorClass, duration time.Duration)
+	}
+
+	// ClientMetrics collects request counts, error counts by class, latency histograms and in-flight requests
+	// and exposes them in the Prometheus text exposition format.
+	ClientMetrics struct {
+		buckets []float64
+
+		mu     sync.Mutex
+		series map[RequestLabels]*requestSeries
+	}
+
+	requestSeries struct {
+		requests uint64
+		inFlight int64
+		errors   map[ErrorClass]uint64
+		// counts holds the number of observations per bucket, where the last bucket counts the observations
+		// greater than the highest upper bound.
+		counts []uint64
+		sum    float64
+	}
+
+	// instrumentedTransport reports the requests to a collector.
+	instrumentedTransport struct {
+		next      http.RoundTripper
+		collector Collector
+		basePath  string
+	}
+)
+
+// Metrics returns an option to report every request to the given collector, e.g. ClientMetrics. The latency
+// covers the time until the response headers have been received.
+func Metrics(collector Collector) Option {
+	return func(c *state.Configuration) {
+		wrapTransport(c, func(next http.RoundTripper) http.RoundTripper {
+			return &instrumentedTransport{next: next, collector: collector, basePath: basePath(c)}
+		})
+	}
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := describeCall(req, t.basePath)
+	labels := RequestLabels{Service: call.service, Operation: call.operation, Element: call.element}
+	if labels.Service == "" {
+		labels.Service = unknownLabel
+	}
+	if labels.Operation == "" {
+		labels.Operation = unknownLabel
+	}
+
+	t.collector.RequestStarted(labels)
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	t.collector.RequestCompleted(labels, classify(resp, err), time.Since(start))
+	return resp, err
+}
+
+// classify returns the error class of a request.
+func classify(resp *http.Response, err error) ErrorClass {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case err != nil:
+		return ErrorClassNetwork
+	case resp.StatusCode >= 500:
+		return ErrorClassServer
+	case resp.StatusCode >= 400:
+		return ErrorClassClient
+	default:
+		return ""
+	}
+}
+
+// NewClientMetrics creates a new collector with latency histograms of the given bucket upper bounds in seconds.
+// DefaultBuckets are used if no buckets are given.
+func NewClientMetrics(buckets ...float64) *ClientMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &ClientMetrics{
+		buckets: buckets,
+		series:  make(map[RequestLabels]*requestSeries),
+	}
+}
+
+// RequestStarted counts the request as in flight.
+func (m *ClientMetrics) RequestStarted(labels RequestLabels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(labels).inFlight++
+}
+
+// RequestCompleted counts the request and its error class and observes its latency.
+func (m *ClientMetrics) RequestCompleted(labels RequestLabels, class ErrorClass, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.get(labels)
+	s.inFlight--
+	s.requests++
+	if class != "" {
+		s.errors[class]++
+	}
+	seconds := duration.Seconds()
+	s.counts[sort.SearchFloat64s(m.buckets, seconds)]++
+	s.sum += seconds
+}
+
+// get returns the series of the given labels. The caller must hold the lock.
+func (m *ClientMetrics) get(labels RequestLabels) *requestSeries {
+	s, ok := m.series[labels]
+	if !ok {
+		s = &requestSeries{errors: make(map[ErrorClass]uint64), counts: make([]uint64, len(m.buckets)+1)}
+		m.series[labels] = s
+	}
+	return s
+}
+
+// WriteMetrics writes the collected metrics in the Prometheus text exposition format.
+func (m *ClientMetrics) WriteMetrics(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]RequestLabels, 0, len(m.series))
+	for labels := range m.series {
+		keys = append(keys, labels)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		return a.Element < b.Element
+	})
+
+	out := bufio.NewWriter(w)
+	prometheus.WriteHeader(out, "rbfs_client_requests_total", "counter", "Number of completed requests.")
+	for _, labels := range keys {
+		fmt.Fprintf(out, "rbfs_client_requests_total{%s} %d\n", requestLabels(labels), m.series[labels].requests)
+	}
+
+	prometheus.WriteHeader(out, "rbfs_client_errors_total", "counter", "Number of failed requests and error responses by error class.")
+	for _, labels := range keys {
+		s := m.series[labels]
+		classes := make([]string, 0, len(s.errors))
+		for class := range s.errors {
+			classes = append(classes, string(class))
+		}
+		sort.Strings(classes)
+		for _, class := range classes {
+			fmt.Fprintf(out, "rbfs_client_errors_total{%s,class=\"%s\"} %d\n", requestLabels(labels), class, s.errors[ErrorClass(class)])
+		}
+	}
+
+	prometheus.WriteHeader(out, "rbfs_client_requests_in_flight", "gauge", "Number of requests waiting for a response.")
+	for _, labels := range keys {
+		fmt.Fprintf(out, "rbfs_client_requests_in_flight{%s} %d\n", requestLabels(labels), m.series[labels].inFlight)
+	}
+
+	prometheus.WriteHeader(out, "rbfs_client_request_duration_seconds", "histogram", "Latency of the requests until the response headers have been received.")
+	for _, labels := range keys {
+		s := m.series[labels]
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(out, "rbfs_client_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				requestLabels(labels), prometheus.FormatValue(bound), cumulative)
+		}
+		cumulative += s.counts[len(m.buckets)]
+		fmt.Fprintf(out, "rbfs_client_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", requestLabels(labels), cumulative)
+		fmt.Fprintf(out, "rbfs_client_request_duration_seconds_sum{%s} %s\n", requestLabels(labels), prometheus.FormatValue(s.sum))
+		fmt.Fprintf(out, "rbfs_client_request_duration_seconds_count{%s} %d\n", requestLabels(labels), cumulative)
+	}
+	return out.Flush()
+}
+
+// Handler returns an HTTP handler serving the collected metrics.
+func (m *ClientMetrics) Handler() http.Handler {
+	return prometheus.Handler(m.WriteMetrics)
+}
+
+// requestLabels returns the label set of the given request labels.
+func requestLabels(labels RequestLabels) string {
+	return fmt.Sprintf(`service="%s",operation="%s",element="%s"`,
+		prometheus.EscapeLabel(string(labels.Service)), prometheus.EscapeLabel(labels.Operation),
+		prometheus.EscapeLabel(labels.Element))
+}
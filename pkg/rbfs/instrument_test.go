@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewClientMetrics(0.1, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/bgp/instances/missing") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	ctx, err := NewRbfsContext(context.Background(), mustParse(t, server.URL), "leaf1")
+	require.NoError(t, err)
+	endpoint, err := ctx.GetServiceEndpoint(OpsdServiceName)
+	require.NoError(t, err)
+
+	client := GetAPIClient(http.DefaultClient, endpoint, Metrics(metrics))
+	_, _, err = client.BGPApi.GetBGPInstance(ctx, "default")
+	require.NoError(t, err)
+	_, _, err = client.BGPApi.GetBGPInstance(ctx, "missing")
+	require.Error(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, metrics.WriteMetrics(&buf))
+	labels := `service="opsd",operation="getBGPInstance",element="leaf1"`
+	for _, line := range []string{
+		"# TYPE rbfs_client_requests_total counter",
+		"rbfs_client_requests_total{" + labels + "} 2",
+		"rbfs_client_errors_total{" + labels + `,class="client_error"} 1`,
+		"rbfs_client_requests_in_flight{" + labels + "} 0",
+		"# TYPE rbfs_client_request_duration_seconds histogram",
+		"rbfs_client_request_duration_seconds_bucket{" + labels + `,le="1"} 2`,
+		"rbfs_client_request_duration_seconds_bucket{" + labels + `,le="+Inf"} 2`,
+		"rbfs_client_request_duration_seconds_count{" + labels + "} 2",
+	} {
+		require.Contains(t, buf.String(), line+"\n")
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, buf.String(), recorder.Body.String())
+}
+
+func TestClientMetrics(t *testing.T) {
+	metrics := NewClientMetrics(0.1, 1)
+	labels := RequestLabels{Service: CtrldServiceName, Operation: "listElements", Element: `a"b`}
+	metrics.RequestStarted(labels)
+	metrics.RequestStarted(labels)
+	metrics.RequestCompleted(labels, ErrorClassTimeout, 5*time.Second)
+
+	var buf bytes.Buffer
+	require.NoError(t, metrics.WriteMetrics(&buf))
+	for _, line := range []string{
+		`rbfs_client_requests_in_flight{service="ctrld",operation="listElements",element="a\"b"} 1`,
+		`rbfs_client_errors_total{service="ctrld",operation="listElements",element="a\"b",class="timeout"} 1`,
+		`rbfs_client_request_duration_seconds_bucket{service="ctrld",operation="listElements",element="a\"b",le="1"} 0`,
+		`rbfs_client_request_duration_seconds_bucket{service="ctrld",operation="listElements",element="a\"b",le="+Inf"} 1`,
+		`rbfs_client_request_duration_seconds_sum{service="ctrld",operation="listElements",element="a\"b"} 5`,
+	} {
+		require.Contains(t, buf.String(), line+"\n")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		want   ErrorClass
+	}{
+		{name: "ok", status: http.StatusOK},
+		{name: "not found", status: http.StatusNotFound, want: ErrorClassClient},
+		{name: "unavailable", status: http.StatusServiceUnavailable, want: ErrorClassServer},
+		{name: "canceled", err: context.Canceled, want: ErrorClassCanceled},
+		{name: "deadline", err: context.DeadlineExceeded, want: ErrorClassTimeout},
+		{name: "network", err: errors.New("connection refused"), want: ErrorClassNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status, Body: io.NopCloser(strings.NewReader(""))}
+			}
+			require.Equal(t, tt.want, classify(resp, tt.err))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+const redacted = "REDACTED"
+
+var (
+	// redactedHeaders holds the request headers carrying credentials.
+	redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+	// redactedParams holds the query parameters carrying credentials.
+	redactedParams = []string{"access_token", "refresh_token", "id_token", "token", "password", "client_secret"}
+)
+
+// loggingTransport logs every request with its outcome.
+type loggingTransport struct {
+	next     http.RoundTripper
+	logger   *slog.Logger
+	basePath string
+}
+
+// Logging returns an option to log every request to the given logger. Each log record reports the method, the URL,
+// the service, the operation, the element, the correlation ID, the status and the duration of the request.
+// Successful requests are logged at info level, error responses at warn level and failed requests at error level.
+// The request headers are logged at debug level. Credentials are redacted from URLs and headers.
+func Logging(logger *slog.Logger) Option {
+	return func(c *state.Configuration) {
+		wrapTransport(c, func(next http.RoundTripper) http.RoundTripper {
+			return &loggingTransport{next: next, logger: logger, basePath: basePath(c)}
+		})
+	}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, correlationID := withCorrelationID(req)
+	call := describeCall(req, t.basePath)
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	ctx := req.Context()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.String("service", string(call.service)),
+		slog.String("operation", call.operation),
+		slog.String("element", call.element),
+		slog.String("correlation_id", correlationID),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if t.logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs, slog.Any("request_headers", redactHeader(req.Header)))
+	}
+	switch {
+	case err != nil:
+		attrs = append(attrs, slog.String("error", err.Error()))
+		t.logger.LogAttrs(ctx, slog.LevelError, "RBFS API request failed", attrs...)
+	case resp.StatusCode >= 400:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		t.logger.LogAttrs(ctx, slog.LevelWarn, "RBFS API request", attrs...)
+	default:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		t.logger.LogAttrs(ctx, slog.LevelInfo, "RBFS API request", attrs...)
+	}
+	return resp, err
+}
+
+// redactURL returns the given URL without password and credential query parameters.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for _, name := range redactedParams {
+		if _, ok := query[name]; ok {
+			query.Set(name, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		copied := *u
+		copied.RawQuery = query.Encode()
+		u = &copied
+	}
+	return u.Redacted()
+}
+
+// redactHeader returns a copy of the given header without credentials.
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
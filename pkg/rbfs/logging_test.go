@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// echoServer answers all requests with an empty JSON list and records the correlation ID and traceparent headers.
+func echoServer(t *testing.T, status int) (*httptest.Server, *http.Header) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &headers
+}
+
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var record map[string]interface{}
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogging(t *testing.T) {
+	server, headers := echoServer(t, http.StatusOK)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx, err := NewRbfsContext(context.Background(), mustParse(t, server.URL), "leaf1",
+		RbfsAccessToken("secret"), RbfsCorrelationID("job-42"))
+	require.NoError(t, err)
+	endpoint, err := ctx.GetServiceEndpoint(OpsdServiceName)
+	require.NoError(t, err)
+
+	client := GetAPIClient(http.DefaultClient, endpoint, Logging(logger))
+	_, _, err = client.BGPApi.GetBGPInstances(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "job-42", headers.Get(CorrelationIDHeader))
+
+	records := decodeLogRecords(t, &buf)
+	require.Len(t, records, 1)
+	record := records[0]
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "RBFS API request", record["msg"])
+	require.Equal(t, "GET", record["method"])
+	require.Equal(t, "opsd", record["service"])
+	require.Equal(t, "getBGPInstances", record["operation"])
+	require.Equal(t, "leaf1", record["element"])
+	require.Equal(t, "job-42", record["correlation_id"])
+	require.Equal(t, float64(http.StatusOK), record["status"])
+	require.Contains(t, record, "duration")
+	requestHeaders := record["request_headers"].(map[string]interface{})
+	require.Equal(t, []interface{}{redacted}, requestHeaders["Authorization"])
+	require.NotContains(t, buf.String(), "secret")
+}
+
+func TestLogging_Error(t *testing.T) {
+	server, headers := echoServer(t, http.StatusNotFound)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	ctx, err := NewRbfsContext(context.Background(), mustParse(t, server.URL), "leaf1")
+	require.NoError(t, err)
+
+	// The CTRLD clients use an HTTP client created by NewHTTPClient
+	httpClient, err := NewHTTPClient(Logging(logger))
+	require.NoError(t, err)
+	endpoint, err := ctx.GetCtrldElementEndpoint()
+	require.NoError(t, err)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String()+"?access_token=secret", nil)
+	require.NoError(t, err)
+	response, err := httpClient.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+
+	records := decodeLogRecords(t, &buf)
+	require.Len(t, records, 1)
+	record := records[0]
+	require.Equal(t, "WARN", record["level"])
+	require.Equal(t, "ctrld", record["service"])
+	require.Equal(t, "getElement", record["operation"])
+	require.Equal(t, server.URL+"/api/v1/ctrld/elements/leaf1?access_token=REDACTED", record["url"])
+	require.Equal(t, headers.Get(CorrelationIDHeader), record["correlation_id"])
+	require.Len(t, record["correlation_id"], 32)
+	require.NotContains(t, record, "request_headers")
+}
+
+func TestDescribeCall(t *testing.T) {
+	tests := []struct {
+		method   string
+		url      string
+		basePath string
+		want     apiCall
+	}{
+		{
+			method: http.MethodGet,
+			url:    "http://ctrld/api/v1/rbfs/elements/leaf1/services/opsd/proxy/interfaces/physicals/ifp-0/1/2",
+			want:   apiCall{service: OpsdServiceName, operation: "getPhysicalInterface", element: "leaf1"},
+		},
+		{
+			method: http.MethodGet,
+			url:    "http://ctrld/api/v1/rbfs/elements/leaf1/services/prometheus/proxy/api/v1/query?query=up",
+			want:   apiCall{service: PrometheusServiceName, operation: "/api/v1/query", element: "leaf1"},
+		},
+		{
+			method: http.MethodGet,
+			url:    "http://ctrld/api/v1/ctrld/elements",
+			want:   apiCall{service: CtrldServiceName, operation: "listElements"},
+		},
+		{
+			method: http.MethodGet,
+			url:    "http://ctrld/api/v1/ctrld/elements/leaf1/services",
+			want:   apiCall{service: CtrldServiceName, operation: "listServices", element: "leaf1"},
+		},
+		{
+			method:   http.MethodPost,
+			url:      "http://leaf1/api/v1/bgp/instances/default/peerings/192.0.2.1/clear",
+			basePath: "/api/v1",
+			want:     apiCall{service: OpsdServiceName, operation: "clearBGPPeering"},
+		},
+		{
+			method: http.MethodGet,
+			url:    "http://other/health",
+			want:   apiCall{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, describeCall(req, tt.basePath))
+		})
+	}
+}
@@ -0,0 +1,383 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package profile loads client configuration profiles describing how to reach a CTRLD instance and its elements.
+//
+// A profile file is a YAML or JSON document listing named profiles, similar to a kubeconfig file:
+//
+//	current-profile: lab
+//	profiles:
+//	  lab:
+//	    endpoint: https://ctrld.lab.example.net:19091
+//	    element: leaf01
+//	    elements: [leaf01, leaf02, spine01]
+//	    token-command: [rtb-token, --profile, lab]
+//	    tls:
+//	      ca-file: /etc/rbfs/ca.pem
+//	    timeout: 30s
+//
+// Settings of the selected profile can be overridden by environment variables, see the Env* constants.
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvConfig holds the path of the profile file.
+	EnvConfig = "RBFS_CONFIG"
+	// EnvProfile selects the profile to be used.
+	EnvProfile = "RBFS_PROFILE"
+	// EnvEndpoint overrides the CTRLD endpoint URL.
+	EnvEndpoint = "RBFS_ENDPOINT"
+	// EnvElement overrides the default element name.
+	EnvElement = "RBFS_ELEMENT"
+	// EnvAccessToken overrides the access token.
+	EnvAccessToken = "RBFS_ACCESS_TOKEN"
+	// EnvTimeout overrides the HTTP client timeout, e.g. 30s.
+	EnvTimeout = "RBFS_TIMEOUT"
+	// EnvCAFile overrides the CA bundle file.
+	EnvCAFile = "RBFS_CA_FILE"
+	// EnvCertFile overrides the client certificate file.
+	EnvCertFile = "RBFS_CERT_FILE"
+	// EnvKeyFile overrides the client key file.
+	EnvKeyFile = "RBFS_KEY_FILE"
+	// EnvServerName overrides the TLS server name.
+	EnvServerName = "RBFS_SERVER_NAME"
+	// EnvInsecureSkipVerify disables the server certificate verification if set to true.
+	EnvInsecureSkipVerify = "RBFS_INSECURE_SKIP_VERIFY"
+
+	// defaultProfileName is the name of the profile created from environment variables only.
+	defaultProfileName = "default"
+)
+
+type (
+	// Config describes all profiles of a profile file.
+	Config struct {
+		// CurrentProfile holds the name of the profile used if no profile is explicitly selected.
+		CurrentProfile string `yaml:"current-profile"`
+		// Profiles holds all profiles by name.
+		Profiles map[string]*Profile `yaml:"profiles"`
+	}
+
+	// Profile describes how to access a CTRLD instance and the elements managed by it.
+	Profile struct {
+		// Name holds the profile name.
+		Name string `yaml:"-"`
+		// Endpoint holds the CTRLD endpoint URL.
+		Endpoint string `yaml:"endpoint"`
+		// Element holds the default element name.
+		Element string `yaml:"element"`
+		// Elements holds the element names fleet-wide tools shall work on.
+		Elements []string `yaml:"elements"`
+		// AccessToken holds a static access token.
+		AccessToken string `yaml:"access-token"`
+		// TokenCommand holds a command and its arguments printing an access token to stdout.
+		// The token command is only used if no access token is configured.
+		TokenCommand []string `yaml:"token-command"`
+		// TLS holds the TLS settings.
+		TLS *TLS `yaml:"tls"`
+		// Timeout holds the HTTP client timeout. No timeout is applied if not set.
+		Timeout time.Duration `yaml:"timeout"`
+	}
+
+	// TLS describes the TLS settings to access CTRLD.
+	TLS struct {
+		// CAFile holds the path of a PEM encoded CA bundle to verify the server certificate.
+		CAFile string `yaml:"ca-file"`
+		// CertFile holds the path of the PEM encoded client certificate.
+		CertFile string `yaml:"cert-file"`
+		// KeyFile holds the path of the PEM encoded client key.
+		KeyFile string `yaml:"key-file"`
+		// ServerName overrides the server name sent in the TLS handshake.
+		ServerName string `yaml:"server-name"`
+		// InsecureSkipVerify disables the server certificate verification.
+		InsecureSkipVerify bool `yaml:"insecure-skip-verify"`
+		// PinnedFingerprints holds the accepted SHA-256 fingerprints of the server certificate chain.
+		PinnedFingerprints []string `yaml:"pinned-fingerprints"`
+	}
+)
+
+// DefaultPath returns the profile file path. The path is read from the RBFS_CONFIG environment variable
+// and defaults to $HOME/.rbfs/config.yaml.
+func DefaultPath() string {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".rbfs", "config.yaml")
+}
+
+// Load reads the profile file with the given path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	config, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read profile file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// LoadDefault reads the profile file from the default path.
+// An empty configuration is returned if the file does not exist, to allow configuring the client by environment
+// variables only.
+func LoadDefault() (*Config, error) {
+	path := DefaultPath()
+	if path == "" {
+		return &Config{}, nil
+	}
+	config, err := Load(path)
+	if errors.Is(err, os.ErrNotExist) && os.Getenv(EnvConfig) == "" {
+		return &Config{}, nil
+	}
+	return config, err
+}
+
+// Parse parses a YAML or JSON encoded profile file.
+func Parse(data []byte) (*Config, error) {
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	for name, profile := range config.Profiles {
+		if profile == nil {
+			return nil, fmt.Errorf("profile %s is empty", name)
+		}
+		profile.Name = name
+	}
+	return &config, nil
+}
+
+// Profile returns a copy of the profile with the given name and applies the environment variable overrides.
+// The profile name defaults to the RBFS_PROFILE environment variable and the current profile of the profile file.
+// A profile created from environment variables only is returned, if the profile file does not define any profiles.
+func (c *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = c.CurrentProfile
+	}
+
+	var profile Profile
+	switch p, ok := c.Profiles[name]; {
+	case ok:
+		profile = *p
+	case name == "" && len(c.Profiles) == 1:
+		for n, p := range c.Profiles {
+			profile = *p
+			profile.Name = n
+		}
+	case name == "" && len(c.Profiles) == 0:
+		profile.Name = defaultProfileName
+	case name == "":
+		return nil, fmt.Errorf("no profile selected")
+	default:
+		return nil, fmt.Errorf("profile %s not found", name)
+	}
+
+	if profile.TLS != nil {
+		tlsSettings := *profile.TLS
+		profile.TLS = &tlsSettings
+	}
+	if err := profile.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if profile.Endpoint == "" {
+		return nil, fmt.Errorf("profile %s does not specify an endpoint", profile.Name)
+	}
+	return &profile, nil
+}
+
+func (p *Profile) applyEnv(lookup func(string) (string, bool)) error {
+	overrides := map[string]*string{
+		EnvEndpoint:    &p.Endpoint,
+		EnvElement:     &p.Element,
+		EnvAccessToken: &p.AccessToken,
+	}
+	for name, field := range overrides {
+		if value, ok := lookup(name); ok {
+			*field = value
+		}
+	}
+
+	if value, ok := lookup(EnvTimeout); ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s value: %w", EnvTimeout, err)
+		}
+		p.Timeout = timeout
+	}
+
+	tlsOverrides := map[string]func(*TLS) *string{
+		EnvCAFile:     func(t *TLS) *string { return &t.CAFile },
+		EnvCertFile:   func(t *TLS) *string { return &t.CertFile },
+		EnvKeyFile:    func(t *TLS) *string { return &t.KeyFile },
+		EnvServerName: func(t *TLS) *string { return &t.ServerName },
+	}
+	for name, field := range tlsOverrides {
+		if value, ok := lookup(name); ok {
+			*field(p.tls()) = value
+		}
+	}
+
+	if value, ok := lookup(EnvInsecureSkipVerify); ok {
+		skip, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s value: %w", EnvInsecureSkipVerify, err)
+		}
+		p.tls().InsecureSkipVerify = skip
+	}
+	return nil
+}
+
+// tls returns the TLS settings and creates them if not yet present.
+func (p *Profile) tls() *TLS {
+	if p.TLS == nil {
+		p.TLS = &TLS{}
+	}
+	return p.TLS
+}
+
+// EndpointURL returns the parsed CTRLD endpoint URL.
+func (p *Profile) EndpointURL() (*url.URL, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(p.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint of profile %s: %w", p.Name, err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint of profile %s: absolute URL expected", p.Name)
+	}
+	return endpoint, nil
+}
+
+// DefaultElement returns the default element name, which is either the configured element or the first
+// of the configured elements.
+func (p *Profile) DefaultElement() string {
+	if p.Element != "" {
+		return p.Element
+	}
+	if len(p.Elements) > 0 {
+		return p.Elements[0]
+	}
+	return ""
+}
+
+// Token returns the access token. The token command is invoked if no static access token is configured.
+// An empty token is returned if neither an access token nor a token command is configured.
+func (p *Profile) Token(ctx context.Context) (string, error) {
+	if p.AccessToken != "" || len(p.TokenCommand) == 0 {
+		return p.AccessToken, nil
+	}
+	//nolint:gosec // the token command is configured by the user
+	cmd := exec.CommandContext(ctx, p.TokenCommand[0], p.TokenCommand[1:]...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command of profile %s failed: %w", p.Name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HTTPClient creates a new HTTP client with the TLS settings and timeout of the profile.
+func (p *Profile) HTTPClient() (*http.Client, error) {
+	client, err := rbfs.NewHTTPClient(p.TLS.options()...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS settings of profile %s: %w", p.Name, err)
+	}
+	client.Timeout = p.Timeout
+	return client, nil
+}
+
+// options returns the client options to apply the TLS settings.
+func (t *TLS) options() []rbfs.Option {
+	if t == nil {
+		return nil
+	}
+	var options []rbfs.Option
+	if t.CAFile != "" {
+		options = append(options, rbfs.CACertFile(t.CAFile))
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		options = append(options, rbfs.ClientCertificate(t.CertFile, t.KeyFile))
+	}
+	if t.ServerName != "" {
+		options = append(options, rbfs.ServerName(t.ServerName))
+	}
+	if t.InsecureSkipVerify {
+		options = append(options, rbfs.InsecureSkipVerify())
+	}
+	if len(t.PinnedFingerprints) > 0 {
+		options = append(options, rbfs.PinnedCertificates(t.PinnedFingerprints...))
+	}
+	return options
+}
+
+// NewRbfsContext creates a new RBFS context for the given element. The element name defaults to the profile's
+// default element. The access token of the profile is added to the context.
+func (p *Profile) NewRbfsContext(ctx context.Context, elementName string, options ...rbfs.RbfsContextOption) (rbfs.RbfsContext, error) {
+	endpoint, err := p.EndpointURL()
+	if err != nil {
+		return nil, err
+	}
+	if elementName == "" {
+		elementName = p.DefaultElement()
+	}
+	if elementName == "" {
+		return nil, fmt.Errorf("profile %s does not specify an element", p.Name)
+	}
+	token, err := p.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	options = append([]rbfs.RbfsContextOption{rbfs.RbfsAccessToken(token)}, options...)
+	return rbfs.NewRbfsContext(ctx, endpoint, elementName, options...)
+}
+
+// Connect creates the HTTP client and the RBFS context for the default element of the profile.
+func (p *Profile) Connect(ctx context.Context, options ...rbfs.RbfsContextOption) (*http.Client, rbfs.RbfsContext, error) {
+	client, err := p.HTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	rbfsCtx, err := p.NewRbfsContext(ctx, "", options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, rbfsCtx, nil
+}
+
+// Connect loads the profile with the given name from the default profile file and creates the HTTP client and the
+// RBFS context for the profile's default element.
+func Connect(ctx context.Context, profileName string, options ...rbfs.RbfsContextOption) (*http.Client, rbfs.RbfsContext, error) {
+	config, err := LoadDefault()
+	if err != nil {
+		return nil, nil, err
+	}
+	profile, err := config.Profile(profileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return profile.Connect(ctx, options...)
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package profile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `
+current-profile: lab
+profiles:
+  lab:
+    endpoint: http://192.168.0.1:19091/
+    element: leaf01
+    access-token: secret
+    timeout: 30s
+  prod:
+    endpoint: https://ctrld.example.net
+    elements: [spine01, spine02]
+    tls:
+      server-name: ctrld
+`
+
+const testConfigJSON = `{
+  "current-profile": "lab",
+  "profiles": {
+    "lab": {"endpoint": "http://192.168.0.1:19091", "element": "leaf01", "timeout": "5s"}
+  }
+}`
+
+func TestParse(t *testing.T) {
+	config, err := Parse([]byte(testConfigYAML))
+	require.NoError(t, err)
+	require.Equal(t, "lab", config.CurrentProfile)
+	require.Len(t, config.Profiles, 2)
+	require.Equal(t, &Profile{
+		Name:        "lab",
+		Endpoint:    "http://192.168.0.1:19091/",
+		Element:     "leaf01",
+		AccessToken: "secret",
+		Timeout:     30 * time.Second,
+	}, config.Profiles["lab"])
+
+	config, err = Parse([]byte(testConfigJSON))
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, config.Profiles["lab"].Timeout)
+
+	_, err = Parse([]byte("profiles:\n  empty:\n"))
+	require.EqualError(t, err, "profile empty is empty")
+}
+
+func TestConfig_Profile(t *testing.T) {
+	config, err := Parse([]byte(testConfigYAML))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		profile  string
+		env      map[string]string
+		wantErr  string
+		validate func(t *testing.T, p *Profile)
+	}{
+		{
+			name: "current profile",
+			validate: func(t *testing.T, p *Profile) {
+				require.Equal(t, "lab", p.Name)
+				require.Equal(t, "leaf01", p.DefaultElement())
+			},
+		}, {
+			name: "profile selected by environment",
+			env:  map[string]string{EnvProfile: "prod"},
+			validate: func(t *testing.T, p *Profile) {
+				require.Equal(t, "prod", p.Name)
+				require.Equal(t, "spine01", p.DefaultElement())
+			},
+		}, {
+			name:    "environment overrides",
+			profile: "prod",
+			env: map[string]string{
+				EnvElement:            "leaf02",
+				EnvTimeout:            "1m",
+				EnvInsecureSkipVerify: "true",
+			},
+			validate: func(t *testing.T, p *Profile) {
+				require.Equal(t, "leaf02", p.DefaultElement())
+				require.Equal(t, time.Minute, p.Timeout)
+				require.True(t, p.TLS.InsecureSkipVerify)
+				require.Equal(t, "ctrld", p.TLS.ServerName)
+				require.False(t, config.Profiles["prod"].TLS.InsecureSkipVerify)
+			},
+		}, {
+			name:    "invalid timeout",
+			env:     map[string]string{EnvTimeout: "soon"},
+			wantErr: `invalid RBFS_TIMEOUT value: time: invalid duration "soon"`,
+		}, {
+			name:    "profile not found",
+			profile: "staging",
+			wantErr: "profile staging not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got, err := config.Profile(tt.profile)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.validate(t, got)
+		})
+	}
+}
+
+func TestConfig_ProfileFromEnvironment(t *testing.T) {
+	t.Setenv(EnvEndpoint, "http://192.168.0.1")
+	t.Setenv(EnvElement, "rtbrick")
+	t.Setenv(EnvAccessToken, "token")
+
+	p, err := (&Config{}).Profile("")
+	require.NoError(t, err)
+	require.Equal(t, "default", p.Name)
+
+	ctx, err := p.NewRbfsContext(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, "token", ctx.Value(state.ContextAccessToken))
+	u, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	require.NoError(t, err)
+	require.Equal(t, "http://192.168.0.1/api/v1/rbfs/elements/rtbrick/services/opsd/proxy", u.String())
+}
+
+func TestLoadDefault(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfigYAML), 0o600))
+	t.Setenv(EnvConfig, path)
+
+	client, ctx, err := Connect(context.Background(), "lab")
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, client.Timeout)
+	u, err := ctx.GetCtrldElementEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, "http://192.168.0.1:19091/api/v1/ctrld/elements/leaf01", u.String())
+
+	t.Setenv(EnvConfig, filepath.Join(dir, "missing.yaml"))
+	_, err = LoadDefault()
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestProfile_Token(t *testing.T) {
+	p := &Profile{Name: "test", TokenCommand: []string{"echo", "command-token"}}
+	token, err := p.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "command-token", token)
+
+	p.AccessToken = "static-token"
+	token, err = p.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "static-token", token)
+}
+
+func TestProfile_HTTPClient(t *testing.T) {
+	p := &Profile{Name: "test", TLS: &TLS{CAFile: filepath.Join(t.TempDir(), "missing.pem")}}
+	_, err := p.HTTPClient()
+	require.Error(t, err)
+
+	p.TLS = &TLS{ServerName: "ctrld"}
+	client, err := p.HTTPClient()
+	require.NoError(t, err)
+	require.NotNil(t, client.Transport)
+}
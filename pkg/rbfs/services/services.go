@@ -33,6 +33,7 @@ type (
 )
 
 // NewClient creates a new client to query running RBFS services and daemons.
+// Create the HTTP client with rbfs.NewHTTPClient to apply TLS, logging or tracing options.
 func NewClient(c *http.Client) Client {
 	return &client{c}
 }
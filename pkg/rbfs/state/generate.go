@@ -0,0 +1,9 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package state
+
+// Generate the API service interfaces and their mocks after regenerating the client from the OpenAPI specification.
+//go:generate go run ../../../internal/cmd/apigen
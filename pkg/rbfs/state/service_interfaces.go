@@ -0,0 +1,471 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Code generated by apigen. DO NOT EDIT.
+
+package state
+
+import (
+	"context"
+	"net/http"
+)
+
+// A10NSPApi describes the operations of the A10NSPApiService.
+type A10NSPApi interface {
+	// GetA10NSPL2XEndpoint shows an L2X endpoint.
+	GetA10NSPL2XEndpoint(ctx context.Context, lagInterfaceName string, sVlan int) ([]A10nspConfig, *http.Response, error)
+	// GetA10NSPL2XEndpoints lists all L2X endpoints.
+	GetA10NSPL2XEndpoints(ctx context.Context) ([]A10nspConfig, *http.Response, error)
+	// GetA10NSPL2XEndpointsOfLAGInterface lists all L2X endpoints.
+	GetA10NSPL2XEndpointsOfLAGInterface(ctx context.Context, lagInterfaceName string) ([]A10nspConfig, *http.Response, error)
+	// GetA10NSPL2XRangeEndpoint shows an L2X endpoint.
+	GetA10NSPL2XRangeEndpoint(ctx context.Context, lagInterfaceName string, sVlanLow int, sVlanHigh int) ([]A10nspConfig, *http.Response, error)
+	// ProcessA10NSPL2XBatch runs a A10NSP L2X configuration batch job.
+	ProcessA10NSPL2XBatch(ctx context.Context, body A10nspConfigBatch) (*http.Response, error)
+	// RemoveA10NSPL2XEndpoint removes the L2X endpoint.
+	RemoveA10NSPL2XEndpoint(ctx context.Context, lagInterfaceName string, sVlan int) (*http.Response, error)
+	// RemoveA10NSPL2XRangeEndpoint removes the L2X endpoint.
+	RemoveA10NSPL2XRangeEndpoint(ctx context.Context, lagInterfaceName string, sVlanLow int, sVlanHigh int) (*http.Response, error)
+	// ResizeA10NSPL2XRangeEndpoint resizes a configured S-VLAN range.
+	ResizeA10NSPL2XRangeEndpoint(ctx context.Context, lagInterfaceName string, sVlanLow int, sVlanHigh int, localVarOptionals *A10NSPApiResizeA10NSPL2XRangeEndpointOpts) (*http.Response, error)
+	// StoreA10NSPL2XEndpoint stores an L2X endpoint.
+	StoreA10NSPL2XEndpoint(ctx context.Context, body A10nspConfig, lagInterfaceName string, sVlan int) (*http.Response, error)
+	// StoreA10NSPL2XEndpoints updates all L2X endpoint configurations.
+	StoreA10NSPL2XEndpoints(ctx context.Context, body []A10nspConfig) (*http.Response, error)
+	// StoreA10NSPL2XEndpointsForLAGInterface updates all L2X endpoint configurations for a LAG interface.
+	StoreA10NSPL2XEndpointsForLAGInterface(ctx context.Context, body []A10nspConfig, lagInterfaceName string) (*http.Response, error)
+	// StoreA10NSPL2XRangeEndpoint stores an L2X endpoint.
+	StoreA10NSPL2XRangeEndpoint(ctx context.Context, body A10nspConfig, lagInterfaceName string, sVlanLow int, sVlanHigh int) (*http.Response, error)
+}
+
+// ensure, that A10NSPApiService does implement A10NSPApi.
+var _ A10NSPApi = &A10NSPApiService{}
+
+// AccessApi describes the operations of the AccessApiService.
+type AccessApi interface {
+	// AddVLANProfile adds a VLAN profile.
+	AddVLANProfile(ctx context.Context, body VlanProfile, ifpName string, localVarOptionals *AccessApiAddVLANProfileOpts) (*http.Response, error)
+	// DeleteVLANProfile deletes a VLAN profile.
+	DeleteVLANProfile(ctx context.Context, ifpName string, localVarOptionals *AccessApiDeleteVLANProfileOpts) (*http.Response, error)
+	// FlushVLANProfiles deletes VLAN profiles.
+	FlushVLANProfiles(ctx context.Context, localVarOptionals *AccessApiFlushVLANProfilesOpts) (*http.Response, error)
+	// GetVLANProfiles lists VLAN profiles.
+	GetVLANProfiles(ctx context.Context, localVarOptionals *AccessApiGetVLANProfilesOpts) ([]VlanProfile, *http.Response, error)
+	// ProcessVLANProfilesBatch runs a VLAN profile configuration batch job.
+	ProcessVLANProfilesBatch(ctx context.Context, body VlanProfileBatch) (*http.Response, error)
+	// StoreVLANProfiles updates VLAN profiles.
+	StoreVLANProfiles(ctx context.Context, body []VlanProfile, localVarOptionals *AccessApiStoreVLANProfilesOpts) (*http.Response, error)
+}
+
+// ensure, that AccessApiService does implement AccessApi.
+var _ AccessApi = &AccessApiService{}
+
+// ActionsApi describes the operations of the ActionsApiService.
+type ActionsApi interface {
+	// Ping pings the given destination (ICMP ping).
+	Ping(ctx context.Context, localVarOptionals *ActionsApiPingOpts) (PingStatus, *http.Response, error)
+	// PingMpls pings the given destination (LSPING).
+	PingMpls(ctx context.Context, localVarOptionals *ActionsApiPingMplsOpts) (PingStatus, *http.Response, error)
+	// Traceroute traces the route to the given destination IP address.
+	Traceroute(ctx context.Context, localVarOptionals *ActionsApiTracerouteOpts) (Traceroute, *http.Response, error)
+}
+
+// ensure, that ActionsApiService does implement ActionsApi.
+var _ ActionsApi = &ActionsApiService{}
+
+// BGPApi describes the operations of the BGPApiService.
+type BGPApi interface {
+	// ClearBGPInstancePeerings clears all instance BGP peerings.
+	ClearBGPInstancePeerings(ctx context.Context, instanceName string) (*http.Response, error)
+	// ClearBGPPeering clears a BGP peering.
+	ClearBGPPeering(ctx context.Context, instanceName string, peerIp string, localVarOptionals *BGPApiClearBGPPeeringOpts) (*http.Response, error)
+	// GetBGPInstance get BGP instance details.
+	GetBGPInstance(ctx context.Context, instanceName string) (BgpInstance, *http.Response, error)
+	// GetBGPInstancePeerings provides BGP peering details.
+	GetBGPInstancePeerings(ctx context.Context, instanceName string, peerIp string, localVarOptionals *BGPApiGetBGPInstancePeeringsOpts) ([]BgpPeering, *http.Response, error)
+	// GetBGPInstances lists all BGP instances.
+	GetBGPInstances(ctx context.Context) ([]BgpInstanceRef, *http.Response, error)
+	// GetBGPPeering provides BGP peering details.
+	GetBGPPeering(ctx context.Context, instanceName string, peerIp string, localVarOptionals *BGPApiGetBGPPeeringOpts) (BgpPeering, *http.Response, error)
+	// GetBGPPeerings lists all BGP peerings grouped by instance.
+	GetBGPPeerings(ctx context.Context) ([]BgpInstancePeerings, *http.Response, error)
+}
+
+// ensure, that BGPApiService does implement BGPApi.
+var _ BGPApi = &BGPApiService{}
+
+// CgNATApi describes the operations of the CgNATApiService.
+type CgNATApi interface {
+	// GetCgnatAddressTranslations looks up all address translations for a given user.
+	GetCgnatAddressTranslations(ctx context.Context, instance string, user string) (UserAddressTranslations, *http.Response, error)
+}
+
+// ensure, that CgNATApiService does implement CgNATApi.
+var _ CgNATApi = &CgNATApiService{}
+
+// FlowspecApi describes the operations of the FlowspecApiService.
+type FlowspecApi interface {
+	// FlowspecInstancesInstanceRulesAfiGet lists all flowspec rules for the given instance and address family.
+	FlowspecInstancesInstanceRulesAfiGet(ctx context.Context, instance string, afi string, localVarOptionals *FlowspecApiFlowspecInstancesInstanceRulesAfiGetOpts) ([]FlowspecRule, *http.Response, error)
+	// FlowspecInstancesInstanceRulesGet lists all flowspec rules for the given instance.
+	FlowspecInstancesInstanceRulesGet(ctx context.Context, instance string, localVarOptionals *FlowspecApiFlowspecInstancesInstanceRulesGetOpts) (FlowspecInstanceRules, *http.Response, error)
+	// FlowspecInstancesRulesGet lists all instances with flowspec rules.
+	FlowspecInstancesRulesGet(ctx context.Context, localVarOptionals *FlowspecApiFlowspecInstancesRulesGetOpts) ([]FlowspecInstanceRules, *http.Response, error)
+}
+
+// ensure, that FlowspecApiService does implement FlowspecApi.
+var _ FlowspecApi = &FlowspecApiService{}
+
+// ISISApi describes the operations of the ISISApiService.
+type ISISApi interface {
+	// ClearISISInstanceNeighbors clears the IS-IS instance adjacency.
+	ClearISISInstanceNeighbors(ctx context.Context, instanceName string) (*http.Response, error)
+	// ClearISISInterfaceNeighbors clears all IS-IS neighbors connected through the specified logical interface.
+	ClearISISInterfaceNeighbors(ctx context.Context, instanceName string, iflName string) (*http.Response, error)
+	// GetISISInstance shows IS-IS instance details.
+	GetISISInstance(ctx context.Context, instanceName string) (IsisInstance, *http.Response, error)
+	// GetISISInstances lists all IS-IS instances.
+	GetISISInstances(ctx context.Context) ([]IsisInstanceRef, *http.Response, error)
+	// GetISISInterface shows details of a IS-IS interface.
+	GetISISInterface(ctx context.Context, instanceName string, iflName string) (IsisInstanceInterface, *http.Response, error)
+	// GetISISInterfaces lists all IS-IS interfaces.
+	GetISISInterfaces(ctx context.Context) ([]IsisInstanceInterfaces, *http.Response, error)
+	// GetISISNeighbor shows details of a IS-IS neighbor.
+	GetISISNeighbor(ctx context.Context, instanceName string, iflName string) (IsisInstanceNeighbor, *http.Response, error)
+	// GetISISNeighbors lists all IS-IS neighbors.
+	GetISISNeighbors(ctx context.Context) ([]IsisInstanceNeighbors, *http.Response, error)
+}
+
+// ensure, that ISISApiService does implement ISISApi.
+var _ ISISApi = &ISISApiService{}
+
+// InterfacesApi describes the operations of the InterfacesApiService.
+type InterfacesApi interface {
+	// ClearInterfaceCounters clears physical or logical interface counters.
+	ClearInterfaceCounters(ctx context.Context, ifName string) (*http.Response, error)
+	// ClearLogicalInterfaceCounters clears logical interface counters.
+	ClearLogicalInterfaceCounters(ctx context.Context, iflName string) (*http.Response, error)
+	// ClearPhysicalInterfaceCounters clears physical interface counters.
+	ClearPhysicalInterfaceCounters(ctx context.Context, ifpName string) (*http.Response, error)
+	// DisableInterface disables a physical or a logical interface name.
+	DisableInterface(ctx context.Context, ifName string) (*http.Response, error)
+	// DisableLawfulInterceptionIFL disables lawful interception for the specified logical interface.
+	DisableLawfulInterceptionIFL(ctx context.Context, iflName string, id int) (*http.Response, error)
+	// DisableLogicalInterface disables a logical interface.
+	DisableLogicalInterface(ctx context.Context, iflName string) (*http.Response, error)
+	// DisablePhysicalInterface disables a physical interface.
+	DisablePhysicalInterface(ctx context.Context, ifpName string) (*http.Response, error)
+	// EnableInterface enables a physical or a logical interface name.
+	EnableInterface(ctx context.Context, ifName string) (*http.Response, error)
+	// EnableLawfulInterceptionIFL enables lawful interception for the specified logical interface.
+	EnableLawfulInterceptionIFL(ctx context.Context, iflName string, id int, direction string, medIp string, medPort int, medInstance string) (*http.Response, error)
+	// EnableLogicalInterface enables a logical interface.
+	EnableLogicalInterface(ctx context.Context, iflName string) (*http.Response, error)
+	// EnablePhysicalInterface enables a physical interface.
+	EnablePhysicalInterface(ctx context.Context, ifpName string) (*http.Response, error)
+	// GetInterface shows a physical interface and its logical interfaces.
+	GetInterface(ctx context.Context, ifpName string) (PhysicalInterfaceSummary, *http.Response, error)
+	// GetInterfaces lists all physical interfaces and their logical interfaces.
+	GetInterfaces(ctx context.Context) ([]PhysicalInterfaceSummaryRef, *http.Response, error)
+	// GetLogicalInterface shows a logical interface.
+	GetLogicalInterface(ctx context.Context, iflName string) (LogicalInterface, *http.Response, error)
+	// GetPhysicalInterface shows a physical interface.
+	GetPhysicalInterface(ctx context.Context, ifpName string, localVarOptionals *InterfacesApiGetPhysicalInterfaceOpts) (PhysicalInterfaceDetail, *http.Response, error)
+	// GetPhysicalInterfaces lists all physical interfaces.
+	GetPhysicalInterfaces(ctx context.Context, localVarOptionals *InterfacesApiGetPhysicalInterfacesOpts) ([]PhysicalInterface, *http.Response, error)
+	// StartLogicalInterfaceTrafficCapture starts logical interface traffic capturing.
+	StartLogicalInterfaceTrafficCapture(ctx context.Context, iflName string, direction string, fileName string) (*http.Response, error)
+	// StartPhysicalInterfaceTrafficCapture starts physical interface traffic capturing.
+	StartPhysicalInterfaceTrafficCapture(ctx context.Context, ifpName string, direction string, fileName string) (*http.Response, error)
+	// StartTrafficCapture starts physical or logical interface traffic capturing.
+	StartTrafficCapture(ctx context.Context, ifName string, direction string, fileName string) (*http.Response, error)
+	// StopLogicalInterfaceTrafficCapture stops logical interface traffic capturing.
+	StopLogicalInterfaceTrafficCapture(ctx context.Context, iflName string, direction string, fileName string) (*http.Response, error)
+	// StopPhysicalInterfaceTrafficCapture stops physical interface traffic capturing.
+	StopPhysicalInterfaceTrafficCapture(ctx context.Context, ifpName string, direction string, fileName string) (*http.Response, error)
+	// StopTrafficCapture stops physical or logical interface traffic capturing.
+	StopTrafficCapture(ctx context.Context, ifName string, direction string, fileName string) (*http.Response, error)
+}
+
+// ensure, that InterfacesApiService does implement InterfacesApi.
+var _ InterfacesApi = &InterfacesApiService{}
+
+// L2BSAApi describes the operations of the L2BSAApiService.
+type L2BSAApi interface {
+	// GetL2BSAService lists all L2BSA services on a physical interface.
+	GetL2BSAService(ctx context.Context, ifpName string) ([]L2bsaServiceConfig, *http.Response, error)
+	// GetL2BSAServices lists all L2BSA services.
+	GetL2BSAServices(ctx context.Context) ([]L2bsaServiceConfig, *http.Response, error)
+	// GetL2BSAServicesOfANP shows a L2BSA service.
+	GetL2BSAServicesOfANP(ctx context.Context, ifpName string, anp int) (L2bsaServiceConfig, *http.Response, error)
+	// ProcessL2BSAServiceBatch runs L2BSA service configuration batch job.
+	ProcessL2BSAServiceBatch(ctx context.Context, body L2bsaServiceConfigBatch) (*http.Response, error)
+	// RemoveL2BSAServicesForANP removes a L2BSA service.
+	RemoveL2BSAServicesForANP(ctx context.Context, ifpName string, anp int) (*http.Response, error)
+	// StoreL2BSAService updates all L2BSA services on the given physical interface.
+	StoreL2BSAService(ctx context.Context, body []L2bsaServiceConfig, ifpName string) ([]L2bsaServiceConfig, *http.Response, error)
+	// StoreL2BSAServices updates all L2BSA services.
+	StoreL2BSAServices(ctx context.Context, body []L2bsaServiceConfig) ([]L2bsaServiceConfig, *http.Response, error)
+	// StoreL2BSAServicesForANP stores a L2BSA service configuration.
+	StoreL2BSAServicesForANP(ctx context.Context, body L2bsaServiceConfig, ifpName string, anp int) (*http.Response, error)
+}
+
+// ensure, that L2BSAApiService does implement L2BSAApi.
+var _ L2BSAApi = &L2BSAApiService{}
+
+// L2VPNApi describes the operations of the L2VPNApiService.
+type L2VPNApi interface {
+	// L2vpnCrossConnectsGet lists all establish cross-connects.
+	L2vpnCrossConnectsGet(ctx context.Context, localVarOptionals *L2VPNApiL2vpnCrossConnectsGetOpts) ([]L2VpnCrossConnect, *http.Response, error)
+	// L2vpnInstancesInstancePseudowiresGet lists L2VPN pseudowires including their respective states.
+	L2vpnInstancesInstancePseudowiresGet(ctx context.Context, instance string, localVarOptionals *L2VPNApiL2vpnInstancesInstancePseudowiresGetOpts) (L2VpnInstancePseudowires, *http.Response, error)
+	// L2vpnInstancesPseudowiresGet lists L2VPN pseudowires including their respective states.
+	L2vpnInstancesPseudowiresGet(ctx context.Context, localVarOptionals *L2VPNApiL2vpnInstancesPseudowiresGetOpts) ([]L2VpnInstancePseudowires, *http.Response, error)
+}
+
+// ensure, that L2VPNApiService does implement L2VPNApi.
+var _ L2VPNApi = &L2VPNApiService{}
+
+// LDPApi describes the operations of the LDPApiService.
+type LDPApi interface {
+	// ClearLDPSession clears a LDP session.
+	ClearLDPSession(ctx context.Context, instanceName string, peerIp string) (*http.Response, error)
+	// ClearLDPSessions clears all LDP sessions.
+	ClearLDPSessions(ctx context.Context, instanceName string) (*http.Response, error)
+	// GetLDPInstance returns the LDP sessions and statistics.
+	GetLDPInstance(ctx context.Context, instanceName string) (LdpInstanceSessions, *http.Response, error)
+	// GetLDPInstances lists all instances running LDP.
+	GetLDPInstances(ctx context.Context) (LdpInstanceRef, *http.Response, error)
+	// GetLDPSession returns the LDP sessions details.
+	GetLDPSession(ctx context.Context, instanceName string, peerIp string) (LdpInstanceSession, *http.Response, error)
+	// GetLDPSessions lists LDP sessions.
+	GetLDPSessions(ctx context.Context) (LdpInstanceSessions, *http.Response, error)
+}
+
+// ensure, that LDPApiService does implement LDPApi.
+var _ LDPApi = &LDPApiService{}
+
+// LLDPApi describes the operations of the LLDPApiService.
+type LLDPApi interface {
+	// GetLLDPNeighbor shows an LLDP neighbor.
+	GetLLDPNeighbor(ctx context.Context, ifpName string) (LldpGlobalNeighbor, *http.Response, error)
+	// GetLLDPNeighbors lists all LLDP neighbors.
+	GetLLDPNeighbors(ctx context.Context) (LldpGlobalNeighbors, *http.Response, error)
+}
+
+// ensure, that LLDPApiService does implement LLDPApi.
+var _ LLDPApi = &LLDPApiService{}
+
+// NeighborsApi describes the operations of the NeighborsApiService.
+type NeighborsApi interface {
+	// GetInstanceNeighbors lists all known ARP/NDP neighbors.
+	GetInstanceNeighbors(ctx context.Context, instanceName string, localVarOptionals *NeighborsApiGetInstanceNeighborsOpts) (ArpndpInstanceNeighbors, *http.Response, error)
+	// GetNeighbors lists all known ARP/NDP neighbors.
+	GetNeighbors(ctx context.Context, localVarOptionals *NeighborsApiGetNeighborsOpts) ([]ArpndpInstanceNeighbors, *http.Response, error)
+}
+
+// ensure, that NeighborsApiService does implement NeighborsApi.
+var _ NeighborsApi = &NeighborsApiService{}
+
+// OSPFApi describes the operations of the OSPFApiService.
+type OSPFApi interface {
+	// ClearOSPFAreaNeighbor clears an OSPF neighbor.
+	ClearOSPFAreaNeighbor(ctx context.Context, instanceName string, areaId string, iflName string) (*http.Response, error)
+	// ClearOSPFNeighbor clears an OSPF neighbor.
+	ClearOSPFNeighbor(ctx context.Context, instanceName string, iflName string) (*http.Response, error)
+	// ClearOSPFv3Neighbor clears an OSPFv3 neighbor.
+	ClearOSPFv3Neighbor(ctx context.Context, instanceName string, instanceId int, iflName string) (*http.Response, error)
+	// GetOSPFInstance shows OSPF instance details.
+	GetOSPFInstance(ctx context.Context, instanceName string) (OspfInstanceDetails, *http.Response, error)
+	// GetOSPFInstanceInterfaces lists interfaces in an OSPF instance.
+	GetOSPFInstanceInterfaces(ctx context.Context, instanceName string) (OspfInstanceInterfaces, *http.Response, error)
+	// GetOSPFInstanceNeighbors lists neighbors in an OSPF instance.
+	GetOSPFInstanceNeighbors(ctx context.Context, instanceName string) (OspfInstanceNeighbors, *http.Response, error)
+	// GetOSPFInstances lists summary of all OSPF instances.
+	GetOSPFInstances(ctx context.Context) ([]OspfInstanceSummary, *http.Response, error)
+	// GetOSPFInterface shows an OSPF interface.
+	GetOSPFInterface(ctx context.Context, instanceName string, iflName string) (OspfInstanceInterface, *http.Response, error)
+	// GetOSPFInterfaces lists all OSPF interfaces.
+	GetOSPFInterfaces(ctx context.Context) ([]OspfInstanceInterfaces, *http.Response, error)
+	// GetOSPFNeighbor shows an OSPF neighbor.
+	GetOSPFNeighbor(ctx context.Context, instanceName string, iflName string) (OspfInstanceNeighbor, *http.Response, error)
+	// GetOSPFNeighbors list all OSPF neighbors.
+	GetOSPFNeighbors(ctx context.Context) (OspfInstanceNeighbors, *http.Response, error)
+	// GetOSPFv3Instance shows OSPFv3 instance details.
+	GetOSPFv3Instance(ctx context.Context, instanceName string, instanceId int) (Ospfv3InstanceDetails, *http.Response, error)
+	// GetOSPFv3InstanceInterfaces lists interfaces in an OSPFv3 instance.
+	GetOSPFv3InstanceInterfaces(ctx context.Context, instanceName string, instanceId int) (Ospfv3InstanceInterfaces, *http.Response, error)
+	// GetOSPFv3InstanceNeighbors lists neighbors in an OSPFv3 instance.
+	GetOSPFv3InstanceNeighbors(ctx context.Context, instanceName string, instanceId int) (Ospfv3InstanceNeighbors, *http.Response, error)
+	// GetOSPFv3Instances lists summary of all OSPFv3 instances.
+	GetOSPFv3Instances(ctx context.Context) ([]Ospfv3InstanceSummary, *http.Response, error)
+	// GetOSPFv3Interface shows an OSPFv3 interface.
+	GetOSPFv3Interface(ctx context.Context, instanceName string, instanceId int, iflName string) (Ospfv3InstanceInterface, *http.Response, error)
+	// GetOSPFv3Interfaces lists all OSPFv3 interfaces.
+	GetOSPFv3Interfaces(ctx context.Context) ([]Ospfv3InstanceInterfaces, *http.Response, error)
+	// GetOSPFv3Neighbor shows an OSPFv3 neighbor.
+	GetOSPFv3Neighbor(ctx context.Context, instanceName string, instanceId string, iflName string) (Ospfv3InstanceNeighbor, *http.Response, error)
+	// GetOSPFv3Neighbors list all OSPFv3 neighbors.
+	GetOSPFv3Neighbors(ctx context.Context) (Ospfv3InstanceNeighbors, *http.Response, error)
+}
+
+// ensure, that OSPFApiService does implement OSPFApi.
+var _ OSPFApi = &OSPFApiService{}
+
+// OpticsApi describes the operations of the OpticsApiService.
+type OpticsApi interface {
+	// DisableLaser disable the laser.
+	DisableLaser(ctx context.Context, ifpName string) (*http.Response, error)
+	// EnableLaser enables the laser.
+	EnableLaser(ctx context.Context, ifpName string) (*http.Response, error)
+	// GetOptic lists details of an attached optic.
+	GetOptic(ctx context.Context, ifpName string) (Optic, *http.Response, error)
+	// GetOptics lists all attached optics.
+	GetOptics(ctx context.Context) ([]Optic, *http.Response, error)
+}
+
+// ensure, that OpticsApiService does implement OpticsApi.
+var _ OpticsApi = &OpticsApiService{}
+
+// PIMApi describes the operations of the PIMApiService.
+type PIMApi interface {
+	// GetPIMInstance returns a PIM instance.
+	GetPIMInstance(ctx context.Context, instanceName string) (PimInstance, *http.Response, error)
+	// GetPIMInstances lists all PIM instances.
+	GetPIMInstances(ctx context.Context) ([]PimInstanceRef, *http.Response, error)
+	// GetPIMInterface returns the PIM interface.
+	GetPIMInterface(ctx context.Context, instanceName string, iflName string) (PimInterface, *http.Response, error)
+	// GetPIMInterfaces lists all PIM interfaces.
+	GetPIMInterfaces(ctx context.Context) ([]PimInterfaces, *http.Response, error)
+	// GetPIMNeighbor returns the PIM neighbor.
+	GetPIMNeighbor(ctx context.Context, instanceName string, neighborIp string) (PimNeighbor, *http.Response, error)
+	// GetPIMNeighbors lists all PIM neighbors.
+	GetPIMNeighbors(ctx context.Context) ([]PimNeighbors, *http.Response, error)
+}
+
+// ensure, that PIMApiService does implement PIMApi.
+var _ PIMApi = &PIMApiService{}
+
+// RPKIRTRApi describes the operations of the RPKIRTRApiService.
+type RPKIRTRApi interface {
+	// ClearRPKIRTRDatabase clears the RPKI-RTR database for the given session.
+	ClearRPKIRTRDatabase(ctx context.Context, instanceName string) (*http.Response, error)
+	// ClearRPKIRTRInstance clears all RPKI-RTR sessions on the given instance.
+	ClearRPKIRTRInstance(ctx context.Context, instanceName string, localVarOptionals *RPKIRTRApiClearRPKIRTRInstanceOpts) (*http.Response, error)
+	// ClearRPKIRTRSession clears a RPKI-RTR session.
+	ClearRPKIRTRSession(ctx context.Context, instanceName string, cacheIp CacheIp2, localVarOptionals *RPKIRTRApiClearRPKIRTRSessionOpts) (*http.Response, error)
+	// GetRPKIRTRInstance returns the instance with configured RPKI-RTR.
+	GetRPKIRTRInstance(ctx context.Context, instanceName string) (RpkirtrInstance, *http.Response, error)
+	// GetRPKIRTRInstances lists all instances with configured RPKI-RTR.
+	GetRPKIRTRInstances(ctx context.Context) ([]RpkirtrInstance, *http.Response, error)
+	// GetRPKIRTRRecord returns the database record for the given prefix.
+	GetRPKIRTRRecord(ctx context.Context, instanceName string, prefix Prefix) (RpkirtrRecord, *http.Response, error)
+	// GetRPKIRTRSession returns a RPKI-RTR session.
+	GetRPKIRTRSession(ctx context.Context, instanceName string, cacheIp CacheIp1, localVarOptionals *RPKIRTRApiGetRPKIRTRSessionOpts) (RpkirtrSession, *http.Response, error)
+	// GetRPKIRTRSessions returns a RPKI-RTR session.
+	GetRPKIRTRSessions(ctx context.Context, instanceName string, cacheIp CacheIp, localVarOptionals *RPKIRTRApiGetRPKIRTRSessionsOpts) ([]RpkirtrSession, *http.Response, error)
+}
+
+// ensure, that RPKIRTRApiService does implement RPKIRTRApi.
+var _ RPKIRTRApi = &RPKIRTRApiService{}
+
+// RoutesApi describes the operations of the RoutesApiService.
+type RoutesApi interface {
+	// GetInstanceMulticastRoutes list multicast route information for a group.
+	GetInstanceMulticastRoutes(ctx context.Context, instanceName string, afi string, safi string, localVarOptionals *RoutesApiGetInstanceMulticastRoutesOpts) (InstanceMulticastRoutes, *http.Response, error)
+	// GetInstanceRoutesSummary returns route summary statistics.
+	GetInstanceRoutesSummary(ctx context.Context, instanceName string) (map[string]RoutesSummary, *http.Response, error)
+	// GetInstanceUnicastRoutes list route information for a prefix.
+	GetInstanceUnicastRoutes(ctx context.Context, instanceName string, afi string, safi string, localVarOptionals *RoutesApiGetInstanceUnicastRoutesOpts) (InstanceUnicastRoutes, *http.Response, error)
+	// GetRoutesSummary returns route summary statistics.
+	GetRoutesSummary(ctx context.Context) (map[string]RoutesSummary, *http.Response, error)
+}
+
+// ensure, that RoutesApiService does implement RoutesApi.
+var _ RoutesApi = &RoutesApiService{}
+
+// StreamsApi describes the operations of the StreamsApiService.
+type StreamsApi interface {
+	// StreamPing pings the given destination IP address (ICMP ping).
+	StreamPing(ctx context.Context, localVarOptionals *StreamsApiStreamPingOpts) (string, *http.Response, error)
+	// StreamPingMpls pings the given destination (LSPING).
+	StreamPingMpls(ctx context.Context, localVarOptionals *StreamsApiStreamPingMplsOpts) (string, *http.Response, error)
+	// StreamTraceroute traces the route to the given destination IP address.
+	StreamTraceroute(ctx context.Context, localVarOptionals *StreamsApiStreamTracerouteOpts) (string, *http.Response, error)
+}
+
+// ensure, that StreamsApiService does implement StreamsApi.
+var _ StreamsApi = &StreamsApiService{}
+
+// SubscriberApi describes the operations of the SubscriberApiService.
+type SubscriberApi interface {
+	// AddTestSubscribers adds test subscribers.
+	AddTestSubscribers(ctx context.Context, body []TestAaaObject) (*http.Response, error)
+	// ClearL2BSASubscriber terminates the L2BSA subscriber session.
+	ClearL2BSASubscriber(ctx context.Context, ifpName string, anp int) (*http.Response, error)
+	// ClearSubscriber terminates the subscriber session.
+	ClearSubscriber(ctx context.Context, subscriberId int, localVarOptionals *SubscriberApiClearSubscriberOpts) (*http.Response, error)
+	// ClearSubscribers terminates all matching subscriber sessions.
+	ClearSubscribers(ctx context.Context, localVarOptionals *SubscriberApiClearSubscribersOpts) (*http.Response, error)
+	// DisableLawfulInterceptionSubscriber disables lawful interception for the specified subscriber.
+	DisableLawfulInterceptionSubscriber(ctx context.Context, subscriberId int, id int) (*http.Response, error)
+	// EnableLawfulInterceptionSubscriber enables lawful interception for the specified subscriber.
+	EnableLawfulInterceptionSubscriber(ctx context.Context, subscriberId int, id int, direction string, medIp string, medPort int, medInstance string) (*http.Response, error)
+	// GetAccessLineInfo returns access line information.
+	GetAccessLineInfo(ctx context.Context, subscriberId int) (AccessLineInfo, *http.Response, error)
+	// GetAjdustedAccountingValues returns adjusted accounting counter values.
+	GetAjdustedAccountingValues(ctx context.Context, subscriberId int) (SubscriberAdjustedAccounting, *http.Response, error)
+	// GetIfpSubscriberSessionStatistics returns the subscriber session statistics for a physical interface.
+	GetIfpSubscriberSessionStatistics(ctx context.Context, ifpName string) (IfpSubscriberSessionStatistics, *http.Response, error)
+	// GetL2BSASubscriber shows L2BSA subscriber session details.
+	GetL2BSASubscriber(ctx context.Context, ifpName string, anp int) (SubscriberSession, *http.Response, error)
+	// GetL2BSASubscriberAdjustedAccountingValues get adjusted accounting counter values.
+	GetL2BSASubscriberAdjustedAccountingValues(ctx context.Context, ifpName string, anp int) (SubscriberAdjustedAccounting, *http.Response, error)
+	// GetSubscriber shows subscriber session details.
+	GetSubscriber(ctx context.Context, subscriberId int) (SubscriberSession, *http.Response, error)
+	// GetSubscriberHistory lists the subscriber history.
+	GetSubscriberHistory(ctx context.Context, localVarOptionals *SubscriberApiGetSubscriberHistoryOpts) ([]SubscriberHistory, *http.Response, error)
+	// GetSubscriberSessionStatistics returns the subscriber session summary statistics.
+	GetSubscriberSessionStatistics(ctx context.Context) (SubscriberSessionStatistics, *http.Response, error)
+	// GetSubscribers lists all matching subscriber sessions.
+	GetSubscribers(ctx context.Context, localVarOptionals *SubscriberApiGetSubscribersOpts) ([]SubscriberSessionRef, *http.Response, error)
+	// GetTestSubscribers lists all test subscribers.
+	GetTestSubscribers(ctx context.Context) ([]TestAaaObject, *http.Response, error)
+	// RemoveTestSubscriber deletes a test subscriber.
+	RemoveTestSubscriber(ctx context.Context, subscriberId int) (*http.Response, error)
+	// StoreTestSubscriber adds or updates a test subscriber.
+	StoreTestSubscriber(ctx context.Context, body TestAaaObject, subscriberId int) (*http.Response, error)
+	// StoreTestSubscribers replaces all test subscribers.
+	StoreTestSubscribers(ctx context.Context, body []TestAaaObject) (*http.Response, error)
+	// UpdateSubscriber dynamically update the specified subscriber.
+	UpdateSubscriber(ctx context.Context, subscriberId int, localVarOptionals *SubscriberApiUpdateSubscriberOpts) (*http.Response, error)
+}
+
+// ensure, that SubscriberApiService does implement SubscriberApi.
+var _ SubscriberApi = &SubscriberApiService{}
+
+// SystemApi describes the operations of the SystemApiService.
+type SystemApi interface {
+	// GetSystemHardware shows system hardware inventory.
+	GetSystemHardware(ctx context.Context) (SystemHardware, *http.Response, error)
+}
+
+// ensure, that SystemApiService does implement SystemApi.
+var _ SystemApi = &SystemApiService{}
+
+// TransceiversApi describes the operations of the TransceiversApiService.
+type TransceiversApi interface {
+	// DisableTransceiverLaser disable the laser.
+	DisableTransceiverLaser(ctx context.Context, ifpName string) (*http.Response, error)
+	// EnableTransceiverLaser enables the laser.
+	EnableTransceiverLaser(ctx context.Context, ifpName string) (*http.Response, error)
+	// GetTransceiver lists details of an attached transceiver.
+	GetTransceiver(ctx context.Context, ifpName string) (Transceiver, *http.Response, error)
+	// GetTransceivers lists all attached transceivers.
+	GetTransceivers(ctx context.Context) ([]Transceiver, *http.Response, error)
+}
+
+// ensure, that TransceiversApiService does implement TransceiversApi.
+var _ TransceiversApi = &TransceiversApiService{}
@@ -0,0 +1,1350 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Code generated by apigen. DO NOT EDIT.
+
+// Package statemock provides testify mocks of the opsd API services.
+package statemock
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/stretchr/testify/mock"
+)
+
+// ensure, that A10NSPApi does implement state.A10NSPApi.
+var _ state.A10NSPApi = &A10NSPApi{}
+
+// A10NSPApi is a mock of state.A10NSPApi.
+type A10NSPApi struct {
+	mock.Mock
+}
+
+// GetA10NSPL2XEndpoint mocks state.A10NSPApi.GetA10NSPL2XEndpoint.
+func (m *A10NSPApi) GetA10NSPL2XEndpoint(ctx context.Context, lagInterfaceName string, sVlan int) ([]state.A10nspConfig, *http.Response, error) {
+	args := m.Called(ctx, lagInterfaceName, sVlan)
+	r0, _ := args.Get(0).([]state.A10nspConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetA10NSPL2XEndpoints mocks state.A10NSPApi.GetA10NSPL2XEndpoints.
+func (m *A10NSPApi) GetA10NSPL2XEndpoints(ctx context.Context) ([]state.A10nspConfig, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.A10nspConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetA10NSPL2XEndpointsOfLAGInterface mocks state.A10NSPApi.GetA10NSPL2XEndpointsOfLAGInterface.
+func (m *A10NSPApi) GetA10NSPL2XEndpointsOfLAGInterface(ctx context.Context, lagInterfaceName string) ([]state.A10nspConfig, *http.Response, error) {
+	args := m.Called(ctx, lagInterfaceName)
+	r0, _ := args.Get(0).([]state.A10nspConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetA10NSPL2XRangeEndpoint mocks state.A10NSPApi.GetA10NSPL2XRangeEndpoint.
+func (m *A10NSPApi) GetA10NSPL2XRangeEndpoint(ctx context.Context, lagInterfaceName string, sVlanLow int, sVlanHigh int) ([]state.A10nspConfig, *http.Response, error) {
+	args := m.Called(ctx, lagInterfaceName, sVlanLow, sVlanHigh)
+	r0, _ := args.Get(0).([]state.A10nspConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ProcessA10NSPL2XBatch mocks state.A10NSPApi.ProcessA10NSPL2XBatch.
+func (m *A10NSPApi) ProcessA10NSPL2XBatch(ctx context.Context, body state.A10nspConfigBatch) (*http.Response, error) {
+	args := m.Called(ctx, body)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// RemoveA10NSPL2XEndpoint mocks state.A10NSPApi.RemoveA10NSPL2XEndpoint.
+func (m *A10NSPApi) RemoveA10NSPL2XEndpoint(ctx context.Context, lagInterfaceName string, sVlan int) (*http.Response, error) {
+	args := m.Called(ctx, lagInterfaceName, sVlan)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// RemoveA10NSPL2XRangeEndpoint mocks state.A10NSPApi.RemoveA10NSPL2XRangeEndpoint.
+func (m *A10NSPApi) RemoveA10NSPL2XRangeEndpoint(ctx context.Context, lagInterfaceName string, sVlanLow int, sVlanHigh int) (*http.Response, error) {
+	args := m.Called(ctx, lagInterfaceName, sVlanLow, sVlanHigh)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ResizeA10NSPL2XRangeEndpoint mocks state.A10NSPApi.ResizeA10NSPL2XRangeEndpoint.
+func (m *A10NSPApi) ResizeA10NSPL2XRangeEndpoint(ctx context.Context, lagInterfaceName string, sVlanLow int, sVlanHigh int, localVarOptionals *state.A10NSPApiResizeA10NSPL2XRangeEndpointOpts) (*http.Response, error) {
+	args := m.Called(ctx, lagInterfaceName, sVlanLow, sVlanHigh, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StoreA10NSPL2XEndpoint mocks state.A10NSPApi.StoreA10NSPL2XEndpoint.
+func (m *A10NSPApi) StoreA10NSPL2XEndpoint(ctx context.Context, body state.A10nspConfig, lagInterfaceName string, sVlan int) (*http.Response, error) {
+	args := m.Called(ctx, body, lagInterfaceName, sVlan)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StoreA10NSPL2XEndpoints mocks state.A10NSPApi.StoreA10NSPL2XEndpoints.
+func (m *A10NSPApi) StoreA10NSPL2XEndpoints(ctx context.Context, body []state.A10nspConfig) (*http.Response, error) {
+	args := m.Called(ctx, body)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StoreA10NSPL2XEndpointsForLAGInterface mocks state.A10NSPApi.StoreA10NSPL2XEndpointsForLAGInterface.
+func (m *A10NSPApi) StoreA10NSPL2XEndpointsForLAGInterface(ctx context.Context, body []state.A10nspConfig, lagInterfaceName string) (*http.Response, error) {
+	args := m.Called(ctx, body, lagInterfaceName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StoreA10NSPL2XRangeEndpoint mocks state.A10NSPApi.StoreA10NSPL2XRangeEndpoint.
+func (m *A10NSPApi) StoreA10NSPL2XRangeEndpoint(ctx context.Context, body state.A10nspConfig, lagInterfaceName string, sVlanLow int, sVlanHigh int) (*http.Response, error) {
+	args := m.Called(ctx, body, lagInterfaceName, sVlanLow, sVlanHigh)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ensure, that AccessApi does implement state.AccessApi.
+var _ state.AccessApi = &AccessApi{}
+
+// AccessApi is a mock of state.AccessApi.
+type AccessApi struct {
+	mock.Mock
+}
+
+// AddVLANProfile mocks state.AccessApi.AddVLANProfile.
+func (m *AccessApi) AddVLANProfile(ctx context.Context, body state.VlanProfile, ifpName string, localVarOptionals *state.AccessApiAddVLANProfileOpts) (*http.Response, error) {
+	args := m.Called(ctx, body, ifpName, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// DeleteVLANProfile mocks state.AccessApi.DeleteVLANProfile.
+func (m *AccessApi) DeleteVLANProfile(ctx context.Context, ifpName string, localVarOptionals *state.AccessApiDeleteVLANProfileOpts) (*http.Response, error) {
+	args := m.Called(ctx, ifpName, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// FlushVLANProfiles mocks state.AccessApi.FlushVLANProfiles.
+func (m *AccessApi) FlushVLANProfiles(ctx context.Context, localVarOptionals *state.AccessApiFlushVLANProfilesOpts) (*http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetVLANProfiles mocks state.AccessApi.GetVLANProfiles.
+func (m *AccessApi) GetVLANProfiles(ctx context.Context, localVarOptionals *state.AccessApiGetVLANProfilesOpts) ([]state.VlanProfile, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).([]state.VlanProfile)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ProcessVLANProfilesBatch mocks state.AccessApi.ProcessVLANProfilesBatch.
+func (m *AccessApi) ProcessVLANProfilesBatch(ctx context.Context, body state.VlanProfileBatch) (*http.Response, error) {
+	args := m.Called(ctx, body)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StoreVLANProfiles mocks state.AccessApi.StoreVLANProfiles.
+func (m *AccessApi) StoreVLANProfiles(ctx context.Context, body []state.VlanProfile, localVarOptionals *state.AccessApiStoreVLANProfilesOpts) (*http.Response, error) {
+	args := m.Called(ctx, body, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ensure, that ActionsApi does implement state.ActionsApi.
+var _ state.ActionsApi = &ActionsApi{}
+
+// ActionsApi is a mock of state.ActionsApi.
+type ActionsApi struct {
+	mock.Mock
+}
+
+// Ping mocks state.ActionsApi.Ping.
+func (m *ActionsApi) Ping(ctx context.Context, localVarOptionals *state.ActionsApiPingOpts) (state.PingStatus, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).(state.PingStatus)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// PingMpls mocks state.ActionsApi.PingMpls.
+func (m *ActionsApi) PingMpls(ctx context.Context, localVarOptionals *state.ActionsApiPingMplsOpts) (state.PingStatus, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).(state.PingStatus)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// Traceroute mocks state.ActionsApi.Traceroute.
+func (m *ActionsApi) Traceroute(ctx context.Context, localVarOptionals *state.ActionsApiTracerouteOpts) (state.Traceroute, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).(state.Traceroute)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that BGPApi does implement state.BGPApi.
+var _ state.BGPApi = &BGPApi{}
+
+// BGPApi is a mock of state.BGPApi.
+type BGPApi struct {
+	mock.Mock
+}
+
+// ClearBGPInstancePeerings mocks state.BGPApi.ClearBGPInstancePeerings.
+func (m *BGPApi) ClearBGPInstancePeerings(ctx context.Context, instanceName string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearBGPPeering mocks state.BGPApi.ClearBGPPeering.
+func (m *BGPApi) ClearBGPPeering(ctx context.Context, instanceName string, peerIp string, localVarOptionals *state.BGPApiClearBGPPeeringOpts) (*http.Response, error) {
+	args := m.Called(ctx, instanceName, peerIp, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetBGPInstance mocks state.BGPApi.GetBGPInstance.
+func (m *BGPApi) GetBGPInstance(ctx context.Context, instanceName string) (state.BgpInstance, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(state.BgpInstance)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetBGPInstancePeerings mocks state.BGPApi.GetBGPInstancePeerings.
+func (m *BGPApi) GetBGPInstancePeerings(ctx context.Context, instanceName string, peerIp string, localVarOptionals *state.BGPApiGetBGPInstancePeeringsOpts) ([]state.BgpPeering, *http.Response, error) {
+	args := m.Called(ctx, instanceName, peerIp, localVarOptionals)
+	r0, _ := args.Get(0).([]state.BgpPeering)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetBGPInstances mocks state.BGPApi.GetBGPInstances.
+func (m *BGPApi) GetBGPInstances(ctx context.Context) ([]state.BgpInstanceRef, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.BgpInstanceRef)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetBGPPeering mocks state.BGPApi.GetBGPPeering.
+func (m *BGPApi) GetBGPPeering(ctx context.Context, instanceName string, peerIp string, localVarOptionals *state.BGPApiGetBGPPeeringOpts) (state.BgpPeering, *http.Response, error) {
+	args := m.Called(ctx, instanceName, peerIp, localVarOptionals)
+	r0, _ := args.Get(0).(state.BgpPeering)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetBGPPeerings mocks state.BGPApi.GetBGPPeerings.
+func (m *BGPApi) GetBGPPeerings(ctx context.Context) ([]state.BgpInstancePeerings, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.BgpInstancePeerings)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that CgNATApi does implement state.CgNATApi.
+var _ state.CgNATApi = &CgNATApi{}
+
+// CgNATApi is a mock of state.CgNATApi.
+type CgNATApi struct {
+	mock.Mock
+}
+
+// GetCgnatAddressTranslations mocks state.CgNATApi.GetCgnatAddressTranslations.
+func (m *CgNATApi) GetCgnatAddressTranslations(ctx context.Context, instance string, user string) (state.UserAddressTranslations, *http.Response, error) {
+	args := m.Called(ctx, instance, user)
+	r0, _ := args.Get(0).(state.UserAddressTranslations)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that FlowspecApi does implement state.FlowspecApi.
+var _ state.FlowspecApi = &FlowspecApi{}
+
+// FlowspecApi is a mock of state.FlowspecApi.
+type FlowspecApi struct {
+	mock.Mock
+}
+
+// FlowspecInstancesInstanceRulesAfiGet mocks state.FlowspecApi.FlowspecInstancesInstanceRulesAfiGet.
+func (m *FlowspecApi) FlowspecInstancesInstanceRulesAfiGet(ctx context.Context, instance string, afi string, localVarOptionals *state.FlowspecApiFlowspecInstancesInstanceRulesAfiGetOpts) ([]state.FlowspecRule, *http.Response, error) {
+	args := m.Called(ctx, instance, afi, localVarOptionals)
+	r0, _ := args.Get(0).([]state.FlowspecRule)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// FlowspecInstancesInstanceRulesGet mocks state.FlowspecApi.FlowspecInstancesInstanceRulesGet.
+func (m *FlowspecApi) FlowspecInstancesInstanceRulesGet(ctx context.Context, instance string, localVarOptionals *state.FlowspecApiFlowspecInstancesInstanceRulesGetOpts) (state.FlowspecInstanceRules, *http.Response, error) {
+	args := m.Called(ctx, instance, localVarOptionals)
+	r0, _ := args.Get(0).(state.FlowspecInstanceRules)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// FlowspecInstancesRulesGet mocks state.FlowspecApi.FlowspecInstancesRulesGet.
+func (m *FlowspecApi) FlowspecInstancesRulesGet(ctx context.Context, localVarOptionals *state.FlowspecApiFlowspecInstancesRulesGetOpts) ([]state.FlowspecInstanceRules, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).([]state.FlowspecInstanceRules)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that ISISApi does implement state.ISISApi.
+var _ state.ISISApi = &ISISApi{}
+
+// ISISApi is a mock of state.ISISApi.
+type ISISApi struct {
+	mock.Mock
+}
+
+// ClearISISInstanceNeighbors mocks state.ISISApi.ClearISISInstanceNeighbors.
+func (m *ISISApi) ClearISISInstanceNeighbors(ctx context.Context, instanceName string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearISISInterfaceNeighbors mocks state.ISISApi.ClearISISInterfaceNeighbors.
+func (m *ISISApi) ClearISISInterfaceNeighbors(ctx context.Context, instanceName string, iflName string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName, iflName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetISISInstance mocks state.ISISApi.GetISISInstance.
+func (m *ISISApi) GetISISInstance(ctx context.Context, instanceName string) (state.IsisInstance, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(state.IsisInstance)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetISISInstances mocks state.ISISApi.GetISISInstances.
+func (m *ISISApi) GetISISInstances(ctx context.Context) ([]state.IsisInstanceRef, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.IsisInstanceRef)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetISISInterface mocks state.ISISApi.GetISISInterface.
+func (m *ISISApi) GetISISInterface(ctx context.Context, instanceName string, iflName string) (state.IsisInstanceInterface, *http.Response, error) {
+	args := m.Called(ctx, instanceName, iflName)
+	r0, _ := args.Get(0).(state.IsisInstanceInterface)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetISISInterfaces mocks state.ISISApi.GetISISInterfaces.
+func (m *ISISApi) GetISISInterfaces(ctx context.Context) ([]state.IsisInstanceInterfaces, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.IsisInstanceInterfaces)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetISISNeighbor mocks state.ISISApi.GetISISNeighbor.
+func (m *ISISApi) GetISISNeighbor(ctx context.Context, instanceName string, iflName string) (state.IsisInstanceNeighbor, *http.Response, error) {
+	args := m.Called(ctx, instanceName, iflName)
+	r0, _ := args.Get(0).(state.IsisInstanceNeighbor)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetISISNeighbors mocks state.ISISApi.GetISISNeighbors.
+func (m *ISISApi) GetISISNeighbors(ctx context.Context) ([]state.IsisInstanceNeighbors, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.IsisInstanceNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that InterfacesApi does implement state.InterfacesApi.
+var _ state.InterfacesApi = &InterfacesApi{}
+
+// InterfacesApi is a mock of state.InterfacesApi.
+type InterfacesApi struct {
+	mock.Mock
+}
+
+// ClearInterfaceCounters mocks state.InterfacesApi.ClearInterfaceCounters.
+func (m *InterfacesApi) ClearInterfaceCounters(ctx context.Context, ifName string) (*http.Response, error) {
+	args := m.Called(ctx, ifName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearLogicalInterfaceCounters mocks state.InterfacesApi.ClearLogicalInterfaceCounters.
+func (m *InterfacesApi) ClearLogicalInterfaceCounters(ctx context.Context, iflName string) (*http.Response, error) {
+	args := m.Called(ctx, iflName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearPhysicalInterfaceCounters mocks state.InterfacesApi.ClearPhysicalInterfaceCounters.
+func (m *InterfacesApi) ClearPhysicalInterfaceCounters(ctx context.Context, ifpName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// DisableInterface mocks state.InterfacesApi.DisableInterface.
+func (m *InterfacesApi) DisableInterface(ctx context.Context, ifName string) (*http.Response, error) {
+	args := m.Called(ctx, ifName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// DisableLawfulInterceptionIFL mocks state.InterfacesApi.DisableLawfulInterceptionIFL.
+func (m *InterfacesApi) DisableLawfulInterceptionIFL(ctx context.Context, iflName string, id int) (*http.Response, error) {
+	args := m.Called(ctx, iflName, id)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// DisableLogicalInterface mocks state.InterfacesApi.DisableLogicalInterface.
+func (m *InterfacesApi) DisableLogicalInterface(ctx context.Context, iflName string) (*http.Response, error) {
+	args := m.Called(ctx, iflName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// DisablePhysicalInterface mocks state.InterfacesApi.DisablePhysicalInterface.
+func (m *InterfacesApi) DisablePhysicalInterface(ctx context.Context, ifpName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// EnableInterface mocks state.InterfacesApi.EnableInterface.
+func (m *InterfacesApi) EnableInterface(ctx context.Context, ifName string) (*http.Response, error) {
+	args := m.Called(ctx, ifName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// EnableLawfulInterceptionIFL mocks state.InterfacesApi.EnableLawfulInterceptionIFL.
+func (m *InterfacesApi) EnableLawfulInterceptionIFL(ctx context.Context, iflName string, id int, direction string, medIp string, medPort int, medInstance string) (*http.Response, error) {
+	args := m.Called(ctx, iflName, id, direction, medIp, medPort, medInstance)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// EnableLogicalInterface mocks state.InterfacesApi.EnableLogicalInterface.
+func (m *InterfacesApi) EnableLogicalInterface(ctx context.Context, iflName string) (*http.Response, error) {
+	args := m.Called(ctx, iflName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// EnablePhysicalInterface mocks state.InterfacesApi.EnablePhysicalInterface.
+func (m *InterfacesApi) EnablePhysicalInterface(ctx context.Context, ifpName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetInterface mocks state.InterfacesApi.GetInterface.
+func (m *InterfacesApi) GetInterface(ctx context.Context, ifpName string) (state.PhysicalInterfaceSummary, *http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(state.PhysicalInterfaceSummary)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetInterfaces mocks state.InterfacesApi.GetInterfaces.
+func (m *InterfacesApi) GetInterfaces(ctx context.Context) ([]state.PhysicalInterfaceSummaryRef, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.PhysicalInterfaceSummaryRef)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetLogicalInterface mocks state.InterfacesApi.GetLogicalInterface.
+func (m *InterfacesApi) GetLogicalInterface(ctx context.Context, iflName string) (state.LogicalInterface, *http.Response, error) {
+	args := m.Called(ctx, iflName)
+	r0, _ := args.Get(0).(state.LogicalInterface)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetPhysicalInterface mocks state.InterfacesApi.GetPhysicalInterface.
+func (m *InterfacesApi) GetPhysicalInterface(ctx context.Context, ifpName string, localVarOptionals *state.InterfacesApiGetPhysicalInterfaceOpts) (state.PhysicalInterfaceDetail, *http.Response, error) {
+	args := m.Called(ctx, ifpName, localVarOptionals)
+	r0, _ := args.Get(0).(state.PhysicalInterfaceDetail)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetPhysicalInterfaces mocks state.InterfacesApi.GetPhysicalInterfaces.
+func (m *InterfacesApi) GetPhysicalInterfaces(ctx context.Context, localVarOptionals *state.InterfacesApiGetPhysicalInterfacesOpts) ([]state.PhysicalInterface, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).([]state.PhysicalInterface)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// StartLogicalInterfaceTrafficCapture mocks state.InterfacesApi.StartLogicalInterfaceTrafficCapture.
+func (m *InterfacesApi) StartLogicalInterfaceTrafficCapture(ctx context.Context, iflName string, direction string, fileName string) (*http.Response, error) {
+	args := m.Called(ctx, iflName, direction, fileName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StartPhysicalInterfaceTrafficCapture mocks state.InterfacesApi.StartPhysicalInterfaceTrafficCapture.
+func (m *InterfacesApi) StartPhysicalInterfaceTrafficCapture(ctx context.Context, ifpName string, direction string, fileName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName, direction, fileName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StartTrafficCapture mocks state.InterfacesApi.StartTrafficCapture.
+func (m *InterfacesApi) StartTrafficCapture(ctx context.Context, ifName string, direction string, fileName string) (*http.Response, error) {
+	args := m.Called(ctx, ifName, direction, fileName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StopLogicalInterfaceTrafficCapture mocks state.InterfacesApi.StopLogicalInterfaceTrafficCapture.
+func (m *InterfacesApi) StopLogicalInterfaceTrafficCapture(ctx context.Context, iflName string, direction string, fileName string) (*http.Response, error) {
+	args := m.Called(ctx, iflName, direction, fileName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StopPhysicalInterfaceTrafficCapture mocks state.InterfacesApi.StopPhysicalInterfaceTrafficCapture.
+func (m *InterfacesApi) StopPhysicalInterfaceTrafficCapture(ctx context.Context, ifpName string, direction string, fileName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName, direction, fileName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StopTrafficCapture mocks state.InterfacesApi.StopTrafficCapture.
+func (m *InterfacesApi) StopTrafficCapture(ctx context.Context, ifName string, direction string, fileName string) (*http.Response, error) {
+	args := m.Called(ctx, ifName, direction, fileName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ensure, that L2BSAApi does implement state.L2BSAApi.
+var _ state.L2BSAApi = &L2BSAApi{}
+
+// L2BSAApi is a mock of state.L2BSAApi.
+type L2BSAApi struct {
+	mock.Mock
+}
+
+// GetL2BSAService mocks state.L2BSAApi.GetL2BSAService.
+func (m *L2BSAApi) GetL2BSAService(ctx context.Context, ifpName string) ([]state.L2bsaServiceConfig, *http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).([]state.L2bsaServiceConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetL2BSAServices mocks state.L2BSAApi.GetL2BSAServices.
+func (m *L2BSAApi) GetL2BSAServices(ctx context.Context) ([]state.L2bsaServiceConfig, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.L2bsaServiceConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetL2BSAServicesOfANP mocks state.L2BSAApi.GetL2BSAServicesOfANP.
+func (m *L2BSAApi) GetL2BSAServicesOfANP(ctx context.Context, ifpName string, anp int) (state.L2bsaServiceConfig, *http.Response, error) {
+	args := m.Called(ctx, ifpName, anp)
+	r0, _ := args.Get(0).(state.L2bsaServiceConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ProcessL2BSAServiceBatch mocks state.L2BSAApi.ProcessL2BSAServiceBatch.
+func (m *L2BSAApi) ProcessL2BSAServiceBatch(ctx context.Context, body state.L2bsaServiceConfigBatch) (*http.Response, error) {
+	args := m.Called(ctx, body)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// RemoveL2BSAServicesForANP mocks state.L2BSAApi.RemoveL2BSAServicesForANP.
+func (m *L2BSAApi) RemoveL2BSAServicesForANP(ctx context.Context, ifpName string, anp int) (*http.Response, error) {
+	args := m.Called(ctx, ifpName, anp)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StoreL2BSAService mocks state.L2BSAApi.StoreL2BSAService.
+func (m *L2BSAApi) StoreL2BSAService(ctx context.Context, body []state.L2bsaServiceConfig, ifpName string) ([]state.L2bsaServiceConfig, *http.Response, error) {
+	args := m.Called(ctx, body, ifpName)
+	r0, _ := args.Get(0).([]state.L2bsaServiceConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// StoreL2BSAServices mocks state.L2BSAApi.StoreL2BSAServices.
+func (m *L2BSAApi) StoreL2BSAServices(ctx context.Context, body []state.L2bsaServiceConfig) ([]state.L2bsaServiceConfig, *http.Response, error) {
+	args := m.Called(ctx, body)
+	r0, _ := args.Get(0).([]state.L2bsaServiceConfig)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// StoreL2BSAServicesForANP mocks state.L2BSAApi.StoreL2BSAServicesForANP.
+func (m *L2BSAApi) StoreL2BSAServicesForANP(ctx context.Context, body state.L2bsaServiceConfig, ifpName string, anp int) (*http.Response, error) {
+	args := m.Called(ctx, body, ifpName, anp)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ensure, that L2VPNApi does implement state.L2VPNApi.
+var _ state.L2VPNApi = &L2VPNApi{}
+
+// L2VPNApi is a mock of state.L2VPNApi.
+type L2VPNApi struct {
+	mock.Mock
+}
+
+// L2vpnCrossConnectsGet mocks state.L2VPNApi.L2vpnCrossConnectsGet.
+func (m *L2VPNApi) L2vpnCrossConnectsGet(ctx context.Context, localVarOptionals *state.L2VPNApiL2vpnCrossConnectsGetOpts) ([]state.L2VpnCrossConnect, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).([]state.L2VpnCrossConnect)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// L2vpnInstancesInstancePseudowiresGet mocks state.L2VPNApi.L2vpnInstancesInstancePseudowiresGet.
+func (m *L2VPNApi) L2vpnInstancesInstancePseudowiresGet(ctx context.Context, instance string, localVarOptionals *state.L2VPNApiL2vpnInstancesInstancePseudowiresGetOpts) (state.L2VpnInstancePseudowires, *http.Response, error) {
+	args := m.Called(ctx, instance, localVarOptionals)
+	r0, _ := args.Get(0).(state.L2VpnInstancePseudowires)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// L2vpnInstancesPseudowiresGet mocks state.L2VPNApi.L2vpnInstancesPseudowiresGet.
+func (m *L2VPNApi) L2vpnInstancesPseudowiresGet(ctx context.Context, localVarOptionals *state.L2VPNApiL2vpnInstancesPseudowiresGetOpts) ([]state.L2VpnInstancePseudowires, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).([]state.L2VpnInstancePseudowires)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that LDPApi does implement state.LDPApi.
+var _ state.LDPApi = &LDPApi{}
+
+// LDPApi is a mock of state.LDPApi.
+type LDPApi struct {
+	mock.Mock
+}
+
+// ClearLDPSession mocks state.LDPApi.ClearLDPSession.
+func (m *LDPApi) ClearLDPSession(ctx context.Context, instanceName string, peerIp string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName, peerIp)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearLDPSessions mocks state.LDPApi.ClearLDPSessions.
+func (m *LDPApi) ClearLDPSessions(ctx context.Context, instanceName string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetLDPInstance mocks state.LDPApi.GetLDPInstance.
+func (m *LDPApi) GetLDPInstance(ctx context.Context, instanceName string) (state.LdpInstanceSessions, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(state.LdpInstanceSessions)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetLDPInstances mocks state.LDPApi.GetLDPInstances.
+func (m *LDPApi) GetLDPInstances(ctx context.Context) (state.LdpInstanceRef, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).(state.LdpInstanceRef)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetLDPSession mocks state.LDPApi.GetLDPSession.
+func (m *LDPApi) GetLDPSession(ctx context.Context, instanceName string, peerIp string) (state.LdpInstanceSession, *http.Response, error) {
+	args := m.Called(ctx, instanceName, peerIp)
+	r0, _ := args.Get(0).(state.LdpInstanceSession)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetLDPSessions mocks state.LDPApi.GetLDPSessions.
+func (m *LDPApi) GetLDPSessions(ctx context.Context) (state.LdpInstanceSessions, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).(state.LdpInstanceSessions)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that LLDPApi does implement state.LLDPApi.
+var _ state.LLDPApi = &LLDPApi{}
+
+// LLDPApi is a mock of state.LLDPApi.
+type LLDPApi struct {
+	mock.Mock
+}
+
+// GetLLDPNeighbor mocks state.LLDPApi.GetLLDPNeighbor.
+func (m *LLDPApi) GetLLDPNeighbor(ctx context.Context, ifpName string) (state.LldpGlobalNeighbor, *http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(state.LldpGlobalNeighbor)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetLLDPNeighbors mocks state.LLDPApi.GetLLDPNeighbors.
+func (m *LLDPApi) GetLLDPNeighbors(ctx context.Context) (state.LldpGlobalNeighbors, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).(state.LldpGlobalNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that NeighborsApi does implement state.NeighborsApi.
+var _ state.NeighborsApi = &NeighborsApi{}
+
+// NeighborsApi is a mock of state.NeighborsApi.
+type NeighborsApi struct {
+	mock.Mock
+}
+
+// GetInstanceNeighbors mocks state.NeighborsApi.GetInstanceNeighbors.
+func (m *NeighborsApi) GetInstanceNeighbors(ctx context.Context, instanceName string, localVarOptionals *state.NeighborsApiGetInstanceNeighborsOpts) (state.ArpndpInstanceNeighbors, *http.Response, error) {
+	args := m.Called(ctx, instanceName, localVarOptionals)
+	r0, _ := args.Get(0).(state.ArpndpInstanceNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetNeighbors mocks state.NeighborsApi.GetNeighbors.
+func (m *NeighborsApi) GetNeighbors(ctx context.Context, localVarOptionals *state.NeighborsApiGetNeighborsOpts) ([]state.ArpndpInstanceNeighbors, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).([]state.ArpndpInstanceNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that OSPFApi does implement state.OSPFApi.
+var _ state.OSPFApi = &OSPFApi{}
+
+// OSPFApi is a mock of state.OSPFApi.
+type OSPFApi struct {
+	mock.Mock
+}
+
+// ClearOSPFAreaNeighbor mocks state.OSPFApi.ClearOSPFAreaNeighbor.
+func (m *OSPFApi) ClearOSPFAreaNeighbor(ctx context.Context, instanceName string, areaId string, iflName string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName, areaId, iflName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearOSPFNeighbor mocks state.OSPFApi.ClearOSPFNeighbor.
+func (m *OSPFApi) ClearOSPFNeighbor(ctx context.Context, instanceName string, iflName string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName, iflName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearOSPFv3Neighbor mocks state.OSPFApi.ClearOSPFv3Neighbor.
+func (m *OSPFApi) ClearOSPFv3Neighbor(ctx context.Context, instanceName string, instanceId int, iflName string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName, instanceId, iflName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetOSPFInstance mocks state.OSPFApi.GetOSPFInstance.
+func (m *OSPFApi) GetOSPFInstance(ctx context.Context, instanceName string) (state.OspfInstanceDetails, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(state.OspfInstanceDetails)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFInstanceInterfaces mocks state.OSPFApi.GetOSPFInstanceInterfaces.
+func (m *OSPFApi) GetOSPFInstanceInterfaces(ctx context.Context, instanceName string) (state.OspfInstanceInterfaces, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(state.OspfInstanceInterfaces)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFInstanceNeighbors mocks state.OSPFApi.GetOSPFInstanceNeighbors.
+func (m *OSPFApi) GetOSPFInstanceNeighbors(ctx context.Context, instanceName string) (state.OspfInstanceNeighbors, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(state.OspfInstanceNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFInstances mocks state.OSPFApi.GetOSPFInstances.
+func (m *OSPFApi) GetOSPFInstances(ctx context.Context) ([]state.OspfInstanceSummary, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.OspfInstanceSummary)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFInterface mocks state.OSPFApi.GetOSPFInterface.
+func (m *OSPFApi) GetOSPFInterface(ctx context.Context, instanceName string, iflName string) (state.OspfInstanceInterface, *http.Response, error) {
+	args := m.Called(ctx, instanceName, iflName)
+	r0, _ := args.Get(0).(state.OspfInstanceInterface)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFInterfaces mocks state.OSPFApi.GetOSPFInterfaces.
+func (m *OSPFApi) GetOSPFInterfaces(ctx context.Context) ([]state.OspfInstanceInterfaces, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.OspfInstanceInterfaces)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFNeighbor mocks state.OSPFApi.GetOSPFNeighbor.
+func (m *OSPFApi) GetOSPFNeighbor(ctx context.Context, instanceName string, iflName string) (state.OspfInstanceNeighbor, *http.Response, error) {
+	args := m.Called(ctx, instanceName, iflName)
+	r0, _ := args.Get(0).(state.OspfInstanceNeighbor)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFNeighbors mocks state.OSPFApi.GetOSPFNeighbors.
+func (m *OSPFApi) GetOSPFNeighbors(ctx context.Context) (state.OspfInstanceNeighbors, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).(state.OspfInstanceNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFv3Instance mocks state.OSPFApi.GetOSPFv3Instance.
+func (m *OSPFApi) GetOSPFv3Instance(ctx context.Context, instanceName string, instanceId int) (state.Ospfv3InstanceDetails, *http.Response, error) {
+	args := m.Called(ctx, instanceName, instanceId)
+	r0, _ := args.Get(0).(state.Ospfv3InstanceDetails)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFv3InstanceInterfaces mocks state.OSPFApi.GetOSPFv3InstanceInterfaces.
+func (m *OSPFApi) GetOSPFv3InstanceInterfaces(ctx context.Context, instanceName string, instanceId int) (state.Ospfv3InstanceInterfaces, *http.Response, error) {
+	args := m.Called(ctx, instanceName, instanceId)
+	r0, _ := args.Get(0).(state.Ospfv3InstanceInterfaces)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFv3InstanceNeighbors mocks state.OSPFApi.GetOSPFv3InstanceNeighbors.
+func (m *OSPFApi) GetOSPFv3InstanceNeighbors(ctx context.Context, instanceName string, instanceId int) (state.Ospfv3InstanceNeighbors, *http.Response, error) {
+	args := m.Called(ctx, instanceName, instanceId)
+	r0, _ := args.Get(0).(state.Ospfv3InstanceNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFv3Instances mocks state.OSPFApi.GetOSPFv3Instances.
+func (m *OSPFApi) GetOSPFv3Instances(ctx context.Context) ([]state.Ospfv3InstanceSummary, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.Ospfv3InstanceSummary)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFv3Interface mocks state.OSPFApi.GetOSPFv3Interface.
+func (m *OSPFApi) GetOSPFv3Interface(ctx context.Context, instanceName string, instanceId int, iflName string) (state.Ospfv3InstanceInterface, *http.Response, error) {
+	args := m.Called(ctx, instanceName, instanceId, iflName)
+	r0, _ := args.Get(0).(state.Ospfv3InstanceInterface)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFv3Interfaces mocks state.OSPFApi.GetOSPFv3Interfaces.
+func (m *OSPFApi) GetOSPFv3Interfaces(ctx context.Context) ([]state.Ospfv3InstanceInterfaces, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.Ospfv3InstanceInterfaces)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFv3Neighbor mocks state.OSPFApi.GetOSPFv3Neighbor.
+func (m *OSPFApi) GetOSPFv3Neighbor(ctx context.Context, instanceName string, instanceId string, iflName string) (state.Ospfv3InstanceNeighbor, *http.Response, error) {
+	args := m.Called(ctx, instanceName, instanceId, iflName)
+	r0, _ := args.Get(0).(state.Ospfv3InstanceNeighbor)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOSPFv3Neighbors mocks state.OSPFApi.GetOSPFv3Neighbors.
+func (m *OSPFApi) GetOSPFv3Neighbors(ctx context.Context) (state.Ospfv3InstanceNeighbors, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).(state.Ospfv3InstanceNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that OpticsApi does implement state.OpticsApi.
+var _ state.OpticsApi = &OpticsApi{}
+
+// OpticsApi is a mock of state.OpticsApi.
+type OpticsApi struct {
+	mock.Mock
+}
+
+// DisableLaser mocks state.OpticsApi.DisableLaser.
+func (m *OpticsApi) DisableLaser(ctx context.Context, ifpName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// EnableLaser mocks state.OpticsApi.EnableLaser.
+func (m *OpticsApi) EnableLaser(ctx context.Context, ifpName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetOptic mocks state.OpticsApi.GetOptic.
+func (m *OpticsApi) GetOptic(ctx context.Context, ifpName string) (state.Optic, *http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(state.Optic)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetOptics mocks state.OpticsApi.GetOptics.
+func (m *OpticsApi) GetOptics(ctx context.Context) ([]state.Optic, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.Optic)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that PIMApi does implement state.PIMApi.
+var _ state.PIMApi = &PIMApi{}
+
+// PIMApi is a mock of state.PIMApi.
+type PIMApi struct {
+	mock.Mock
+}
+
+// GetPIMInstance mocks state.PIMApi.GetPIMInstance.
+func (m *PIMApi) GetPIMInstance(ctx context.Context, instanceName string) (state.PimInstance, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(state.PimInstance)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetPIMInstances mocks state.PIMApi.GetPIMInstances.
+func (m *PIMApi) GetPIMInstances(ctx context.Context) ([]state.PimInstanceRef, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.PimInstanceRef)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetPIMInterface mocks state.PIMApi.GetPIMInterface.
+func (m *PIMApi) GetPIMInterface(ctx context.Context, instanceName string, iflName string) (state.PimInterface, *http.Response, error) {
+	args := m.Called(ctx, instanceName, iflName)
+	r0, _ := args.Get(0).(state.PimInterface)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetPIMInterfaces mocks state.PIMApi.GetPIMInterfaces.
+func (m *PIMApi) GetPIMInterfaces(ctx context.Context) ([]state.PimInterfaces, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.PimInterfaces)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetPIMNeighbor mocks state.PIMApi.GetPIMNeighbor.
+func (m *PIMApi) GetPIMNeighbor(ctx context.Context, instanceName string, neighborIp string) (state.PimNeighbor, *http.Response, error) {
+	args := m.Called(ctx, instanceName, neighborIp)
+	r0, _ := args.Get(0).(state.PimNeighbor)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetPIMNeighbors mocks state.PIMApi.GetPIMNeighbors.
+func (m *PIMApi) GetPIMNeighbors(ctx context.Context) ([]state.PimNeighbors, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.PimNeighbors)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that RPKIRTRApi does implement state.RPKIRTRApi.
+var _ state.RPKIRTRApi = &RPKIRTRApi{}
+
+// RPKIRTRApi is a mock of state.RPKIRTRApi.
+type RPKIRTRApi struct {
+	mock.Mock
+}
+
+// ClearRPKIRTRDatabase mocks state.RPKIRTRApi.ClearRPKIRTRDatabase.
+func (m *RPKIRTRApi) ClearRPKIRTRDatabase(ctx context.Context, instanceName string) (*http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearRPKIRTRInstance mocks state.RPKIRTRApi.ClearRPKIRTRInstance.
+func (m *RPKIRTRApi) ClearRPKIRTRInstance(ctx context.Context, instanceName string, localVarOptionals *state.RPKIRTRApiClearRPKIRTRInstanceOpts) (*http.Response, error) {
+	args := m.Called(ctx, instanceName, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearRPKIRTRSession mocks state.RPKIRTRApi.ClearRPKIRTRSession.
+func (m *RPKIRTRApi) ClearRPKIRTRSession(ctx context.Context, instanceName string, cacheIp state.CacheIp2, localVarOptionals *state.RPKIRTRApiClearRPKIRTRSessionOpts) (*http.Response, error) {
+	args := m.Called(ctx, instanceName, cacheIp, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetRPKIRTRInstance mocks state.RPKIRTRApi.GetRPKIRTRInstance.
+func (m *RPKIRTRApi) GetRPKIRTRInstance(ctx context.Context, instanceName string) (state.RpkirtrInstance, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(state.RpkirtrInstance)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetRPKIRTRInstances mocks state.RPKIRTRApi.GetRPKIRTRInstances.
+func (m *RPKIRTRApi) GetRPKIRTRInstances(ctx context.Context) ([]state.RpkirtrInstance, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.RpkirtrInstance)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetRPKIRTRRecord mocks state.RPKIRTRApi.GetRPKIRTRRecord.
+func (m *RPKIRTRApi) GetRPKIRTRRecord(ctx context.Context, instanceName string, prefix state.Prefix) (state.RpkirtrRecord, *http.Response, error) {
+	args := m.Called(ctx, instanceName, prefix)
+	r0, _ := args.Get(0).(state.RpkirtrRecord)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetRPKIRTRSession mocks state.RPKIRTRApi.GetRPKIRTRSession.
+func (m *RPKIRTRApi) GetRPKIRTRSession(ctx context.Context, instanceName string, cacheIp state.CacheIp1, localVarOptionals *state.RPKIRTRApiGetRPKIRTRSessionOpts) (state.RpkirtrSession, *http.Response, error) {
+	args := m.Called(ctx, instanceName, cacheIp, localVarOptionals)
+	r0, _ := args.Get(0).(state.RpkirtrSession)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetRPKIRTRSessions mocks state.RPKIRTRApi.GetRPKIRTRSessions.
+func (m *RPKIRTRApi) GetRPKIRTRSessions(ctx context.Context, instanceName string, cacheIp state.CacheIp, localVarOptionals *state.RPKIRTRApiGetRPKIRTRSessionsOpts) ([]state.RpkirtrSession, *http.Response, error) {
+	args := m.Called(ctx, instanceName, cacheIp, localVarOptionals)
+	r0, _ := args.Get(0).([]state.RpkirtrSession)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that RoutesApi does implement state.RoutesApi.
+var _ state.RoutesApi = &RoutesApi{}
+
+// RoutesApi is a mock of state.RoutesApi.
+type RoutesApi struct {
+	mock.Mock
+}
+
+// GetInstanceMulticastRoutes mocks state.RoutesApi.GetInstanceMulticastRoutes.
+func (m *RoutesApi) GetInstanceMulticastRoutes(ctx context.Context, instanceName string, afi string, safi string, localVarOptionals *state.RoutesApiGetInstanceMulticastRoutesOpts) (state.InstanceMulticastRoutes, *http.Response, error) {
+	args := m.Called(ctx, instanceName, afi, safi, localVarOptionals)
+	r0, _ := args.Get(0).(state.InstanceMulticastRoutes)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetInstanceRoutesSummary mocks state.RoutesApi.GetInstanceRoutesSummary.
+func (m *RoutesApi) GetInstanceRoutesSummary(ctx context.Context, instanceName string) (map[string]state.RoutesSummary, *http.Response, error) {
+	args := m.Called(ctx, instanceName)
+	r0, _ := args.Get(0).(map[string]state.RoutesSummary)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetInstanceUnicastRoutes mocks state.RoutesApi.GetInstanceUnicastRoutes.
+func (m *RoutesApi) GetInstanceUnicastRoutes(ctx context.Context, instanceName string, afi string, safi string, localVarOptionals *state.RoutesApiGetInstanceUnicastRoutesOpts) (state.InstanceUnicastRoutes, *http.Response, error) {
+	args := m.Called(ctx, instanceName, afi, safi, localVarOptionals)
+	r0, _ := args.Get(0).(state.InstanceUnicastRoutes)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetRoutesSummary mocks state.RoutesApi.GetRoutesSummary.
+func (m *RoutesApi) GetRoutesSummary(ctx context.Context) (map[string]state.RoutesSummary, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).(map[string]state.RoutesSummary)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that StreamsApi does implement state.StreamsApi.
+var _ state.StreamsApi = &StreamsApi{}
+
+// StreamsApi is a mock of state.StreamsApi.
+type StreamsApi struct {
+	mock.Mock
+}
+
+// StreamPing mocks state.StreamsApi.StreamPing.
+func (m *StreamsApi) StreamPing(ctx context.Context, localVarOptionals *state.StreamsApiStreamPingOpts) (string, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).(string)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// StreamPingMpls mocks state.StreamsApi.StreamPingMpls.
+func (m *StreamsApi) StreamPingMpls(ctx context.Context, localVarOptionals *state.StreamsApiStreamPingMplsOpts) (string, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).(string)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// StreamTraceroute mocks state.StreamsApi.StreamTraceroute.
+func (m *StreamsApi) StreamTraceroute(ctx context.Context, localVarOptionals *state.StreamsApiStreamTracerouteOpts) (string, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).(string)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that SubscriberApi does implement state.SubscriberApi.
+var _ state.SubscriberApi = &SubscriberApi{}
+
+// SubscriberApi is a mock of state.SubscriberApi.
+type SubscriberApi struct {
+	mock.Mock
+}
+
+// AddTestSubscribers mocks state.SubscriberApi.AddTestSubscribers.
+func (m *SubscriberApi) AddTestSubscribers(ctx context.Context, body []state.TestAaaObject) (*http.Response, error) {
+	args := m.Called(ctx, body)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearL2BSASubscriber mocks state.SubscriberApi.ClearL2BSASubscriber.
+func (m *SubscriberApi) ClearL2BSASubscriber(ctx context.Context, ifpName string, anp int) (*http.Response, error) {
+	args := m.Called(ctx, ifpName, anp)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearSubscriber mocks state.SubscriberApi.ClearSubscriber.
+func (m *SubscriberApi) ClearSubscriber(ctx context.Context, subscriberId int, localVarOptionals *state.SubscriberApiClearSubscriberOpts) (*http.Response, error) {
+	args := m.Called(ctx, subscriberId, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ClearSubscribers mocks state.SubscriberApi.ClearSubscribers.
+func (m *SubscriberApi) ClearSubscribers(ctx context.Context, localVarOptionals *state.SubscriberApiClearSubscribersOpts) (*http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// DisableLawfulInterceptionSubscriber mocks state.SubscriberApi.DisableLawfulInterceptionSubscriber.
+func (m *SubscriberApi) DisableLawfulInterceptionSubscriber(ctx context.Context, subscriberId int, id int) (*http.Response, error) {
+	args := m.Called(ctx, subscriberId, id)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// EnableLawfulInterceptionSubscriber mocks state.SubscriberApi.EnableLawfulInterceptionSubscriber.
+func (m *SubscriberApi) EnableLawfulInterceptionSubscriber(ctx context.Context, subscriberId int, id int, direction string, medIp string, medPort int, medInstance string) (*http.Response, error) {
+	args := m.Called(ctx, subscriberId, id, direction, medIp, medPort, medInstance)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetAccessLineInfo mocks state.SubscriberApi.GetAccessLineInfo.
+func (m *SubscriberApi) GetAccessLineInfo(ctx context.Context, subscriberId int) (state.AccessLineInfo, *http.Response, error) {
+	args := m.Called(ctx, subscriberId)
+	r0, _ := args.Get(0).(state.AccessLineInfo)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetAjdustedAccountingValues mocks state.SubscriberApi.GetAjdustedAccountingValues.
+func (m *SubscriberApi) GetAjdustedAccountingValues(ctx context.Context, subscriberId int) (state.SubscriberAdjustedAccounting, *http.Response, error) {
+	args := m.Called(ctx, subscriberId)
+	r0, _ := args.Get(0).(state.SubscriberAdjustedAccounting)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetIfpSubscriberSessionStatistics mocks state.SubscriberApi.GetIfpSubscriberSessionStatistics.
+func (m *SubscriberApi) GetIfpSubscriberSessionStatistics(ctx context.Context, ifpName string) (state.IfpSubscriberSessionStatistics, *http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(state.IfpSubscriberSessionStatistics)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetL2BSASubscriber mocks state.SubscriberApi.GetL2BSASubscriber.
+func (m *SubscriberApi) GetL2BSASubscriber(ctx context.Context, ifpName string, anp int) (state.SubscriberSession, *http.Response, error) {
+	args := m.Called(ctx, ifpName, anp)
+	r0, _ := args.Get(0).(state.SubscriberSession)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetL2BSASubscriberAdjustedAccountingValues mocks state.SubscriberApi.GetL2BSASubscriberAdjustedAccountingValues.
+func (m *SubscriberApi) GetL2BSASubscriberAdjustedAccountingValues(ctx context.Context, ifpName string, anp int) (state.SubscriberAdjustedAccounting, *http.Response, error) {
+	args := m.Called(ctx, ifpName, anp)
+	r0, _ := args.Get(0).(state.SubscriberAdjustedAccounting)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetSubscriber mocks state.SubscriberApi.GetSubscriber.
+func (m *SubscriberApi) GetSubscriber(ctx context.Context, subscriberId int) (state.SubscriberSession, *http.Response, error) {
+	args := m.Called(ctx, subscriberId)
+	r0, _ := args.Get(0).(state.SubscriberSession)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetSubscriberHistory mocks state.SubscriberApi.GetSubscriberHistory.
+func (m *SubscriberApi) GetSubscriberHistory(ctx context.Context, localVarOptionals *state.SubscriberApiGetSubscriberHistoryOpts) ([]state.SubscriberHistory, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).([]state.SubscriberHistory)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetSubscriberSessionStatistics mocks state.SubscriberApi.GetSubscriberSessionStatistics.
+func (m *SubscriberApi) GetSubscriberSessionStatistics(ctx context.Context) (state.SubscriberSessionStatistics, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).(state.SubscriberSessionStatistics)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetSubscribers mocks state.SubscriberApi.GetSubscribers.
+func (m *SubscriberApi) GetSubscribers(ctx context.Context, localVarOptionals *state.SubscriberApiGetSubscribersOpts) ([]state.SubscriberSessionRef, *http.Response, error) {
+	args := m.Called(ctx, localVarOptionals)
+	r0, _ := args.Get(0).([]state.SubscriberSessionRef)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetTestSubscribers mocks state.SubscriberApi.GetTestSubscribers.
+func (m *SubscriberApi) GetTestSubscribers(ctx context.Context) ([]state.TestAaaObject, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.TestAaaObject)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// RemoveTestSubscriber mocks state.SubscriberApi.RemoveTestSubscriber.
+func (m *SubscriberApi) RemoveTestSubscriber(ctx context.Context, subscriberId int) (*http.Response, error) {
+	args := m.Called(ctx, subscriberId)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StoreTestSubscriber mocks state.SubscriberApi.StoreTestSubscriber.
+func (m *SubscriberApi) StoreTestSubscriber(ctx context.Context, body state.TestAaaObject, subscriberId int) (*http.Response, error) {
+	args := m.Called(ctx, body, subscriberId)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// StoreTestSubscribers mocks state.SubscriberApi.StoreTestSubscribers.
+func (m *SubscriberApi) StoreTestSubscribers(ctx context.Context, body []state.TestAaaObject) (*http.Response, error) {
+	args := m.Called(ctx, body)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// UpdateSubscriber mocks state.SubscriberApi.UpdateSubscriber.
+func (m *SubscriberApi) UpdateSubscriber(ctx context.Context, subscriberId int, localVarOptionals *state.SubscriberApiUpdateSubscriberOpts) (*http.Response, error) {
+	args := m.Called(ctx, subscriberId, localVarOptionals)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// ensure, that SystemApi does implement state.SystemApi.
+var _ state.SystemApi = &SystemApi{}
+
+// SystemApi is a mock of state.SystemApi.
+type SystemApi struct {
+	mock.Mock
+}
+
+// GetSystemHardware mocks state.SystemApi.GetSystemHardware.
+func (m *SystemApi) GetSystemHardware(ctx context.Context) (state.SystemHardware, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).(state.SystemHardware)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// ensure, that TransceiversApi does implement state.TransceiversApi.
+var _ state.TransceiversApi = &TransceiversApi{}
+
+// TransceiversApi is a mock of state.TransceiversApi.
+type TransceiversApi struct {
+	mock.Mock
+}
+
+// DisableTransceiverLaser mocks state.TransceiversApi.DisableTransceiverLaser.
+func (m *TransceiversApi) DisableTransceiverLaser(ctx context.Context, ifpName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// EnableTransceiverLaser mocks state.TransceiversApi.EnableTransceiverLaser.
+func (m *TransceiversApi) EnableTransceiverLaser(ctx context.Context, ifpName string) (*http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(*http.Response)
+	return r0, args.Error(1)
+}
+
+// GetTransceiver mocks state.TransceiversApi.GetTransceiver.
+func (m *TransceiversApi) GetTransceiver(ctx context.Context, ifpName string) (state.Transceiver, *http.Response, error) {
+	args := m.Called(ctx, ifpName)
+	r0, _ := args.Get(0).(state.Transceiver)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
+
+// GetTransceivers mocks state.TransceiversApi.GetTransceivers.
+func (m *TransceiversApi) GetTransceivers(ctx context.Context) ([]state.Transceiver, *http.Response, error) {
+	args := m.Called(ctx)
+	r0, _ := args.Get(0).([]state.Transceiver)
+	r1, _ := args.Get(1).(*http.Response)
+	return r0, r1, args.Error(2)
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/openapi"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+const (
+	// UnknownField reports a response attribute not defined by the opsd API specification.
+	UnknownField = DecodeIssueKind(openapi.UnknownField)
+	// TypeMismatch reports a response attribute of another type than defined by the opsd API specification.
+	TypeMismatch = DecodeIssueKind(openapi.TypeMismatch)
+	// MissingField reports a required request parameter or body attribute, which is not present.
+	MissingField = DecodeIssueKind(openapi.MissingField)
+	// InvalidValue reports a request parameter or body attribute not listed by the enum, out of the range or not
+	// matching the format defined by the opsd API specification.
+	InvalidValue = DecodeIssueKind(openapi.InvalidValue)
+)
+
+type (
+	// DecodeIssueKind describes the kind of a decode issue or a request validation error.
+	DecodeIssueKind string
+
+	// DecodeIssue describes a response attribute, which does not match the opsd API specification the client has
+	// been generated from.
+	DecodeIssue struct {
+		// Operation holds the operation ID (e.g. getBGPInstances).
+		Operation string
+		// Field holds the path of the attribute (e.g. peerings[0].peer.ipv4_address).
+		// The field is empty if the response body itself does not match.
+		Field   string
+		Kind    DecodeIssueKind
+		Message string
+	}
+
+	// DecodeError reports the decode issues of a response in strict decoding mode.
+	DecodeError struct {
+		Operation string
+		Issues    []DecodeIssue
+	}
+
+	// strictTransport checks the JSON responses of the opsd API against the opsd API specification.
+	strictTransport struct {
+		next     http.RoundTripper
+		basePath string
+		// report receives the decode issues. Responses with issues are rejected with a DecodeError if nil.
+		report func(DecodeIssue)
+	}
+
+	// layeredTransport is a transport added by wrapTransport. It keeps the wrapped transport and the wrap function
+	// to rebuild the layer on top of a transport with updated TLS settings.
+	layeredTransport struct {
+		http.RoundTripper
+		next http.RoundTripper
+		wrap func(http.RoundTripper) http.RoundTripper
+	}
+)
+
+func (i DecodeIssue) String() string {
+	if i.Field == "" {
+		return fmt.Sprintf("%s: %s", i.Operation, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Operation, i.Field, i.Message)
+}
+
+func (e *DecodeError) Error() string {
+	var issues []string
+	for _, issue := range e.Issues {
+		if issue.Field == "" {
+			issues = append(issues, issue.Message)
+		} else {
+			issues = append(issues, issue.Field+": "+issue.Message)
+		}
+	}
+	return fmt.Sprintf("response of %s does not match the API specification: %s", e.Operation, strings.Join(issues, "; "))
+}
+
+// StrictDecoding returns an option to check all successful JSON responses against the opsd API specification the
+// client has been generated from. The given function is invoked for every response attribute not defined by the
+// specification and for every attribute of another type than specified. The response is passed to the caller
+// unchanged, where unknown attributes are dropped and type mismatches fail the decoding.
+//
+// Use this option to detect API drift between the client and the RBFS release running on an element.
+func StrictDecoding(report func(DecodeIssue)) Option {
+	return func(c *state.Configuration) {
+		wrapTransport(c, func(next http.RoundTripper) http.RoundTripper {
+			return &strictTransport{next: next, basePath: basePath(c), report: report}
+		})
+	}
+}
+
+// StrictDecodingErrors returns an option to reject all successful JSON responses not matching the opsd API
+// specification the client has been generated from with a DecodeError. See StrictDecoding.
+func StrictDecodingErrors() Option {
+	return StrictDecoding(nil)
+}
+
+// wrapTransport wraps the transport of the configuration's HTTP client.
+// The HTTP client is cloned to not affect other users of the HTTP client.
+func wrapTransport(c *state.Configuration, wrap func(http.RoundTripper) http.RoundTripper) {
+	client := &http.Client{}
+	if c.HTTPClient != nil {
+		*client = *c.HTTPClient
+	}
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.Transport = newLayeredTransport(next, wrap)
+	c.HTTPClient = client
+}
+
+func newLayeredTransport(next http.RoundTripper, wrap func(http.RoundTripper) http.RoundTripper) *layeredTransport {
+	return &layeredTransport{RoundTripper: wrap(next), next: next, wrap: wrap}
+}
+
+// Unwrap returns the wrapped transport.
+func (t *layeredTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport.
+func (t *layeredTransport) CloseIdleConnections() {
+	if closer, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// basePath returns the path of the API endpoint of the configuration.
+func basePath(c *state.Configuration) string {
+	endpoint, err := url.Parse(c.BasePath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(endpoint.Path, "/")
+}
+
+func (t *strictTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 ||
+		!strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
+		return resp, err
+	}
+
+	spec, err := openapi.Opsd()
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	op, _, ok := spec.Match(req.Method, strings.TrimPrefix(req.URL.Path, t.basePath))
+	if !ok {
+		return resp, nil
+	}
+	response, err := spec.Response(op.Operation, strconv.Itoa(resp.StatusCode))
+	if err != nil || response == nil || openapi.JSONSchema(response.Content) == nil {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		// The client reports invalid JSON
+		return resp, nil
+	}
+	violations, err := spec.Check(openapi.JSONSchema(response.Content), value)
+	if err != nil || len(violations) == 0 {
+		return resp, nil
+	}
+
+	issues := make([]DecodeIssue, 0, len(violations))
+	for _, violation := range violations {
+		issues = append(issues, DecodeIssue{
+			Operation: op.ID(),
+			Field:     violation.Field,
+			Kind:      DecodeIssueKind(violation.Kind),
+			Message:   violation.Message,
+		})
+	}
+	if t.report == nil {
+		return nil, &DecodeError{Operation: op.ID(), Issues: issues}
+	}
+	for _, issue := range issues {
+		t.report(issue)
+	}
+	return resp, nil
+}
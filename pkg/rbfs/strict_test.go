@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// jsonServer answers all requests with the given status and JSON body.
+func jsonServer(t *testing.T, status int, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStrictDecoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   []DecodeIssue
+	}{
+		{
+			name:   "valid",
+			status: http.StatusOK,
+			body:   `[{"instance_name":"default","asn":65000,"router_id":"192.0.2.1"}]`,
+		},
+		{
+			name:   "unknown field",
+			status: http.StatusOK,
+			body:   `[{"instance_name":"default","asn":65000,"cluster_id":"192.0.2.1"}]`,
+			want: []DecodeIssue{
+				{Operation: "getBGPInstances", Field: "[0].cluster_id", Kind: UnknownField, Message: "unknown attribute"},
+			},
+		},
+		{
+			name:   "type mismatch",
+			status: http.StatusOK,
+			body:   `[{"instance_name":"default","asn":"65000"},{"asn":1.5}]`,
+			want: []DecodeIssue{
+				{Operation: "getBGPInstances", Field: "[0].asn", Kind: TypeMismatch, Message: "expected integer, got string"},
+				{Operation: "getBGPInstances", Field: "[1].asn", Kind: TypeMismatch, Message: "expected integer, got number"},
+			},
+		},
+		{
+			name:   "error response",
+			status: http.StatusNotFound,
+			body:   `{"error":"not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := jsonServer(t, tt.status, tt.body)
+			var issues []DecodeIssue
+			client := GetAPIClient(http.DefaultClient, mustParse(t, server.URL+"/api/v1/rbfs/elements/leaf1/services/opsd/proxy"),
+				StrictDecoding(func(issue DecodeIssue) {
+					issues = append(issues, issue)
+				}))
+			_, response, _ := client.BGPApi.GetBGPInstances(context.Background())
+			require.Equal(t, tt.status, response.StatusCode)
+			require.Equal(t, tt.want, issues)
+		})
+	}
+}
+
+func TestStrictDecodingErrors(t *testing.T) {
+	server := jsonServer(t, http.StatusOK, `{"instance_name":"default","state":"UP"}`)
+	client := GetAPIClient(http.DefaultClient, mustParse(t, server.URL), StrictDecodingErrors())
+
+	_, _, err := client.BGPApi.GetBGPInstance(context.Background(), "default")
+	var decodeErr *DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	require.Equal(t, "getBGPInstance", decodeErr.Operation)
+	require.EqualError(t, decodeErr, "response of getBGPInstance does not match the API specification: state: unknown attribute")
+
+	// The HTTP client passed to GetAPIClient is not modified
+	require.Nil(t, http.DefaultClient.Transport)
+}
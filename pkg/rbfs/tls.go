@@ -0,0 +1,253 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+type (
+	// errTransport fails all requests with the error that occurred while applying an option.
+	errTransport struct {
+		err error
+	}
+
+	// certReloader loads a client certificate and reloads it whenever the certificate or key file changes.
+	certReloader struct {
+		certFile string
+		keyFile  string
+
+		mu      sync.Mutex
+		cert    *tls.Certificate
+		modTime time.Time
+	}
+)
+
+func (t errTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+// NewHTTPClient creates a new HTTP client and applies the given options to it. An error is returned if an option
+// cannot be applied. Use this function to create the HTTP client for the elements, services, metrics and alerts
+// clients, which do not accept options, to apply the same TLS, logging and tracing settings as for the API client
+// created by NewAPIClient.
+func NewHTTPClient(options ...Option) (*http.Client, error) {
+	config := state.NewConfiguration()
+	config.HTTPClient = &http.Client{}
+	for _, option := range options {
+		option(config)
+	}
+	if err := transportError(config.HTTPClient.Transport); err != nil {
+		return nil, err
+	}
+	return config.HTTPClient, nil
+}
+
+// CACertFile returns an option to verify the server certificate against the CA certificates of the given PEM file
+// instead of the system certificate pool.
+func CACertFile(path string) Option {
+	return func(c *state.Configuration) {
+		pem, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			setTLSError(c, fmt.Errorf("cannot read CA bundle: %w", err))
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			setTLSError(c, fmt.Errorf("no certificates found in CA bundle %s", path))
+			return
+		}
+		updateTLSConfig(c, func(t *tls.Config) { t.RootCAs = pool })
+	}
+}
+
+// ClientCertificate returns an option to authenticate with the client certificate and key of the given PEM files
+// (mutual TLS). The certificate is reloaded from disk whenever one of the files changes.
+func ClientCertificate(certFile, keyFile string) Option {
+	return func(c *state.Configuration) {
+		reloader := &certReloader{certFile: certFile, keyFile: keyFile}
+		if _, err := reloader.certificate(); err != nil {
+			setTLSError(c, err)
+			return
+		}
+		updateTLSConfig(c, func(t *tls.Config) {
+			t.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return reloader.certificate()
+			}
+		})
+	}
+}
+
+// ServerName returns an option to override the server name sent in the TLS handshake (SNI) and used to verify the
+// server certificate.
+func ServerName(name string) Option {
+	return func(c *state.Configuration) {
+		updateTLSConfig(c, func(t *tls.Config) { t.ServerName = name })
+	}
+}
+
+// InsecureSkipVerify returns an option to disable the server certificate verification.
+// Combine this option with PinnedCertificates to accept self-signed certificates.
+func InsecureSkipVerify() Option {
+	return func(c *state.Configuration) {
+		updateTLSConfig(c, func(t *tls.Config) { t.InsecureSkipVerify = true })
+	}
+}
+
+// PinnedCertificates returns an option to accept only server certificate chains containing a certificate with one of
+// the given SHA-256 fingerprints. A fingerprint is hex encoded, optionally separated by colons.
+func PinnedCertificates(fingerprints ...string) Option {
+	return func(c *state.Configuration) {
+		var pins [][]byte
+		for _, fingerprint := range fingerprints {
+			pin, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+			if err != nil || len(pin) != sha256.Size {
+				setTLSError(c, fmt.Errorf("invalid SHA-256 certificate fingerprint %s", fingerprint))
+				return
+			}
+			pins = append(pins, pin)
+		}
+		updateTLSConfig(c, func(t *tls.Config) {
+			t.VerifyConnection = func(cs tls.ConnectionState) error {
+				for _, cert := range cs.PeerCertificates {
+					sum := sha256.Sum256(cert.Raw)
+					for _, pin := range pins {
+						if bytes.Equal(sum[:], pin) {
+							return nil
+						}
+					}
+				}
+				return errors.New("server certificate does not match any pinned fingerprint")
+			}
+		})
+	}
+}
+
+// updateTLSConfig applies the given update to the TLS settings of the configuration's HTTP client.
+// The HTTP client and its transport are cloned to not affect other users of the HTTP client.
+func updateTLSConfig(c *state.Configuration, update func(*tls.Config)) {
+	client := &http.Client{}
+	if c.HTTPClient != nil {
+		*client = *c.HTTPClient
+	}
+	if transportError(client.Transport) != nil {
+		// Keep reporting the first error
+		return
+	}
+	transport, err := withTLSConfig(client.Transport, update)
+	if err != nil {
+		setTLSError(c, err)
+		return
+	}
+	client.Transport = transport
+	c.HTTPClient = client
+}
+
+// withTLSConfig returns a clone of the given transport with updated TLS settings. The layers added by wrapping
+// options are rebuilt on top of the cloned transport.
+func withTLSConfig(rt http.RoundTripper, update func(*tls.Config)) (http.RoundTripper, error) {
+	var transport *http.Transport
+	switch t := rt.(type) {
+	case *layeredTransport:
+		next, err := withTLSConfig(t.Unwrap(), update)
+		if err != nil {
+			return nil, err
+		}
+		return newLayeredTransport(next, t.wrap), nil
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("cannot apply TLS settings to transport of type %T", t)
+	}
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	update(transport.TLSClientConfig)
+	return transport, nil
+}
+
+// setTLSError replaces the configuration's HTTP client transport by a transport failing all requests with the given
+// error, unless an error has already been reported.
+func setTLSError(c *state.Configuration, err error) {
+	client := &http.Client{}
+	if c.HTTPClient != nil {
+		*client = *c.HTTPClient
+	}
+	if transportError(client.Transport) != nil {
+		return
+	}
+	client.Transport = errTransport{err: err}
+	c.HTTPClient = client
+}
+
+// transportError returns the error reported by an errTransport of the given transport and its wrapped transports.
+func transportError(rt http.RoundTripper) error {
+	for {
+		switch t := rt.(type) {
+		case errTransport:
+			return t.err
+		case *layeredTransport:
+			rt = t.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+func (r *certReloader) certificate() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err == nil && r.cert != nil && !modTime.After(r.modTime) {
+		return r.cert, nil
+	}
+	var cert tls.Certificate
+	if err == nil {
+		cert, err = tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	}
+	if err != nil {
+		if r.cert != nil {
+			// Keep using the previous certificate while the files are being replaced
+			return r.cert, nil
+		}
+		return nil, fmt.Errorf("cannot read client certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return r.cert, nil
+}
+
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_CACertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client, err := NewHTTPClient()
+	require.NoError(t, err)
+	_, err = client.Get(server.URL)
+	require.Error(t, err, "the test server certificate must not be trusted by default")
+
+	caFile := writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	client, err = NewHTTPClient(CACertFile(caFile), ServerName("example.com"))
+	require.NoError(t, err)
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+
+	_, err = NewHTTPClient(CACertFile(filepath.Join(t.TempDir(), "missing.pem")))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewHTTPClient_PinnedCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	client, err := NewHTTPClient(InsecureSkipVerify(), PinnedCertificates(hex.EncodeToString(sum[:])))
+	require.NoError(t, err)
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+
+	other := sha256.Sum256([]byte("other"))
+	client, err = NewHTTPClient(InsecureSkipVerify(), PinnedCertificates(hex.EncodeToString(other[:])))
+	require.NoError(t, err)
+	_, err = client.Get(server.URL)
+	require.ErrorContains(t, err, "server certificate does not match any pinned fingerprint")
+
+	_, err = NewHTTPClient(PinnedCertificates("00:11"))
+	require.EqualError(t, err, "invalid SHA-256 certificate fingerprint 00:11")
+}
+
+func TestNewHTTPClient_ClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	writeClientCertificate(t, certFile, keyFile, "first")
+
+	client, err := NewHTTPClient(InsecureSkipVerify(), ClientCertificate(certFile, keyFile))
+	require.NoError(t, err)
+	require.Equal(t, "first", get(t, client, server.URL))
+
+	// Replace the certificate and force a new TLS handshake
+	writeClientCertificate(t, certFile, keyFile, "second")
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+	client.CloseIdleConnections()
+	require.Equal(t, "second", get(t, client, server.URL))
+
+	_, err = NewHTTPClient(ClientCertificate(filepath.Join(dir, "missing.pem"), keyFile))
+	require.ErrorContains(t, err, "cannot read client certificate")
+}
+
+func TestNewHTTPClient_TLSOptionsAfterWrappingOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	caFile := writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	client, err := NewHTTPClient(Logging(slog.New(slog.NewTextHandler(&buf, nil))), CACertFile(caFile),
+		ServerName("example.com"))
+	require.NoError(t, err)
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	require.Contains(t, buf.String(), "status=200")
+
+	_, err = NewHTTPClient(StrictDecodingErrors(), CACertFile(filepath.Join(t.TempDir(), "missing.pem")))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewAPIClient_TLSError(t *testing.T) {
+	endpoint := mustParse(t, "https://192.168.0.1")
+	_, err := NewAPIClient(&http.Client{}, endpoint, CACertFile(filepath.Join(t.TempDir(), "missing.pem")))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	client, err := NewAPIClient(&http.Client{}, endpoint, ServerName("ctrld"))
+	require.NoError(t, err)
+	require.NotNil(t, client)
+}
+
+func TestGetAPIClient_TLSOptionsDoNotModifyClient(t *testing.T) {
+	client := &http.Client{}
+	GetAPIClient(client, mustParse(t, "https://192.168.0.1"), ServerName("ctrld"))
+	require.Nil(t, client.Transport)
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	response, err := client.Get(url)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	var body [64]byte
+	n, _ := response.Body.Read(body[:])
+	return string(body[:n])
+}
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func writeClientCertificate(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
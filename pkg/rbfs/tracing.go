@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+const (
+	// TraceParentHeader holds the W3C trace context header propagating the span of a request.
+	TraceParentHeader = "traceparent"
+
+	// SpanStatusUnset reports a span without status, i.e. a successful request.
+	SpanStatusUnset = SpanStatus("Unset")
+	// SpanStatusError reports a failed request or an error response.
+	SpanStatusError = SpanStatus("Error")
+
+	// SpanKindClient describes a span of an outgoing request.
+	SpanKindClient = SpanKind("client")
+
+	spanContextKey = contextKey("spanContext")
+)
+
+var traceParentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+type (
+	// SpanStatus describes the status of a span following the OpenTelemetry status codes.
+	SpanStatus string
+
+	// SpanKind describes the kind of a span following the OpenTelemetry span kinds.
+	SpanKind string
+
+	// SpanContext identifies a span within a trace.
+	SpanContext struct {
+		// TraceID holds the hex encoded 16 byte trace ID.
+		TraceID string
+		// SpanID holds the hex encoded 8 byte span ID.
+		SpanID string
+	}
+
+	// Span describes a request following the OpenTelemetry span semantics. The attributes follow the OpenTelemetry
+	// HTTP client semantic conventions (e.g. http.request.method, url.full, http.response.status_code) and add
+	// the RBFS service, operation, element and correlation ID (e.g. rbfs.element).
+	Span struct {
+		SpanContext
+		// ParentSpanID holds the span ID of the parent span or is empty for root spans.
+		ParentSpanID string
+		// Name holds the operation or the HTTP method, if the operation is not known.
+		Name          string
+		Kind          SpanKind
+		StartTime     time.Time
+		EndTime       time.Time
+		Attributes    map[string]interface{}
+		Status        SpanStatus
+		StatusMessage string
+	}
+
+	// SpanExporter receives the spans of completed requests, e.g. to forward them to an OpenTelemetry SDK.
+	SpanExporter interface {
+		ExportSpan(Span)
+	}
+
+	// InMemoryExporter collects the exported spans in memory for tests.
+	InMemoryExporter struct {
+		mu    sync.Mutex
+		spans []Span
+	}
+
+	// tracingTransport records a span of every request.
+	tracingTransport struct {
+		next     http.RoundTripper
+		exporter SpanExporter
+		basePath string
+	}
+)
+
+// ExportSpan stores the given span.
+func (e *InMemoryExporter) ExportSpan(span Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns the stored spans in the order of completion.
+func (e *InMemoryExporter) Spans() []Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Span(nil), e.spans...)
+}
+
+// Reset drops all stored spans.
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+// ContextWithSpanContext returns a copy of the given context carrying the given span as parent of the request
+// spans.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey, sc)
+}
+
+// SpanContextFromContext returns the span carried by the given context.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey).(SpanContext)
+	return sc, ok
+}
+
+// ParseTraceParent parses a W3C trace context traceparent header, e.g. of an incoming request to continue its
+// trace.
+func ParseTraceParent(header string) (SpanContext, error) {
+	match := traceParentPattern.FindStringSubmatch(header)
+	if match == nil {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", header)
+	}
+	return SpanContext{TraceID: match[1], SpanID: match[2]}, nil
+}
+
+// TraceParent returns the W3C trace context traceparent header of the span.
+func (sc SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID)
+}
+
+// Tracing returns an option to record a client span of every request and export it to the given exporter when
+// the request completes. The span continues the trace of the span carried by the request context, if any (see
+// ContextWithSpanContext), and is propagated to RBFS by the traceparent and correlation ID headers.
+func Tracing(exporter SpanExporter) Option {
+	return func(c *state.Configuration) {
+		wrapTransport(c, func(next http.RoundTripper) http.RoundTripper {
+			return &tracingTransport{next: next, exporter: exporter, basePath: basePath(c)}
+		})
+	}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, correlationID := withCorrelationID(req)
+	call := describeCall(req, t.basePath)
+
+	span := Span{
+		SpanContext: SpanContext{TraceID: randomID(16), SpanID: randomID(8)},
+		Name:        call.operation,
+		Kind:        SpanKindClient,
+		StartTime:   time.Now(),
+		Attributes: map[string]interface{}{
+			"http.request.method": req.Method,
+			"url.full":            redactURL(req.URL),
+			"server.address":      req.URL.Hostname(),
+			"rbfs.service":        string(call.service),
+			"rbfs.operation":      call.operation,
+			"rbfs.element":        call.element,
+			"rbfs.correlation_id": correlationID,
+		},
+		Status: SpanStatusUnset,
+	}
+	if span.Name == "" {
+		span.Name = req.Method
+	}
+	if parent, ok := SpanContextFromContext(req.Context()); ok {
+		span.TraceID = parent.TraceID
+		span.ParentSpanID = parent.SpanID
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(TraceParentHeader, span.TraceParent())
+
+	resp, err := t.next.RoundTrip(req)
+	span.EndTime = time.Now()
+	switch {
+	case err != nil:
+		span.Status = SpanStatusError
+		span.StatusMessage = err.Error()
+		span.Attributes["error.type"] = fmt.Sprintf("%T", err)
+	case resp.StatusCode >= 400:
+		span.Status = SpanStatusError
+		span.Attributes["http.response.status_code"] = resp.StatusCode
+		span.Attributes["error.type"] = strconv.Itoa(resp.StatusCode)
+	default:
+		span.Attributes["http.response.status_code"] = resp.StatusCode
+	}
+	t.exporter.ExportSpan(span)
+	return resp, err
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracing(t *testing.T) {
+	server, headers := echoServer(t, http.StatusOK)
+	exporter := &InMemoryExporter{}
+	parent, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	ctx, err := NewRbfsContext(ContextWithSpanContext(context.Background(), parent), mustParse(t, server.URL), "leaf1")
+	require.NoError(t, err)
+	endpoint, err := ctx.GetServiceEndpoint(OpsdServiceName)
+	require.NoError(t, err)
+
+	// Logging and tracing report the same correlation ID
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := GetAPIClient(http.DefaultClient, endpoint, Logging(logger), Tracing(exporter))
+	_, _, err = client.BGPApi.GetBGPInstances(ctx)
+	require.NoError(t, err)
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	require.Equal(t, "getBGPInstances", span.Name)
+	require.Equal(t, SpanKindClient, span.Kind)
+	require.Equal(t, SpanStatusUnset, span.Status)
+	require.Equal(t, parent.TraceID, span.TraceID)
+	require.Equal(t, parent.SpanID, span.ParentSpanID)
+	require.Len(t, span.SpanID, 16)
+	require.False(t, span.EndTime.Before(span.StartTime))
+	require.Equal(t, http.MethodGet, span.Attributes["http.request.method"])
+	require.Equal(t, http.StatusOK, span.Attributes["http.response.status_code"])
+	require.Equal(t, "leaf1", span.Attributes["rbfs.element"])
+	require.Equal(t, headers.Get(CorrelationIDHeader), span.Attributes["rbfs.correlation_id"])
+	require.Equal(t, span.TraceParent(), headers.Get(TraceParentHeader))
+
+	exporter.Reset()
+	require.Empty(t, exporter.Spans())
+}
+
+func TestTracing_Error(t *testing.T) {
+	exporter := &InMemoryExporter{}
+	client := GetAPIClient(http.DefaultClient, mustParse(t, "http://127.0.0.1:1/api/v1"), Tracing(exporter))
+	_, _, err := client.BGPApi.GetBGPInstances(context.Background())
+	require.Error(t, err)
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	require.Equal(t, SpanStatusError, spans[0].Status)
+	require.Equal(t, "getBGPInstances", spans[0].Name)
+	require.Empty(t, spans[0].ParentSpanID)
+	require.Len(t, spans[0].TraceID, 32)
+	require.NotEmpty(t, spans[0].Attributes["error.type"])
+
+	_, err = ParseTraceParent("01-invalid")
+	require.EqualError(t, err, `invalid traceparent "01-invalid"`)
+}
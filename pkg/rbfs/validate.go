@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/openapi"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+type (
+	// FieldError describes a request parameter or body attribute, which does not match the opsd API specification.
+	FieldError struct {
+		// In holds the location of the field: path, query or body.
+		In string
+		// Field holds the parameter name or the path of the body attribute (e.g. peer.ipv4_address).
+		// The field is empty if the request body itself does not match.
+		Field   string
+		Kind    DecodeIssueKind
+		Message string
+	}
+
+	// ValidationError reports the field errors of a request rejected by the request validation.
+	ValidationError struct {
+		// Operation holds the operation ID (e.g. startTrafficCapture).
+		Operation string
+		Fields    []FieldError
+	}
+
+	// validatingTransport validates the requests of the opsd API against the opsd API specification.
+	validatingTransport struct {
+		next     http.RoundTripper
+		basePath string
+	}
+)
+
+func (e FieldError) String() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", e.In, e.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", e.In, e.Field, e.Message)
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		fields = append(fields, field.String())
+	}
+	return fmt.Sprintf("invalid request of %s: %s", e.Operation, strings.Join(fields, "; "))
+}
+
+// ValidateRequests returns an option to validate the path parameters, the query parameters and the JSON body of
+// all requests against the opsd API specification the client has been generated from, before the requests are
+// sent. Requests with missing required fields, values not listed by an enum, numbers out of range or malformed IP
+// addresses are rejected with a *ValidationError listing all field errors.
+func ValidateRequests() Option {
+	return func(c *state.Configuration) {
+		wrapTransport(c, func(next http.RoundTripper) http.RoundTripper {
+			return &validatingTransport{next: next, basePath: basePath(c)}
+		})
+	}
+}
+
+func (t *validatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	spec, err := openapi.Opsd()
+	if err != nil {
+		return nil, err
+	}
+	op, params, ok := spec.Match(req.Method, strings.TrimPrefix(req.URL.Path, t.basePath))
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	violations, err := spec.ValidateRequest(op, params, req.URL.Query(), body)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		fields := make([]FieldError, 0, len(violations))
+		for _, violation := range violations {
+			fields = append(fields, FieldError{
+				In:      violation.In,
+				Field:   violation.Field,
+				Kind:    DecodeIssueKind(violation.Kind),
+				Message: violation.Message,
+			})
+		}
+		return nil, &ValidationError{Operation: op.ID(), Fields: fields}
+	}
+	return t.next.RoundTrip(req)
+}
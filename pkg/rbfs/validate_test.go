@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antihax/optional"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+func TestValidateRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+	client := GetAPIClient(http.DefaultClient, mustParse(t, server.URL+"/api/v1/rbfs/elements/leaf1/services/opsd/proxy"),
+		ValidateRequests())
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		want *ValidationError
+	}{
+		{
+			name: "valid",
+			call: func() error {
+				_, err := client.InterfacesApi.StartTrafficCapture(ctx, "ifp-0/0/1", "BOTH", "capture.pcap")
+				return err
+			},
+		},
+		{
+			name: "enum",
+			call: func() error {
+				_, err := client.InterfacesApi.StartTrafficCapture(ctx, "ifp-0/0/1", "IN", "capture.pcap")
+				return err
+			},
+			want: &ValidationError{Operation: "startTrafficCapture", Fields: []FieldError{
+				{In: "query", Field: "direction", Kind: InvalidValue, Message: "invalid value IN, expected one of INGRESS, EGRESS, BOTH"},
+			}},
+		},
+		{
+			name: "optional enum",
+			call: func() error {
+				_, _, err := client.NeighborsApi.GetNeighbors(ctx, &state.NeighborsApiGetNeighborsOpts{Afi: optional.NewString("ipv5")})
+				return err
+			},
+			want: &ValidationError{Operation: "getNeighbors", Fields: []FieldError{
+				{In: "query", Field: "afi", Kind: InvalidValue, Message: "invalid value ipv5, expected one of ipv4, ipv6"},
+			}},
+		},
+		{
+			name: "body",
+			call: func() error {
+				_, err := client.AccessApi.AddVLANProfile(ctx, state.VlanProfile{OuterVlanMin: 5000}, "ifp-0/0/1",
+					&state.AccessApiAddVLANProfileOpts{InnerVlanMax: optional.NewInt(4095)})
+				return err
+			},
+			want: &ValidationError{Operation: "addVLANProfile", Fields: []FieldError{
+				{In: "body", Field: "outer_vlan_min", Kind: InvalidValue, Message: "value 5000 out of range 1..4094"},
+				{In: "query", Field: "inner_vlan_max", Kind: InvalidValue, Message: "value 4095 out of range 1..4094"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := requests
+			err := tt.call()
+			if tt.want == nil {
+				require.NoError(t, err)
+				require.Equal(t, before+1, requests)
+				return
+			}
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "unexpected error %v", err)
+			require.Equal(t, tt.want, validationErr)
+			require.Equal(t, before, requests)
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Operation: "addVLANProfile", Fields: []FieldError{
+		{In: "body", Kind: MissingField, Message: "missing required request body"},
+		{In: "query", Field: "ifp_name", Kind: MissingField, Message: "missing required parameter"},
+	}}
+	require.EqualError(t, err, "invalid request of addVLANProfile: body: missing required request body; query ifp_name: missing required parameter")
+}
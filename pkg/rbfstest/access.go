@@ -0,0 +1,603 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// vlanRangeParams holds the query parameters identifying the VLAN range of a VLAN profile.
+var vlanRangeParams = []string{"outer_vlan_min", "outer_vlan_max", "inner_vlan_min", "inner_vlan_max"}
+
+// AddVLANProfile adds a VLAN profile to the model, replacing any profile with the same interface and VLAN range.
+func (o *Opsd) AddVLANProfile(profile state.VlanProfile) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.storeVLANProfile(profile)
+}
+
+// VLANProfiles returns all VLAN profiles sorted by interface and VLAN range.
+func (o *Opsd) VLANProfiles() []state.VlanProfile {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.filterVLANProfiles(func(state.VlanProfile) bool { return true })
+}
+
+// AddL2BSAService adds an L2BSA service to the model, replacing any service with the same interface and ANP VLAN.
+func (o *Opsd) AddL2BSAService(service state.L2bsaServiceConfig) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.storeL2BSAService(service)
+}
+
+// L2BSAServices returns all L2BSA services sorted by interface and ANP VLAN.
+func (o *Opsd) L2BSAServices() []state.L2bsaServiceConfig {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.filterL2BSAServices(func(state.L2bsaServiceConfig) bool { return true })
+}
+
+// AddA10NSPEndpoint adds an A10NSP L2X endpoint to the model, replacing any endpoint with the same LAG
+// interface and S-VLAN or S-VLAN range.
+func (o *Opsd) AddA10NSPEndpoint(endpoint state.A10nspConfig) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.storeA10NSPEndpoint(endpoint)
+}
+
+// A10NSPEndpoints returns all A10NSP L2X endpoints sorted by LAG interface and S-VLAN.
+func (o *Opsd) A10NSPEndpoints() []state.A10nspConfig {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.filterA10NSPEndpoints(func(state.A10nspConfig) bool { return true })
+}
+
+func (o *Opsd) handleVLANProfiles() {
+	o.handle(http.MethodGet, "/access/vlan-profiles", o.getVLANProfiles)
+	o.handle(http.MethodPut, "/access/vlan-profiles", o.putVLANProfiles)
+	o.handle(http.MethodPost, "/access/vlan-profiles/add", o.addVLANProfile)
+	o.handle(http.MethodPost, "/access/vlan-profiles/delete", o.deleteVLANProfile)
+	o.handle(http.MethodPost, "/access/vlan-profiles/flush", o.flushVLANProfiles)
+	o.handle(http.MethodPost, "/access/vlan-profiles/batch", o.processVLANProfilesBatch)
+}
+
+func (o *Opsd) handleL2BSA() {
+	o.handle(http.MethodGet, "/l2bsa", o.getL2BSAServices)
+	o.handle(http.MethodPut, "/l2bsa", o.putL2BSAServices)
+	o.handle(http.MethodPost, "/l2bsa/batch", o.processL2BSABatch)
+	o.handle(http.MethodGet, "/l2bsa/{ifp_name}/{anp}", o.getL2BSAService)
+	o.handle(http.MethodPut, "/l2bsa/{ifp_name}/{anp}", o.putL2BSAService)
+	o.handle(http.MethodDelete, "/l2bsa/{ifp_name}/{anp}", o.deleteL2BSAService)
+	o.handle(http.MethodGet, "/l2bsa/{ifp_name}", o.getL2BSAServicesOfInterface)
+	o.handle(http.MethodPut, "/l2bsa/{ifp_name}", o.putL2BSAServicesOfInterface)
+}
+
+func (o *Opsd) handleA10NSP() {
+	o.handle(http.MethodGet, "/a10nsp/l2x", o.getA10NSPEndpoints)
+	o.handle(http.MethodPut, "/a10nsp/l2x", o.putA10NSPEndpoints)
+	o.handle(http.MethodPost, "/a10nsp/l2x/batch", o.processA10NSPBatch)
+	o.handle(http.MethodGet, "/a10nsp/l2x/{lag_interface_name}/{s_vlan_low}-{s_vlan_high}", o.getA10NSPEndpoint)
+	o.handle(http.MethodPut, "/a10nsp/l2x/{lag_interface_name}/{s_vlan_low}-{s_vlan_high}", o.putA10NSPEndpoint)
+	o.handle(http.MethodDelete, "/a10nsp/l2x/{lag_interface_name}/{s_vlan_low}-{s_vlan_high}", o.deleteA10NSPEndpoint)
+	o.handle(http.MethodGet, "/a10nsp/l2x/{lag_interface_name}/{s_vlan}", o.getA10NSPEndpoint)
+	o.handle(http.MethodPut, "/a10nsp/l2x/{lag_interface_name}/{s_vlan}", o.putA10NSPEndpoint)
+	o.handle(http.MethodDelete, "/a10nsp/l2x/{lag_interface_name}/{s_vlan}", o.deleteA10NSPEndpoint)
+	o.handle(http.MethodGet, "/a10nsp/l2x/{lag_interface_name}", o.getA10NSPEndpointsOfLAG)
+	o.handle(http.MethodPut, "/a10nsp/l2x/{lag_interface_name}", o.putA10NSPEndpointsOfLAG)
+}
+
+// VLAN profiles
+
+func sameVLANProfile(a, b state.VlanProfile) bool {
+	return a.IfpName == b.IfpName &&
+		a.OuterVlanMin == b.OuterVlanMin && a.OuterVlanMax == b.OuterVlanMax &&
+		a.InnerVlanMin == b.InnerVlanMin && a.InnerVlanMax == b.InnerVlanMax
+}
+
+func (o *Opsd) storeVLANProfile(profile state.VlanProfile) {
+	for i, candidate := range o.vlanProfiles {
+		if sameVLANProfile(candidate, profile) {
+			o.vlanProfiles[i] = profile
+			return
+		}
+	}
+	o.vlanProfiles = append(o.vlanProfiles, profile)
+}
+
+// removeVLANProfiles removes all profiles matching the given function and returns the number of removed profiles.
+func (o *Opsd) removeVLANProfiles(match func(state.VlanProfile) bool) int {
+	kept := o.vlanProfiles[:0]
+	for _, profile := range o.vlanProfiles {
+		if !match(profile) {
+			kept = append(kept, profile)
+		}
+	}
+	removed := len(o.vlanProfiles) - len(kept)
+	o.vlanProfiles = kept
+	return removed
+}
+
+func (o *Opsd) filterVLANProfiles(match func(state.VlanProfile) bool) []state.VlanProfile {
+	list := make([]state.VlanProfile, 0)
+	for _, profile := range o.vlanProfiles {
+		if match(profile) {
+			list = append(list, profile)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		switch {
+		case a.IfpName != b.IfpName:
+			return a.IfpName < b.IfpName
+		case a.OuterVlanMin != b.OuterVlanMin:
+			return a.OuterVlanMin < b.OuterVlanMin
+		case a.OuterVlanMax != b.OuterVlanMax:
+			return a.OuterVlanMax < b.OuterVlanMax
+		case a.InnerVlanMin != b.InnerVlanMin:
+			return a.InnerVlanMin < b.InnerVlanMin
+		default:
+			return a.InnerVlanMax < b.InnerVlanMax
+		}
+	})
+	return list
+}
+
+// vlanProfileOfInterface returns a function matching the profiles of the interface given in the ifp_name query
+// parameter or all profiles, if the parameter is not set.
+func vlanProfileOfInterface(r *request) func(state.VlanProfile) bool {
+	ifpName, ok := r.query("ifp_name")
+	return func(profile state.VlanProfile) bool {
+		return !ok || profile.IfpName == ifpName
+	}
+}
+
+// vlanRange reads the VLAN range query parameters of the given request.
+// The returned map holds the values of the parameters present in the request.
+func vlanRange(r *request) (map[string]int, error) {
+	values := make(map[string]int)
+	for _, name := range vlanRangeParams {
+		value, ok, err := r.intQuery(name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			values[name] = value
+		}
+	}
+	return values, nil
+}
+
+// vlanRangeValue returns a pointer to the VLAN range attribute of the profile with the given query parameter name.
+func vlanRangeValue(profile *state.VlanProfile, name string) *int {
+	switch name {
+	case "outer_vlan_min":
+		return &profile.OuterVlanMin
+	case "outer_vlan_max":
+		return &profile.OuterVlanMax
+	case "inner_vlan_min":
+		return &profile.InnerVlanMin
+	default:
+		return &profile.InnerVlanMax
+	}
+}
+
+func requiredInterface(r *request) (string, error) {
+	ifpName, ok := r.query("ifp_name")
+	if !ok || ifpName == "" {
+		return "", errorf(http.StatusBadRequest, "missing ifp_name")
+	}
+	return ifpName, nil
+}
+
+func (o *Opsd) getVLANProfiles(r *request) (interface{}, error) {
+	return o.filterVLANProfiles(vlanProfileOfInterface(r)), nil
+}
+
+// putVLANProfiles replaces all profiles, or the profiles of the interface given in the ifp_name query parameter.
+func (o *Opsd) putVLANProfiles(r *request) (interface{}, error) {
+	var profiles []state.VlanProfile
+	if err := r.decode(&profiles); err != nil {
+		return nil, err
+	}
+	ifpName, scoped := r.query("ifp_name")
+	for i := range profiles {
+		if scoped && profiles[i].IfpName == "" {
+			profiles[i].IfpName = ifpName
+		}
+		if scoped && profiles[i].IfpName != ifpName {
+			return nil, errorf(http.StatusBadRequest, "VLAN profile of interface %s does not belong to %s", profiles[i].IfpName, ifpName)
+		}
+	}
+	o.removeVLANProfiles(vlanProfileOfInterface(r))
+	for _, profile := range profiles {
+		o.storeVLANProfile(profile)
+	}
+	return nil, nil
+}
+
+func (o *Opsd) addVLANProfile(r *request) (interface{}, error) {
+	ifpName, err := requiredInterface(r)
+	if err != nil {
+		return nil, err
+	}
+	values, err := vlanRange(r)
+	if err != nil {
+		return nil, err
+	}
+	var profile state.VlanProfile
+	if err := r.decode(&profile); err != nil {
+		return nil, err
+	}
+	profile.IfpName = ifpName
+	for name, value := range values {
+		*vlanRangeValue(&profile, name) = value
+	}
+	o.storeVLANProfile(profile)
+	return nil, nil
+}
+
+// deleteVLANProfile removes the profiles of an interface matching the given VLAN range query parameters.
+func (o *Opsd) deleteVLANProfile(r *request) (interface{}, error) {
+	ifpName, err := requiredInterface(r)
+	if err != nil {
+		return nil, err
+	}
+	values, err := vlanRange(r)
+	if err != nil {
+		return nil, err
+	}
+	removed := o.removeVLANProfiles(func(profile state.VlanProfile) bool {
+		if profile.IfpName != ifpName {
+			return false
+		}
+		for name, value := range values {
+			if *vlanRangeValue(&profile, name) != value {
+				return false
+			}
+		}
+		return true
+	})
+	if removed == 0 {
+		return nil, errorf(http.StatusNotFound, "VLAN profile of interface %s not found", ifpName)
+	}
+	return nil, nil
+}
+
+func (o *Opsd) flushVLANProfiles(r *request) (interface{}, error) {
+	o.removeVLANProfiles(vlanProfileOfInterface(r))
+	return nil, nil
+}
+
+func (o *Opsd) processVLANProfilesBatch(r *request) (interface{}, error) {
+	var batch state.VlanProfileBatch
+	if err := r.decode(&batch); err != nil {
+		return nil, err
+	}
+	for _, profile := range batch.Remove {
+		profile := profile
+		o.removeVLANProfiles(func(candidate state.VlanProfile) bool {
+			return sameVLANProfile(candidate, profile)
+		})
+	}
+	for _, profile := range batch.Store {
+		o.storeVLANProfile(profile)
+	}
+	return nil, nil
+}
+
+// L2BSA services
+
+func sameL2BSAService(a, b state.L2bsaServiceConfig) bool {
+	return a.IfpName == b.IfpName && a.AnpVlan == b.AnpVlan
+}
+
+func (o *Opsd) storeL2BSAService(service state.L2bsaServiceConfig) {
+	for i, candidate := range o.l2bsa {
+		if sameL2BSAService(candidate, service) {
+			o.l2bsa[i] = service
+			return
+		}
+	}
+	o.l2bsa = append(o.l2bsa, service)
+}
+
+func (o *Opsd) removeL2BSAServices(match func(state.L2bsaServiceConfig) bool) int {
+	kept := o.l2bsa[:0]
+	for _, service := range o.l2bsa {
+		if !match(service) {
+			kept = append(kept, service)
+		}
+	}
+	removed := len(o.l2bsa) - len(kept)
+	o.l2bsa = kept
+	return removed
+}
+
+func (o *Opsd) filterL2BSAServices(match func(state.L2bsaServiceConfig) bool) []state.L2bsaServiceConfig {
+	list := make([]state.L2bsaServiceConfig, 0)
+	for _, service := range o.l2bsa {
+		if match(service) {
+			list = append(list, service)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].IfpName != list[j].IfpName {
+			return list[i].IfpName < list[j].IfpName
+		}
+		return list[i].AnpVlan < list[j].AnpVlan
+	})
+	return list
+}
+
+func l2bsaServiceOfInterface(ifpName string) func(state.L2bsaServiceConfig) bool {
+	return func(service state.L2bsaServiceConfig) bool {
+		return service.IfpName == ifpName
+	}
+}
+
+func (o *Opsd) getL2BSAServices(r *request) (interface{}, error) {
+	return o.filterL2BSAServices(func(state.L2bsaServiceConfig) bool { return true }), nil
+}
+
+func (o *Opsd) putL2BSAServices(r *request) (interface{}, error) {
+	var services []state.L2bsaServiceConfig
+	if err := r.decode(&services); err != nil {
+		return nil, err
+	}
+	o.l2bsa = nil
+	for _, service := range services {
+		o.storeL2BSAService(service)
+	}
+	return o.filterL2BSAServices(func(state.L2bsaServiceConfig) bool { return true }), nil
+}
+
+func (o *Opsd) processL2BSABatch(r *request) (interface{}, error) {
+	var batch state.L2bsaServiceConfigBatch
+	if err := r.decode(&batch); err != nil {
+		return nil, err
+	}
+	for _, service := range batch.Remove {
+		service := service
+		o.removeL2BSAServices(func(candidate state.L2bsaServiceConfig) bool {
+			return sameL2BSAService(candidate, service)
+		})
+	}
+	for _, service := range batch.Store {
+		o.storeL2BSAService(service)
+	}
+	return nil, nil
+}
+
+func (o *Opsd) getL2BSAServicesOfInterface(r *request) (interface{}, error) {
+	return o.filterL2BSAServices(l2bsaServiceOfInterface(r.param("ifp_name"))), nil
+}
+
+func (o *Opsd) putL2BSAServicesOfInterface(r *request) (interface{}, error) {
+	ifpName := r.param("ifp_name")
+	var services []state.L2bsaServiceConfig
+	if err := r.decode(&services); err != nil {
+		return nil, err
+	}
+	o.removeL2BSAServices(l2bsaServiceOfInterface(ifpName))
+	for _, service := range services {
+		service.IfpName = ifpName
+		o.storeL2BSAService(service)
+	}
+	return o.filterL2BSAServices(l2bsaServiceOfInterface(ifpName)), nil
+}
+
+func (o *Opsd) getL2BSAService(r *request) (interface{}, error) {
+	anp, err := r.intParam("anp")
+	if err != nil {
+		return nil, err
+	}
+	key := state.L2bsaServiceConfig{IfpName: r.param("ifp_name"), AnpVlan: anp}
+	for _, service := range o.l2bsa {
+		if sameL2BSAService(service, key) {
+			return service, nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "L2BSA service %s/%d not found", key.IfpName, anp)
+}
+
+func (o *Opsd) putL2BSAService(r *request) (interface{}, error) {
+	anp, err := r.intParam("anp")
+	if err != nil {
+		return nil, err
+	}
+	var service state.L2bsaServiceConfig
+	if err := r.decode(&service); err != nil {
+		return nil, err
+	}
+	service.IfpName, service.AnpVlan = r.param("ifp_name"), anp
+	o.storeL2BSAService(service)
+	return nil, nil
+}
+
+func (o *Opsd) deleteL2BSAService(r *request) (interface{}, error) {
+	anp, err := r.intParam("anp")
+	if err != nil {
+		return nil, err
+	}
+	key := state.L2bsaServiceConfig{IfpName: r.param("ifp_name"), AnpVlan: anp}
+	if o.removeL2BSAServices(func(service state.L2bsaServiceConfig) bool { return sameL2BSAService(service, key) }) == 0 {
+		return nil, errorf(http.StatusNotFound, "L2BSA service %s/%d not found", key.IfpName, anp)
+	}
+	return nil, nil
+}
+
+// A10NSP L2X endpoints
+
+func sameA10NSPEndpoint(a, b state.A10nspConfig) bool {
+	return a.LagInterfaceName == b.LagInterfaceName && a.SVlan == b.SVlan &&
+		a.SVlanRangeLow == b.SVlanRangeLow && a.SVlanRangeHigh == b.SVlanRangeHigh
+}
+
+func (o *Opsd) storeA10NSPEndpoint(endpoint state.A10nspConfig) {
+	for i, candidate := range o.a10nsp {
+		if sameA10NSPEndpoint(candidate, endpoint) {
+			o.a10nsp[i] = endpoint
+			return
+		}
+	}
+	o.a10nsp = append(o.a10nsp, endpoint)
+}
+
+func (o *Opsd) removeA10NSPEndpoints(match func(state.A10nspConfig) bool) int {
+	kept := o.a10nsp[:0]
+	for _, endpoint := range o.a10nsp {
+		if !match(endpoint) {
+			kept = append(kept, endpoint)
+		}
+	}
+	removed := len(o.a10nsp) - len(kept)
+	o.a10nsp = kept
+	return removed
+}
+
+func (o *Opsd) filterA10NSPEndpoints(match func(state.A10nspConfig) bool) []state.A10nspConfig {
+	list := make([]state.A10nspConfig, 0)
+	for _, endpoint := range o.a10nsp {
+		if match(endpoint) {
+			list = append(list, endpoint)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		switch {
+		case a.LagInterfaceName != b.LagInterfaceName:
+			return a.LagInterfaceName < b.LagInterfaceName
+		case a.SVlan != b.SVlan:
+			return a.SVlan < b.SVlan
+		default:
+			return a.SVlanRangeLow < b.SVlanRangeLow
+		}
+	})
+	return list
+}
+
+func a10nspEndpointOfLAG(lagName string) func(state.A10nspConfig) bool {
+	return func(endpoint state.A10nspConfig) bool {
+		return endpoint.LagInterfaceName == lagName
+	}
+}
+
+// a10nspKey returns the key of the endpoint addressed by the request path.
+func a10nspKey(r *request) (state.A10nspConfig, error) {
+	key := state.A10nspConfig{LagInterfaceName: r.param("lag_interface_name")}
+	var err error
+	if _, ok := r.params["s_vlan"]; ok {
+		key.SVlan, err = r.intParam("s_vlan")
+		return key, err
+	}
+	if key.SVlanRangeLow, err = r.intParam("s_vlan_low"); err != nil {
+		return key, err
+	}
+	key.SVlanRangeHigh, err = r.intParam("s_vlan_high")
+	return key, err
+}
+
+func a10nspName(key state.A10nspConfig) string {
+	if key.SVlan != 0 {
+		return fmt.Sprintf("%s/%d", key.LagInterfaceName, key.SVlan)
+	}
+	return fmt.Sprintf("%s/%d-%d", key.LagInterfaceName, key.SVlanRangeLow, key.SVlanRangeHigh)
+}
+
+func (o *Opsd) getA10NSPEndpoints(r *request) (interface{}, error) {
+	return o.filterA10NSPEndpoints(func(state.A10nspConfig) bool { return true }), nil
+}
+
+func (o *Opsd) putA10NSPEndpoints(r *request) (interface{}, error) {
+	var endpoints []state.A10nspConfig
+	if err := r.decode(&endpoints); err != nil {
+		return nil, err
+	}
+	o.a10nsp = nil
+	for _, endpoint := range endpoints {
+		o.storeA10NSPEndpoint(endpoint)
+	}
+	return nil, nil
+}
+
+func (o *Opsd) processA10NSPBatch(r *request) (interface{}, error) {
+	var batch state.A10nspConfigBatch
+	if err := r.decode(&batch); err != nil {
+		return nil, err
+	}
+	for _, endpoint := range batch.Remove {
+		endpoint := endpoint
+		o.removeA10NSPEndpoints(func(candidate state.A10nspConfig) bool {
+			return sameA10NSPEndpoint(candidate, endpoint)
+		})
+	}
+	for _, endpoint := range batch.Store {
+		o.storeA10NSPEndpoint(endpoint)
+	}
+	return nil, nil
+}
+
+func (o *Opsd) getA10NSPEndpointsOfLAG(r *request) (interface{}, error) {
+	return o.filterA10NSPEndpoints(a10nspEndpointOfLAG(r.param("lag_interface_name"))), nil
+}
+
+func (o *Opsd) putA10NSPEndpointsOfLAG(r *request) (interface{}, error) {
+	lagName := r.param("lag_interface_name")
+	var endpoints []state.A10nspConfig
+	if err := r.decode(&endpoints); err != nil {
+		return nil, err
+	}
+	o.removeA10NSPEndpoints(a10nspEndpointOfLAG(lagName))
+	for _, endpoint := range endpoints {
+		endpoint.LagInterfaceName = lagName
+		o.storeA10NSPEndpoint(endpoint)
+	}
+	return nil, nil
+}
+
+func (o *Opsd) getA10NSPEndpoint(r *request) (interface{}, error) {
+	key, err := a10nspKey(r)
+	if err != nil {
+		return nil, err
+	}
+	list := o.filterA10NSPEndpoints(func(endpoint state.A10nspConfig) bool {
+		return sameA10NSPEndpoint(endpoint, key)
+	})
+	if len(list) == 0 {
+		return nil, errorf(http.StatusNotFound, "A10NSP endpoint %s not found", a10nspName(key))
+	}
+	return list, nil
+}
+
+func (o *Opsd) putA10NSPEndpoint(r *request) (interface{}, error) {
+	key, err := a10nspKey(r)
+	if err != nil {
+		return nil, err
+	}
+	var endpoint state.A10nspConfig
+	if err := r.decode(&endpoint); err != nil {
+		return nil, err
+	}
+	endpoint.LagInterfaceName, endpoint.SVlan = key.LagInterfaceName, key.SVlan
+	endpoint.SVlanRangeLow, endpoint.SVlanRangeHigh = key.SVlanRangeLow, key.SVlanRangeHigh
+	o.storeA10NSPEndpoint(endpoint)
+	return nil, nil
+}
+
+func (o *Opsd) deleteA10NSPEndpoint(r *request) (interface{}, error) {
+	key, err := a10nspKey(r)
+	if err != nil {
+		return nil, err
+	}
+	if o.removeA10NSPEndpoints(func(endpoint state.A10nspConfig) bool { return sameA10NSPEndpoint(endpoint, key) }) == 0 {
+		return nil, errorf(http.StatusNotFound, "A10NSP endpoint %s not found", a10nspName(key))
+	}
+	return nil, nil
+}
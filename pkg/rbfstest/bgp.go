@@ -0,0 +1,257 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+const bgpResetReason = "Administrative reset"
+
+// AddBGPInstance adds a BGP instance to the model, replacing any instance with the same name.
+// The peerings summary of the instance is computed from the peerings in the model.
+func (o *Opsd) AddBGPInstance(instance state.BgpInstance) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	instance.Peerings = nil
+	o.bgpInstances[instance.InstanceName] = instance
+	if _, ok := o.bgpPeerings[instance.InstanceName]; !ok {
+		o.bgpPeerings[instance.InstanceName] = make(map[string]state.BgpPeering)
+	}
+}
+
+// AddBGPPeering adds a BGP peering to the model, replacing any peering with the same instance and peer address.
+// The peering is identified by the IPv4 or IPv6 address of the peer. The BGP instance is created, if it does
+// not exist. AddBGPPeering panics, if the peering has no peer address.
+func (o *Opsd) AddBGPPeering(peering state.BgpPeering) {
+	peerIP := bgpPeerIP(peering)
+	if peerIP == "" {
+		panic("BGP peering without peer address")
+	}
+	if peering.InstanceName == "" {
+		peering.InstanceName = "default"
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.bgpInstances[peering.InstanceName]; !ok {
+		o.bgpInstances[peering.InstanceName] = state.BgpInstance{InstanceName: peering.InstanceName}
+		o.bgpPeerings[peering.InstanceName] = make(map[string]state.BgpPeering)
+	}
+	o.bgpPeerings[peering.InstanceName][peerIP] = peering
+}
+
+// BGPPeering returns the BGP peering with the given peer address in the given instance.
+func (o *Opsd) BGPPeering(instanceName, peerIP string) (state.BgpPeering, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	peering, ok := o.bgpPeerings[instanceName][peerIP]
+	return peering, ok
+}
+
+func (o *Opsd) handleBGP() {
+	o.handle(http.MethodGet, "/bgp/instances", o.getBGPInstances)
+	o.handle(http.MethodGet, "/bgp/instances/{instance_name}", o.getBGPInstance)
+	o.handle(http.MethodGet, "/bgp/instances/{instance_name}/peerings", o.getBGPInstancePeerings)
+	o.handle(http.MethodPost, "/bgp/instances/{instance_name}/peerings/clear", o.clearBGPInstancePeerings)
+	o.handle(http.MethodGet, "/bgp/instances/{instance_name}/peerings/{peer_ip}", o.getBGPPeering)
+	o.handle(http.MethodPost, "/bgp/instances/{instance_name}/peerings/{peer_ip}/clear", o.clearBGPPeering)
+	o.handle(http.MethodGet, "/bgp/peerings", o.getBGPPeerings)
+}
+
+func bgpPeerIP(peering state.BgpPeering) string {
+	if peering.Peer == nil {
+		return ""
+	}
+	if peering.Peer.Ipv4Address != "" {
+		return peering.Peer.Ipv4Address
+	}
+	return peering.Peer.Ipv6Address
+}
+
+func (o *Opsd) sortedBGPInstances() []state.BgpInstance {
+	list := make([]state.BgpInstance, 0, len(o.bgpInstances))
+	for _, instance := range o.bgpInstances {
+		list = append(list, instance)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].InstanceName < list[j].InstanceName
+	})
+	return list
+}
+
+// sortedBGPPeerings returns the peerings of the given instance sorted by peer address.
+func (o *Opsd) sortedBGPPeerings(instanceName string) []state.BgpPeering {
+	peerings := o.bgpPeerings[instanceName]
+	peerIPs := make([]string, 0, len(peerings))
+	for peerIP := range peerings {
+		peerIPs = append(peerIPs, peerIP)
+	}
+	sort.Strings(peerIPs)
+	list := make([]state.BgpPeering, 0, len(peerIPs))
+	for _, peerIP := range peerIPs {
+		list = append(list, peerings[peerIP])
+	}
+	return list
+}
+
+func (o *Opsd) bgpPeeringsSummary(instanceName string) *state.BgpInstancePeeringsSummary {
+	summary := &state.BgpInstancePeeringsSummary{}
+	for _, peering := range o.sortedBGPPeerings(instanceName) {
+		if peering.BgpState != nil {
+			switch *peering.BgpState {
+			case state.ACTIVE_BgpState:
+				summary.ActiveCount++
+			case state.IDLE_BgpState:
+				summary.IdleCount++
+			case state.CONNECT_BgpState:
+				summary.ConnectCount++
+			case state.OPEN_SENT_BgpState:
+				summary.OpensentCount++
+			case state.OPEN_CONFIRM_BgpState:
+				summary.OpenconfirmCount++
+			case state.ESTABLISHED_BgpState:
+				summary.EstablishedCount++
+			}
+		}
+		summary.Peerings = append(summary.Peerings, state.BgpPeeringRef{
+			AdministrativeState: peering.AdministrativeState,
+			BgpState:            peering.BgpState,
+			LastStateTransition: peering.LastStateTransition,
+			LastResetReason:     peering.LastResetReason,
+			Asn:                 peering.Asn,
+			IflName:             peering.IflName,
+			Ipv6Address:         peering.Ipv6Address,
+			Ipv4Address:         peering.Ipv4Address,
+			PeeringType:         peering.PeeringType,
+			Peer:                peering.Peer,
+		})
+	}
+	return summary
+}
+
+func (o *Opsd) bgpInstance(r *request) (state.BgpInstance, error) {
+	instance, ok := o.bgpInstances[r.param("instance_name")]
+	if !ok {
+		return instance, errorf(http.StatusNotFound, "BGP instance %s not found", r.param("instance_name"))
+	}
+	return instance, nil
+}
+
+func (o *Opsd) getBGPInstances(r *request) (interface{}, error) {
+	list := make([]state.BgpInstanceRef, 0, len(o.bgpInstances))
+	for _, instance := range o.sortedBGPInstances() {
+		list = append(list, state.BgpInstanceRef{
+			InstanceName: instance.InstanceName,
+			Asn:          instance.Asn,
+			RouterId:     instance.RouterId,
+		})
+	}
+	return list, nil
+}
+
+func (o *Opsd) getBGPInstance(r *request) (interface{}, error) {
+	instance, err := o.bgpInstance(r)
+	if err != nil {
+		return nil, err
+	}
+	instance.Peerings = o.bgpPeeringsSummary(instance.InstanceName)
+	return instance, nil
+}
+
+func (o *Opsd) getBGPInstancePeerings(r *request) (interface{}, error) {
+	instance, err := o.bgpInstance(r)
+	if err != nil {
+		return nil, err
+	}
+	peerIP, filterPeer := r.query("peer_ip")
+	sourceIP, filterSource := r.query("source_ip")
+	list := make([]state.BgpPeering, 0)
+	for _, peering := range o.sortedBGPPeerings(instance.InstanceName) {
+		if filterPeer && bgpPeerIP(peering) != peerIP {
+			continue
+		}
+		if filterSource && peering.Ipv4Address != sourceIP && peering.Ipv6Address != sourceIP {
+			continue
+		}
+		list = append(list, peering)
+	}
+	return list, nil
+}
+
+func (o *Opsd) getBGPPeering(r *request) (interface{}, error) {
+	peering, err := o.bgpPeering(r)
+	if err != nil {
+		return nil, err
+	}
+	return peering, nil
+}
+
+func (o *Opsd) bgpPeering(r *request) (state.BgpPeering, error) {
+	instance, err := o.bgpInstance(r)
+	if err != nil {
+		return state.BgpPeering{}, err
+	}
+	peering, ok := o.bgpPeerings[instance.InstanceName][r.param("peer_ip")]
+	if !ok {
+		return peering, errorf(http.StatusNotFound, "BGP peering %s not found", r.param("peer_ip"))
+	}
+	if sourceIP, ok := r.query("source_ip"); ok && peering.Ipv4Address != sourceIP && peering.Ipv6Address != sourceIP {
+		return peering, errorf(http.StatusNotFound, "BGP peering %s with source %s not found", r.param("peer_ip"), sourceIP)
+	}
+	return peering, nil
+}
+
+// clearBGPPeering resets a BGP peering. A reset peering transitions to IDLE.
+func (o *Opsd) clearBGPPeering(r *request) (interface{}, error) {
+	peering, err := o.bgpPeering(r)
+	if err != nil {
+		return nil, err
+	}
+	o.bgpPeerings[peering.InstanceName][bgpPeerIP(peering)] = resetBGPPeering(peering)
+	return nil, nil
+}
+
+// clearBGPInstancePeerings resets all BGP peerings of an instance.
+func (o *Opsd) clearBGPInstancePeerings(r *request) (interface{}, error) {
+	instance, err := o.bgpInstance(r)
+	if err != nil {
+		return nil, err
+	}
+	for peerIP, peering := range o.bgpPeerings[instance.InstanceName] {
+		o.bgpPeerings[instance.InstanceName][peerIP] = resetBGPPeering(peering)
+	}
+	return nil, nil
+}
+
+func resetBGPPeering(peering state.BgpPeering) state.BgpPeering {
+	idle := state.IDLE_BgpState
+	peering.BgpState = &idle
+	peering.LastStateTransition = time.Now().UTC()
+	peering.LastResetReason = bgpResetReason
+	return peering
+}
+
+func (o *Opsd) getBGPPeerings(r *request) (interface{}, error) {
+	list := make([]state.BgpInstancePeerings, 0, len(o.bgpInstances))
+	for _, instance := range o.sortedBGPInstances() {
+		list = append(list, state.BgpInstancePeerings{
+			InstanceName: instance.InstanceName,
+			Asn:          instance.Asn,
+			RouterId:     instance.RouterId,
+			HostName:     instance.HostName,
+			Hostname:     instance.Hostname,
+			DomainName:   instance.DomainName,
+			ClusterId:    instance.ClusterId,
+			Peerings:     o.bgpPeeringsSummary(instance.InstanceName),
+		})
+	}
+	return list, nil
+}
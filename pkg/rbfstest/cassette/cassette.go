@@ -0,0 +1,331 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package cassette records HTTP interactions with CTRLD, opsd and Prometheus once and replays them in tests.
+//
+// A Recorder is an http.RoundTripper. In record mode, it forwards requests to the real transport and stores the
+// requests and responses in a cassette file when stopped. Tokens and subscriber PII are scrubbed before the
+// interactions are stored. In replay mode, it answers requests from the cassette file without any network access.
+// Requests are matched on method, path and normalised query. Identical requests are answered in the recorded
+// order. Scrubbed values are replaced by pseudonyms derived from a keyed hash of the value. The query of a replayed
+// request is scrubbed the same way before matching, so tests replay a cassette with the same input they recorded
+// it with.
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Version holds the version of the cassette file format.
+	Version = 1
+
+	// ModeEnv holds the name of the environment variable selecting the mode of the cassettes used by tests.
+	ModeEnv = "RBFS_CASSETTE_MODE"
+)
+
+const (
+	// ModeReplay answers requests from the cassette.
+	ModeReplay = Mode(iota)
+	// ModeRecord forwards requests to the real transport and records the interactions in the cassette.
+	ModeRecord
+)
+
+type (
+	// Mode describes whether a recorder records or replays interactions.
+	Mode int
+
+	// Cassette holds the recorded interactions.
+	Cassette struct {
+		// Version holds the cassette file format version.
+		Version int `yaml:"version"`
+		// Interactions holds the interactions in the recorded order.
+		Interactions []Interaction `yaml:"interactions"`
+	}
+
+	// Interaction holds a request and the response received for the request.
+	Interaction struct {
+		Request  Request  `yaml:"request"`
+		Response Response `yaml:"response"`
+	}
+
+	// Request describes a recorded request.
+	Request struct {
+		Method string `yaml:"method"`
+		Path   string `yaml:"path"`
+		// Query holds the normalised query.
+		Query string `yaml:"query,omitempty"`
+		Body  string `yaml:"body,omitempty"`
+	}
+
+	// Response describes a recorded response.
+	Response struct {
+		StatusCode int         `yaml:"status"`
+		Header     http.Header `yaml:"header,omitempty"`
+		Body       string      `yaml:"body,omitempty"`
+	}
+
+	// Recorder records or replays HTTP interactions.
+	Recorder struct {
+		name      string
+		mode      Mode
+		transport http.RoundTripper
+		scrubber  *scrubber
+
+		mu       sync.Mutex
+		cassette Cassette
+		used     []bool
+	}
+
+	// Option applies an optional recorder setting.
+	Option func(*Recorder)
+
+	// UnmatchedError reports a replayed request without a matching recorded interaction.
+	UnmatchedError struct {
+		Cassette string
+		Method   string
+		Path     string
+		Query    string
+		// Recorded holds the number of recorded interactions with the same method, path and query.
+		Recorded int
+	}
+)
+
+func (e *UnmatchedError) Error() string {
+	request := e.Method + " " + e.Path
+	if e.Query != "" {
+		request += "?" + e.Query
+	}
+	if e.Recorded > 0 {
+		return fmt.Sprintf("cassette %s: all %d recorded interactions matching %s have been replayed already", e.Cassette, e.Recorded, request)
+	}
+	return fmt.Sprintf("cassette %s: no recorded interaction matches %s", e.Cassette, request)
+}
+
+// Transport sets the transport used to send the requests in record mode. Defaults to http.DefaultTransport.
+func Transport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// ScrubFields adds JSON attributes and query parameters to scrub in addition to the default tokens and
+// subscriber PII.
+func ScrubFields(names ...string) Option {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.scrubber.fields[name] = true
+		}
+	}
+}
+
+// ScrubKey sets the key of the hash the pseudonyms of subscriber PII are derived from. Set a secret key to prevent
+// recovering PII from a cassette by hashing guessed values. A cassette must be replayed with the key it has been
+// recorded with.
+func ScrubKey(key string) Option {
+	return func(r *Recorder) {
+		r.scrubber.key = []byte(key)
+	}
+}
+
+// New creates a recorder for the cassette file with the given name. In replay mode, the cassette file is loaded
+// and must exist.
+func New(name string, mode Mode, options ...Option) (*Recorder, error) {
+	r := &Recorder{
+		name:      name,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		scrubber:  newScrubber(),
+		cassette:  Cassette{Version: Version},
+	}
+	for _, option := range options {
+		option(r)
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cassette: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("cannot decode cassette %s: %w", name, err)
+	}
+	if r.cassette.Version != Version {
+		return nil, fmt.Errorf("cassette %s has unsupported version %d, expected %d", name, r.cassette.Version, Version)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Use creates a recorder for a test. The mode is read from the environment variable RBFS_CASSETTE_MODE, which
+// selects record mode if set to "record" and replay mode otherwise. The recorder is stopped when the test ends.
+func Use(t testing.TB, name string, options ...Option) *Recorder {
+	t.Helper()
+	mode := ModeReplay
+	if os.Getenv(ModeEnv) == "record" {
+		mode = ModeRecord
+	}
+	r, err := New(name, mode, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	return r
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client using the recorder as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Stop writes the recorded interactions to the cassette file in record mode.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := yaml.Marshal(r.cassette)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.name, data, 0o644)
+}
+
+// Unused returns the recorded interactions, which have not been replayed.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// RoundTrip records or replays the given request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  normaliseQuery(r.scrubber.query(req.URL.Query())),
+			Body:   r.scrubber.body(requestBody),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     r.scrubber.header(resp.Header),
+			Body:       r.scrubber.body(responseBody),
+		},
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	query := normaliseQuery(r.scrubber.query(req.URL.Query()))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recorded := 0
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.Request.Method != req.Method || interaction.Request.Path != req.URL.Path || interaction.Request.Query != query {
+			continue
+		}
+		recorded++
+		if r.used[i] {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, &UnmatchedError{
+		Cassette: r.name,
+		Method:   req.Method,
+		Path:     req.URL.Path,
+		Query:    query,
+		Recorded: recorded,
+	}
+}
+
+// normaliseQuery encodes the query with sorted keys and values.
+func normaliseQuery(query url.Values) string {
+	for _, values := range query {
+		sort.Strings(values)
+	}
+	return query.Encode()
+}
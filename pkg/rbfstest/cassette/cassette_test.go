@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cassette
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antihax/optional"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfstest"
+)
+
+func getSubscribers(t *testing.T, r *Recorder, ctx rbfs.RbfsContext, userName string) ([]state.SubscriberSessionRef, error) {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	require.NoError(t, err)
+	refs, _, err := rbfs.GetAPIClient(r.Client(), endpoint).SubscriberApi.GetSubscribers(ctx, &state.SubscriberApiGetSubscribersOpts{
+		SubscriberUserName: optional.NewString(userName),
+		OuterVlan:          optional.NewInt(100),
+	})
+	return refs, err
+}
+
+func TestRecorder(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "testdata", "subscribers.yaml")
+
+	// Record
+	s := rbfstest.NewServer(rbfstest.AccessToken("secret-token"))
+	leaf1 := s.AddElement(elements.Element{ElementName: "leaf1"})
+	leaf1.Opsd.AddSubscriber(state.SubscriberSession{
+		SubscriberId:       1,
+		SubscriberUserName: "alice@isp",
+		ClientMac:          "aa:bb:cc:dd:ee:ff",
+		AgentRemoteId:      "DEU.RTBRICK.1",
+		OuterVlan:          100,
+	})
+	recorder, err := New(name, ModeRecord, Transport(s.Client().Transport))
+	require.NoError(t, err)
+	recorded, err := getSubscribers(t, recorder, s.Context(context.Background(), "leaf1"), "alice@isp")
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	require.Equal(t, "alice@isp", recorded[0].SubscriberUserName)
+	require.NoError(t, recorder.Stop())
+	s.Close()
+
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	for _, secret := range []string{"secret-token", "alice", "aa:bb:cc:dd:ee:ff", "DEU.RTBRICK.1"} {
+		require.NotContains(t, string(data), secret)
+	}
+
+	// Replay
+	ctrld, _ := url.Parse("http://ctrld.invalid")
+	ctx, err := rbfs.NewRbfsContext(context.Background(), ctrld, "leaf1")
+	require.NoError(t, err)
+	replay, err := New(name, ModeReplay)
+	require.NoError(t, err)
+	replayed, err := getSubscribers(t, replay, ctx, "alice@isp")
+	require.NoError(t, err)
+	require.Len(t, replayed, 1)
+	scrubber := newScrubber()
+	pseudonym := func(name, value string) string {
+		pseudonym, _ := scrubber.value(name, value)
+		return pseudonym
+	}
+	require.Equal(t, pseudonym("subscriber_user_name", "alice@isp"), replayed[0].SubscriberUserName)
+	require.Equal(t, pseudonym("client_mac", "aa:bb:cc:dd:ee:ff"), replayed[0].ClientMac)
+	require.Equal(t, pseudonym("agent_remote_id", "DEU.RTBRICK.1"), replayed[0].AgentRemoteId)
+	require.Equal(t, 1, replayed[0].SubscriberId)
+	require.Empty(t, replay.Unused())
+
+	// All matching interactions have been replayed
+	var unmatched *UnmatchedError
+	_, err = getSubscribers(t, replay, ctx, "alice@isp")
+	require.True(t, errors.As(err, &unmatched))
+	require.Equal(t, 1, unmatched.Recorded)
+
+	// No matching interaction
+	_, err = getSubscribers(t, replay, ctx, "bob@isp")
+	require.True(t, errors.As(err, &unmatched))
+	require.Equal(t, 0, unmatched.Recorded)
+	require.Contains(t, err.Error(), "no recorded interaction matches GET /api/v1/rbfs/elements/leaf1/services/opsd/proxy/subscribers?outer_vlan=100&subscriber_user_name="+pseudonym("subscriber_user_name", "bob@isp"))
+
+	// Pseudonyms depend on the scrub key
+	replay, err = New(name, ModeReplay, ScrubKey("other"))
+	require.NoError(t, err)
+	_, err = getSubscribers(t, replay, ctx, "alice@isp")
+	require.True(t, errors.As(err, &unmatched))
+}
+
+func TestNew_Version(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "future.yaml")
+	require.NoError(t, os.WriteFile(name, []byte("version: 2\ninteractions: []\n"), 0o644))
+	_, err := New(name, ModeReplay)
+	require.EqualError(t, err, "cassette "+name+" has unsupported version 2, expected 1")
+
+	_, err = New(filepath.Join(t.TempDir(), "missing.yaml"), ModeReplay)
+	require.Error(t, err)
+}
+
+func TestNormaliseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", ""},
+		{"b=2&a=1", "a=1&b=2"},
+		{"a=2&a=1", "a=1&a=2"},
+		{"a=x%20y", "a=x+y"},
+	}
+	for _, test := range tests {
+		values, err := url.ParseQuery(test.query)
+		require.NoError(t, err)
+		require.Equal(t, test.want, normaliseQuery(values), test.query)
+	}
+}
+
+func TestScrubber_Body(t *testing.T) {
+	s := newScrubber()
+	require.Equal(t, "not json", s.body([]byte("not json")))
+	require.Equal(t, "{\n  \"access_token\": \"REDACTED\",\n  \"items\": [\n    {\n      \"client_mac\": \"02:00:65:34:8b:51\",\n      \"count\": 12345678901234567890\n    }\n  ]\n}\n",
+		s.body([]byte(`{"access_token":"abc","items":[{"client_mac":"aa:bb:cc:dd:ee:ff","count":12345678901234567890}]}`)))
+	// Pseudonyms are deterministic
+	value, _ := s.value("client_mac", "aa:bb:cc:dd:ee:ff")
+	require.Equal(t, "02:00:65:34:8b:51", value)
+	value, _ = s.value("client_mac_matches", "aa:bb:cc:dd:ee:ff")
+	require.Equal(t, "02:00:65:34:8b:51", value)
+	value, _ = newScrubber().value("subscriber_user_name", "alice@isp")
+	require.Equal(t, "subscriber_user_name-1d436450", value)
+}
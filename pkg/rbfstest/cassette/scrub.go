@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cassette
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// Redacted replaces scrubbed tokens and secrets.
+	Redacted = "REDACTED"
+
+	// defaultScrubKey holds the key of the pseudonyms, unless set by ScrubKey.
+	defaultScrubKey = "go-rbfs-client cassette"
+)
+
+var (
+	// secretFields holds the JSON attributes and query parameters replaced by Redacted.
+	secretFields = []string{"access_token", "refresh_token", "id_token", "token", "password", "client_secret"}
+
+	// piiFields holds the JSON attributes and query parameters replaced by stable pseudonyms.
+	piiFields = []string{"subscriber_user_name", "client_mac", "agent_remote_id", "agent_circuit_id", "accounting_session_id"}
+
+	// secretHeaders holds the response headers replaced by Redacted.
+	secretHeaders = []string{"Authorization", "Set-Cookie", "Www-Authenticate"}
+)
+
+// scrubber removes tokens and subscriber PII from interactions. Subscriber PII is replaced by pseudonyms derived
+// from a keyed hash of the value. Hence, the same subscriber is represented by the same pseudonym in all
+// interactions, and replayed requests are scrubbed to the pseudonyms of the recorded requests.
+type scrubber struct {
+	secrets map[string]bool
+	fields  map[string]bool
+	key     []byte
+}
+
+func newScrubber() *scrubber {
+	s := &scrubber{
+		secrets: make(map[string]bool),
+		fields:  make(map[string]bool),
+		key:     []byte(defaultScrubKey),
+	}
+	for _, name := range secretFields {
+		s.secrets[name] = true
+	}
+	for _, name := range piiFields {
+		s.fields[name] = true
+	}
+	return s
+}
+
+// value returns the scrubbed value of the given attribute or query parameter and whether the value was scrubbed.
+// Query parameters with the suffix _matches are scrubbed like the attribute they match.
+func (s *scrubber) value(name, value string) (string, bool) {
+	name = strings.TrimSuffix(name, "_matches")
+	if s.secrets[name] {
+		return Redacted, true
+	}
+	if !s.fields[name] || value == "" {
+		return value, false
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(name + "\x00" + value))
+	sum := mac.Sum(nil)
+	if strings.HasSuffix(name, "mac") {
+		// Locally administered unicast addresses keep the MAC address format
+		return fmt.Sprintf("02:00:%02x:%02x:%02x:%02x", sum[0], sum[1], sum[2], sum[3]), true
+	}
+	return name + "-" + hex.EncodeToString(sum[:4]), true
+}
+
+func (s *scrubber) query(query url.Values) url.Values {
+	scrubbed := make(url.Values, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			value, _ = s.value(name, value)
+			scrubbed.Add(name, value)
+		}
+	}
+	return scrubbed
+}
+
+func (s *scrubber) header(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, name := range secretHeaders {
+		if _, ok := scrubbed[name]; ok {
+			scrubbed.Set(name, Redacted)
+		}
+	}
+	return scrubbed
+}
+
+// body scrubs a JSON body. Other bodies are recorded unchanged. JSON bodies are indented to keep cassettes
+// readable.
+func (s *scrubber) body(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil || decoder.More() {
+		return string(body)
+	}
+	scrubbed, err := json.MarshalIndent(s.json(v), "", "  ")
+	if err != nil {
+		return string(body)
+	}
+	return string(scrubbed) + "\n"
+}
+
+func (s *scrubber) json(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for name, value := range v {
+			if str, ok := value.(string); ok {
+				v[name], _ = s.value(name, str)
+				continue
+			}
+			if s.secrets[name] && value != nil {
+				v[name] = Redacted
+				continue
+			}
+			v[name] = s.json(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = s.json(value)
+		}
+	}
+	return v
+}
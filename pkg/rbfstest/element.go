@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/services"
+)
+
+const serviceStateUp = "UP"
+
+// Element is a fake element managed by the fake CTRLD.
+type Element struct {
+	// Opsd holds the state of the opsd service of the element.
+	Opsd *Opsd
+
+	mu       sync.Mutex
+	info     elements.Element
+	services map[rbfs.ServiceName]http.Handler
+}
+
+func newElement(info elements.Element) *Element {
+	opsd := NewOpsd()
+	return &Element{
+		Opsd: opsd,
+		info: info,
+		services: map[rbfs.ServiceName]http.Handler{
+			rbfs.OpsdServiceName: opsd,
+		},
+	}
+}
+
+// Info returns the element information reported by CTRLD.
+func (e *Element) Info() elements.Element {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.info
+}
+
+// SetState changes the container and operational state of the element.
+// The service proxy rejects requests with 503 Service Unavailable unless the container is RUNNING and the
+// element is UP.
+func (e *Element) SetState(containerState elements.ContainerState, operationalState elements.OperationalState) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.info.ContainerState = containerState
+	e.info.OperationalState = operationalState
+}
+
+// Handle registers the handler of the given service, replacing any existing handler.
+// The handler receives the requests sent to the service proxy with the path relative to the proxy endpoint.
+func (e *Element) Handle(serviceName rbfs.ServiceName, handler http.Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.services[serviceName] = handler
+}
+
+// Services returns the services of the element as reported by CTRLD.
+func (e *Element) Services() []services.Service {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	list := make([]services.Service, 0, len(e.services))
+	for name := range e.services {
+		list = append(list, services.Service{
+			ServiceName:         string(name),
+			AdministrativeState: serviceStateUp,
+			OperationalState:    serviceStateUp,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ServiceName < list[j].ServiceName
+	})
+	return list
+}
+
+func (e *Element) handler(serviceName rbfs.ServiceName) http.Handler {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.services[serviceName]
+}
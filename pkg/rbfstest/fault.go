@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+)
+
+type (
+	// Fault describes an error or a delay injected into all requests matching the fault.
+	Fault struct {
+		// Element restricts the fault to requests addressing the given element. Empty matches all elements.
+		Element string
+		// Service restricts the fault to requests sent to the proxy of the given service.
+		// Empty matches all requests including CTRLD requests.
+		Service rbfs.ServiceName
+		// Method restricts the fault to requests with the given HTTP method. Empty matches all methods.
+		Method string
+		// Path restricts the fault to requests starting with the given path. Service proxy request paths are
+		// relative to the proxy endpoint (e.g. /bgp/instances), CTRLD request paths are absolute.
+		// Empty matches all paths.
+		Path string
+		// Latency delays the matching requests.
+		Latency time.Duration
+		// StatusCode fails the matching requests with the given HTTP status code, unless zero.
+		StatusCode int
+		// Message holds the error message returned with the status code.
+		Message string
+		// Times limits the number of requests affected by the fault. Zero affects all requests.
+		Times int
+	}
+
+	fault struct {
+		Fault
+		hits int
+	}
+)
+
+// Inject injects the given fault into all subsequent matching requests.
+// The returned function removes the fault.
+func (s *Server) Inject(f Fault) (remove func()) {
+	injected := &fault{Fault: f}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, injected)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, candidate := range s.faults {
+			if candidate == injected {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (f *fault) matches(r *http.Request, elementName string, serviceName rbfs.ServiceName, path string) bool {
+	if f.Times > 0 && f.hits >= f.Times {
+		return false
+	}
+	return (f.Element == "" || f.Element == elementName) &&
+		(f.Service == "" || f.Service == serviceName) &&
+		(f.Method == "" || strings.EqualFold(f.Method, r.Method)) &&
+		strings.HasPrefix(path, f.Path)
+}
+
+// applyFaults applies the faults matching the request and reports whether the request shall be processed.
+func (s *Server) applyFaults(w http.ResponseWriter, r *http.Request, elementName string, serviceName rbfs.ServiceName, path string) bool {
+	var latency time.Duration
+	status, message := 0, ""
+
+	s.mu.Lock()
+	for _, f := range s.faults {
+		if !f.matches(r, elementName, serviceName, path) {
+			continue
+		}
+		f.hits++
+		latency += f.Latency
+		if status == 0 && f.StatusCode != 0 {
+			status, message = f.StatusCode, f.Message
+		}
+	}
+	s.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return false
+		}
+	}
+	if status != 0 {
+		if message == "" {
+			message = http.StatusText(status)
+		}
+		writeError(w, status, message)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package fixtures provides sample instances of the opsd models and sample responses of the opsd operations.
+//
+// The samples are generated from the schemas of the opsd OpenAPI specification by go generate. Samples use the
+// examples, defaults and enum values of the specification, if any, and contain every attribute of the model.
+// The samples are valid according to the specification and decode into the types of the state package.
+package fixtures
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+//go:embed models.json operations.json
+var files embed.FS
+
+var (
+	models     map[string]json.RawMessage
+	operations []Operation
+)
+
+// Operation describes an opsd operation and holds its sample request and response.
+type Operation struct {
+	// ID holds the operation ID of the specification (e.g. getBGPInstances).
+	ID     string `json:"operation_id"`
+	Method string `json:"method"`
+	// Path holds the path template relative to the opsd endpoint (e.g. /bgp/instances/{instance_name}).
+	Path string `json:"path"`
+	// Status holds the status code of the success response.
+	Status int `json:"status"`
+	// Request holds the sample request body, if the operation accepts a body.
+	Request json.RawMessage `json:"request,omitempty"`
+	// Response holds the sample response body, if the success response has a body.
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+func init() {
+	data, err := files.ReadFile("models.json")
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, &models); err != nil {
+		panic(err)
+	}
+	data, err = files.ReadFile("operations.json")
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, &operations); err != nil {
+		panic(err)
+	}
+}
+
+// Models returns the names of the models in alphabetical order. Models are named after the schemas of the
+// specification (e.g. BGPInstance).
+func Models() []string {
+	names := make([]string, 0, len(models))
+	for name := range models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Model returns the JSON sample of the model with the given name.
+func Model(name string) (json.RawMessage, bool) {
+	sample, ok := models[name]
+	return sample, ok
+}
+
+// ModelType returns the type of the state package representing the model with the given name. Models describing
+// simple values, like interface names, are not represented by a type.
+func ModelType(name string) (reflect.Type, bool) {
+	t, ok := modelTypes[name]
+	return t, ok
+}
+
+// Decode decodes the sample of the model with the given name into v.
+// Attributes not defined by v are rejected.
+func Decode(name string, v interface{}) error {
+	sample, ok := models[name]
+	if !ok {
+		return fmt.Errorf("unknown model %s", name)
+	}
+	return decodeStrict(sample, v)
+}
+
+// New returns a pointer to a new value of the type representing the model with the given name, populated with
+// the model sample (e.g. *state.BgpInstance for BGPInstance).
+func New(name string) (interface{}, error) {
+	t, ok := modelTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("model %s is not represented by a type", name)
+	}
+	v := reflect.New(t).Interface()
+	if err := Decode(name, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Operations returns the operations sorted by path and method.
+func Operations() []Operation {
+	return append([]Operation(nil), operations...)
+}
+
+// GetOperation returns the operation with the given operation ID.
+func GetOperation(id string) (Operation, bool) {
+	for _, op := range operations {
+		if op.ID == id {
+			return op, true
+		}
+	}
+	return Operation{}, false
+}
+
+// DecodeResponse decodes the sample response into v. Attributes not defined by v are rejected.
+func (op Operation) DecodeResponse(v interface{}) error {
+	if len(op.Response) == 0 {
+		return fmt.Errorf("operation %s has no response body", op.ID)
+	}
+	return decodeStrict(op.Response, v)
+}
+
+func decodeStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package fixtures
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+var (
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	responseType = reflect.TypeOf((*http.Response)(nil))
+)
+
+// apiMethod returns the method of the generated opsd client implementing the given operation.
+func apiMethod(id string) (reflect.Method, bool) {
+	client := reflect.TypeOf(state.APIClient{})
+	name := strings.ToUpper(id[:1]) + id[1:]
+	for i := 0; i < client.NumField(); i++ {
+		field := client.Field(i)
+		if !strings.HasSuffix(field.Name, "Api") {
+			continue
+		}
+		if method, ok := field.Type.MethodByName(name); ok {
+			return method, true
+		}
+	}
+	return reflect.Method{}, false
+}
+
+// requestType returns the type of the request body parameter, which follows the receiver and the context.
+// Optional bodies are passed as untyped option and are not reported.
+func requestType(method reflect.Method) (reflect.Type, bool) {
+	if method.Type.NumIn() < 3 {
+		return nil, false
+	}
+	t := method.Type.In(2)
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice:
+		return t, true
+	default:
+		return nil, false
+	}
+}
+
+// TestModels verifies that all model samples decode into the types of the state package.
+func TestModels(t *testing.T) {
+	require.NotEmpty(t, Models())
+	for _, name := range Models() {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := ModelType(name); !ok {
+				sample, _ := Model(name)
+				require.NotContains(t, string(sample), "{", "model %s is not represented by a type", name)
+				return
+			}
+			v, err := New(name)
+			require.NoError(t, err)
+			require.NotNil(t, v)
+		})
+	}
+}
+
+// TestOperations verifies that all sample requests and responses decode into the types of the generated opsd
+// client methods.
+func TestOperations(t *testing.T) {
+	require.NotEmpty(t, Operations())
+	for _, op := range Operations() {
+		t.Run(op.ID, func(t *testing.T) {
+			require.True(t, op.Status >= 200 && op.Status < 300)
+			method, ok := apiMethod(op.ID)
+			if !ok {
+				// The opsd client is not generated for all operations of the specification
+				t.Skipf("operation %s is not implemented by the opsd client", op.ID)
+			}
+
+			results := method.Type.NumOut()
+			if len(op.Response) > 0 && results == 3 {
+				v := reflect.New(method.Type.Out(0))
+				require.NoError(t, op.DecodeResponse(v.Interface()))
+			}
+			require.Equal(t, contextType, method.Type.In(1))
+			if body, ok := requestType(method); ok && len(op.Request) > 0 {
+				v := reflect.New(body)
+				require.NoError(t, decodeStrict(op.Request, v.Interface()))
+			}
+			require.Equal(t, responseType, method.Type.Out(results-2))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	v, err := New("BGPPeering")
+	require.NoError(t, err)
+	peering := v.(*state.BgpPeering)
+	require.Equal(t, "default", peering.InstanceName)
+	require.Equal(t, "192.0.2.1", peering.Peer.Ipv4Address)
+
+	_, err = New("IfpName")
+	require.Error(t, err)
+
+	op, ok := GetOperation("getBGPInstance")
+	require.True(t, ok)
+	require.Equal(t, "/bgp/instances/{instance_name}", op.Path)
+	var instance state.BgpInstance
+	require.NoError(t, op.DecodeResponse(&instance))
+	require.Equal(t, "default", instance.InstanceName)
+}
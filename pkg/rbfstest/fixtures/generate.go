@@ -0,0 +1,9 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package fixtures
+
+// Generate the samples after updating the OpenAPI specification and regenerating the opsd client.
+//go:generate go run ../../../internal/cmd/fixturegen
@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Code generated by fixturegen. DO NOT EDIT.
+
+package fixtures
+
+import (
+	"reflect"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// modelTypes maps the model names to the types of the state package.
+var modelTypes = map[string]reflect.Type{
+	"A10nspConfig":                    reflect.TypeOf((*state.A10nspConfig)(nil)).Elem(),
+	"A10nspConfigBatch":               reflect.TypeOf((*state.A10nspConfigBatch)(nil)).Elem(),
+	"ARPNDPInstanceNeighbors":         reflect.TypeOf((*state.ArpndpInstanceNeighbors)(nil)).Elem(),
+	"ARPNDPNeighbor":                  reflect.TypeOf((*state.ArpndpNeighbor)(nil)).Elem(),
+	"AccessLineInfo":                  reflect.TypeOf((*state.AccessLineInfo)(nil)).Elem(),
+	"AddressTranslation":              reflect.TypeOf((*state.AddressTranslation)(nil)).Elem(),
+	"BGPAfiSafiStatistics":            reflect.TypeOf((*state.BgpAfiSafiStatistics)(nil)).Elem(),
+	"BGPInstance":                     reflect.TypeOf((*state.BgpInstance)(nil)).Elem(),
+	"BGPInstancePeerings":             reflect.TypeOf((*state.BgpInstancePeerings)(nil)).Elem(),
+	"BGPInstancePeeringsSummary":      reflect.TypeOf((*state.BgpInstancePeeringsSummary)(nil)).Elem(),
+	"BGPInstanceRef":                  reflect.TypeOf((*state.BgpInstanceRef)(nil)).Elem(),
+	"BGPPeer":                         reflect.TypeOf((*state.BgpPeer)(nil)).Elem(),
+	"BGPPeering":                      reflect.TypeOf((*state.BgpPeering)(nil)).Elem(),
+	"BGPPeeringRef":                   reflect.TypeOf((*state.BgpPeeringRef)(nil)).Elem(),
+	"BGPPeeringStatistics":            reflect.TypeOf((*state.BgpPeeringStatistics)(nil)).Elem(),
+	"BGPState":                        reflect.TypeOf((*state.BgpState)(nil)).Elem(),
+	"BGPStatistics":                   reflect.TypeOf((*state.BgpStatistics)(nil)).Elem(),
+	"Chassis":                         reflect.TypeOf((*state.Chassis)(nil)).Elem(),
+	"Fan":                             reflect.TypeOf((*state.Fan)(nil)).Elem(),
+	"FlowspecInstanceRules":           reflect.TypeOf((*state.FlowspecInstanceRules)(nil)).Elem(),
+	"FlowspecRule":                    reflect.TypeOf((*state.FlowspecRule)(nil)).Elem(),
+	"ISISInstance":                    reflect.TypeOf((*state.IsisInstance)(nil)).Elem(),
+	"ISISInstanceInterface":           reflect.TypeOf((*state.IsisInstanceInterface)(nil)).Elem(),
+	"ISISInstanceInterfaces":          reflect.TypeOf((*state.IsisInstanceInterfaces)(nil)).Elem(),
+	"ISISInstanceNeighbor":            reflect.TypeOf((*state.IsisInstanceNeighbor)(nil)).Elem(),
+	"ISISInstanceNeighbors":           reflect.TypeOf((*state.IsisInstanceNeighbors)(nil)).Elem(),
+	"ISISInstanceNeighborsSummary":    reflect.TypeOf((*state.IsisInstanceNeighborsSummary)(nil)).Elem(),
+	"ISISInstanceRef":                 reflect.TypeOf((*state.IsisInstanceRef)(nil)).Elem(),
+	"ISISInterface":                   reflect.TypeOf((*state.IsisInterface)(nil)).Elem(),
+	"ISISInterfaceLevel":              reflect.TypeOf((*state.IsisInterfaceLevel)(nil)).Elem(),
+	"ISISInterfaceSRIDs":              reflect.TypeOf((*state.IsisInterfaceSrids)(nil)).Elem(),
+	"ISISNeighbor":                    reflect.TypeOf((*state.IsisNeighbor)(nil)).Elem(),
+	"IfpSubscriberSessionStatistics":  reflect.TypeOf((*state.IfpSubscriberSessionStatistics)(nil)).Elem(),
+	"InstanceMulticastRoutes":         reflect.TypeOf((*state.InstanceMulticastRoutes)(nil)).Elem(),
+	"InstanceUnicastRoutes":           reflect.TypeOf((*state.InstanceUnicastRoutes)(nil)).Elem(),
+	"L2VPNCrossConnect":               reflect.TypeOf((*state.L2VpnCrossConnect)(nil)).Elem(),
+	"L2VPNCrossConnectState":          reflect.TypeOf((*state.L2VpnCrossConnectState)(nil)).Elem(),
+	"L2VPNInstancePseudowires":        reflect.TypeOf((*state.L2VpnInstancePseudowires)(nil)).Elem(),
+	"L2VPNPseudowire":                 reflect.TypeOf((*state.L2VpnPseudowire)(nil)).Elem(),
+	"L2bsaServiceConfig":              reflect.TypeOf((*state.L2bsaServiceConfig)(nil)).Elem(),
+	"L2bsaServiceConfigBatch":         reflect.TypeOf((*state.L2bsaServiceConfigBatch)(nil)).Elem(),
+	"L2xConfig":                       reflect.TypeOf((*state.L2xConfig)(nil)).Elem(),
+	"LDPCapabilities":                 reflect.TypeOf((*state.LdpCapabilities)(nil)).Elem(),
+	"LDPInstanceRef":                  reflect.TypeOf((*state.LdpInstanceRef)(nil)).Elem(),
+	"LDPInstanceSession":              reflect.TypeOf((*state.LdpInstanceSession)(nil)).Elem(),
+	"LDPInstanceSessions":             reflect.TypeOf((*state.LdpInstanceSessions)(nil)).Elem(),
+	"LDPInstanceSessionsSessions":     reflect.TypeOf((*state.LdpInstanceSessionsSessions)(nil)).Elem(),
+	"LDPInstanceStatistics":           reflect.TypeOf((*state.LdpInstanceStatistics)(nil)).Elem(),
+	"LDPLabelAdvertismentMode":        reflect.TypeOf((*state.LdpLabelAdvertismentMode)(nil)).Elem(),
+	"LDPLabelDistributionControlMode": reflect.TypeOf((*state.LdpLabelDistributionControlMode)(nil)).Elem(),
+	"LDPLabelRetentionMode":           reflect.TypeOf((*state.LdpLabelRetentionMode)(nil)).Elem(),
+	"LDPPeer":                         reflect.TypeOf((*state.LdpPeer)(nil)).Elem(),
+	"LDPRole":                         reflect.TypeOf((*state.LdpRole)(nil)).Elem(),
+	"LDPSession":                      reflect.TypeOf((*state.LdpSession)(nil)).Elem(),
+	"LDPSessionState":                 reflect.TypeOf((*state.LdpSessionState)(nil)).Elem(),
+	"Lane":                            reflect.TypeOf((*state.Lane)(nil)).Elem(),
+	"LinkAggregationGroup":            reflect.TypeOf((*state.LinkAggregationGroup)(nil)).Elem(),
+	"LinkAggregationGroupMember":      reflect.TypeOf((*state.LinkAggregationGroupMember)(nil)).Elem(),
+	"LldpGlobalNeighbor":              reflect.TypeOf((*state.LldpGlobalNeighbor)(nil)).Elem(),
+	"LldpGlobalNeighbors":             reflect.TypeOf((*state.LldpGlobalNeighbors)(nil)).Elem(),
+	"LldpNeighbor":                    reflect.TypeOf((*state.LldpNeighbor)(nil)).Elem(),
+	"LogicalInterface":                reflect.TypeOf((*state.LogicalInterface)(nil)).Elem(),
+	"MCNextHops":                      reflect.TypeOf((*state.McNextHops)(nil)).Elem(),
+	"Mroutes":                         reflect.TypeOf((*state.Mroutes)(nil)).Elem(),
+	"NextHops":                        reflect.TypeOf((*state.NextHops)(nil)).Elem(),
+	"OSPFArea":                        reflect.TypeOf((*state.OspfArea)(nil)).Elem(),
+	"OSPFInstanceDetails":             reflect.TypeOf((*state.OspfInstanceDetails)(nil)).Elem(),
+	"OSPFInstanceGlobal":              reflect.TypeOf((*state.OspfInstanceGlobal)(nil)).Elem(),
+	"OSPFInstanceInterface":           reflect.TypeOf((*state.OspfInstanceInterface)(nil)).Elem(),
+	"OSPFInstanceInterfaces":          reflect.TypeOf((*state.OspfInstanceInterfaces)(nil)).Elem(),
+	"OSPFInstanceNeighbor":            reflect.TypeOf((*state.OspfInstanceNeighbor)(nil)).Elem(),
+	"OSPFInstanceNeighbors":           reflect.TypeOf((*state.OspfInstanceNeighbors)(nil)).Elem(),
+	"OSPFInstanceSummary":             reflect.TypeOf((*state.OspfInstanceSummary)(nil)).Elem(),
+	"OSPFInterface":                   reflect.TypeOf((*state.OspfInterface)(nil)).Elem(),
+	"OSPFNeighbor":                    reflect.TypeOf((*state.OspfNeighbor)(nil)).Elem(),
+	"OSPFv3InstanceDetails":           reflect.TypeOf((*state.Ospfv3InstanceDetails)(nil)).Elem(),
+	"OSPFv3InstanceInterface":         reflect.TypeOf((*state.Ospfv3InstanceInterface)(nil)).Elem(),
+	"OSPFv3InstanceInterfaces":        reflect.TypeOf((*state.Ospfv3InstanceInterfaces)(nil)).Elem(),
+	"OSPFv3InstanceNeighbor":          reflect.TypeOf((*state.Ospfv3InstanceNeighbor)(nil)).Elem(),
+	"OSPFv3InstanceNeighbors":         reflect.TypeOf((*state.Ospfv3InstanceNeighbors)(nil)).Elem(),
+	"OSPFv3InstanceSummary":           reflect.TypeOf((*state.Ospfv3InstanceSummary)(nil)).Elem(),
+	"OSPFv3Interface":                 reflect.TypeOf((*state.Ospfv3Interface)(nil)).Elem(),
+	"OSPFv3Neighbor":                  reflect.TypeOf((*state.Ospfv3Neighbor)(nil)).Elem(),
+	"Optic":                           reflect.TypeOf((*state.Optic)(nil)).Elem(),
+	"OpticModule":                     reflect.TypeOf((*state.OpticModule)(nil)).Elem(),
+	"PIMDesignatedRouter":             reflect.TypeOf((*state.PimDesignatedRouter)(nil)).Elem(),
+	"PIMInstance":                     reflect.TypeOf((*state.PimInstance)(nil)).Elem(),
+	"PIMInstanceInterface":            reflect.TypeOf((*state.PimInstanceInterface)(nil)).Elem(),
+	"PIMInstanceNeighbor":             reflect.TypeOf((*state.PimInstanceNeighbor)(nil)).Elem(),
+	"PIMInstanceRef":                  reflect.TypeOf((*state.PimInstanceRef)(nil)).Elem(),
+	"PIMInterface":                    reflect.TypeOf((*state.PimInterface)(nil)).Elem(),
+	"PIMInterfaces":                   reflect.TypeOf((*state.PimInterfaces)(nil)).Elem(),
+	"PIMMessageStatistics":            reflect.TypeOf((*state.PimMessageStatistics)(nil)).Elem(),
+	"PIMNeighbor":                     reflect.TypeOf((*state.PimNeighbor)(nil)).Elem(),
+	"PIMNeighbors":                    reflect.TypeOf((*state.PimNeighbors)(nil)).Elem(),
+	"PSU":                             reflect.TypeOf((*state.Psu)(nil)).Elem(),
+	"PhysicalInterface":               reflect.TypeOf((*state.PhysicalInterface)(nil)).Elem(),
+	"PhysicalInterfaceCounters":       reflect.TypeOf((*state.PhysicalInterfaceCounters)(nil)).Elem(),
+	"PhysicalInterfaceDetail":         reflect.TypeOf((*state.PhysicalInterfaceDetail)(nil)).Elem(),
+	"PhysicalInterfaceSummary":        reflect.TypeOf((*state.PhysicalInterfaceSummary)(nil)).Elem(),
+	"PhysicalInterfaceSummaryRef":     reflect.TypeOf((*state.PhysicalInterfaceSummaryRef)(nil)).Elem(),
+	"PingArguments":                   reflect.TypeOf((*state.PingArguments)(nil)).Elem(),
+	"PingStatistics":                  reflect.TypeOf((*state.PingStatistics)(nil)).Elem(),
+	"PingStatus":                      reflect.TypeOf((*state.PingStatus)(nil)).Elem(),
+	"RPKIRTRCache":                    reflect.TypeOf((*state.RpkirtrCache)(nil)).Elem(),
+	"RPKIRTRInstance":                 reflect.TypeOf((*state.RpkirtrInstance)(nil)).Elem(),
+	"RPKIRTRRecord":                   reflect.TypeOf((*state.RpkirtrRecord)(nil)).Elem(),
+	"RPKIRTRSession":                  reflect.TypeOf((*state.RpkirtrSession)(nil)).Elem(),
+	"RPKIRTRSessionInfo":              reflect.TypeOf((*state.RpkirtrSessionInfo)(nil)).Elem(),
+	"RouteCounts":                     reflect.TypeOf((*state.RouteCounts)(nil)).Elem(),
+	"Routes":                          reflect.TypeOf((*state.Routes)(nil)).Elem(),
+	"RoutesCount":                     reflect.TypeOf((*state.RoutesCount)(nil)).Elem(),
+	"RoutesSummary":                   reflect.TypeOf((*state.RoutesSummary)(nil)).Elem(),
+	"SRGB":                            reflect.TypeOf((*state.Srgb)(nil)).Elem(),
+	"SubscriberAccessType":            reflect.TypeOf((*state.SubscriberAccessType)(nil)).Elem(),
+	"SubscriberAccountingInfo":        reflect.TypeOf((*state.SubscriberAccountingInfo)(nil)).Elem(),
+	"SubscriberAdjustedAccounting":    reflect.TypeOf((*state.SubscriberAdjustedAccounting)(nil)).Elem(),
+	"SubscriberHistory":               reflect.TypeOf((*state.SubscriberHistory)(nil)).Elem(),
+	"SubscriberQosPolicer":            reflect.TypeOf((*state.SubscriberQosPolicer)(nil)).Elem(),
+	"SubscriberQosProfile":            reflect.TypeOf((*state.SubscriberQosProfile)(nil)).Elem(),
+	"SubscriberQosShaper":             reflect.TypeOf((*state.SubscriberQosShaper)(nil)).Elem(),
+	"SubscriberSession":               reflect.TypeOf((*state.SubscriberSession)(nil)).Elem(),
+	"SubscriberSessionCounters":       reflect.TypeOf((*state.SubscriberSessionCounters)(nil)).Elem(),
+	"SubscriberSessionRef":            reflect.TypeOf((*state.SubscriberSessionRef)(nil)).Elem(),
+	"SubscriberSessionStatistics":     reflect.TypeOf((*state.SubscriberSessionStatistics)(nil)).Elem(),
+	"SubscriberUpdate":                reflect.TypeOf((*state.SubscriberUpdate)(nil)).Elem(),
+	"SystemHardware":                  reflect.TypeOf((*state.SystemHardware)(nil)).Elem(),
+	"TestAaaObject":                   reflect.TypeOf((*state.TestAaaObject)(nil)).Elem(),
+	"Traceroute":                      reflect.TypeOf((*state.Traceroute)(nil)).Elem(),
+	"TracerouteArguments":             reflect.TypeOf((*state.TracerouteArguments)(nil)).Elem(),
+	"TracerouteHop":                   reflect.TypeOf((*state.TracerouteHop)(nil)).Elem(),
+	"Transceiver":                     reflect.TypeOf((*state.Transceiver)(nil)).Elem(),
+	"TransceiverLane":                 reflect.TypeOf((*state.TransceiverLane)(nil)).Elem(),
+	"TransceiverModule":               reflect.TypeOf((*state.TransceiverModule)(nil)).Elem(),
+	"TransceiverModuleInfo":           reflect.TypeOf((*state.TransceiverModuleInfo)(nil)).Elem(),
+	"UserAddressTranslations":         reflect.TypeOf((*state.UserAddressTranslations)(nil)).Elem(),
+	"VLANProfile":                     reflect.TypeOf((*state.VlanProfile)(nil)).Elem(),
+	"VLANProfileBatch":                reflect.TypeOf((*state.VlanProfileBatch)(nil)).Elem(),
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+const (
+	stateUp   = "UP"
+	stateDown = "DOWN"
+)
+
+// AddPhysicalInterface adds a physical interface to the model, replacing any interface with the same name.
+func (o *Opsd) AddPhysicalInterface(ifp state.PhysicalInterfaceDetail) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.physicals[ifp.IfpName] = ifp
+}
+
+// AddLogicalInterface adds a logical interface to the model, replacing any interface with the same name.
+func (o *Opsd) AddLogicalInterface(ifl state.LogicalInterface) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.logicals[ifl.IflName] = ifl
+}
+
+// PhysicalInterface returns the physical interface with the given name.
+func (o *Opsd) PhysicalInterface(ifpName string) (state.PhysicalInterfaceDetail, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ifp, ok := o.physicals[ifpName]
+	return ifp, ok
+}
+
+// LogicalInterface returns the logical interface with the given name.
+func (o *Opsd) LogicalInterface(iflName string) (state.LogicalInterface, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ifl, ok := o.logicals[iflName]
+	return ifl, ok
+}
+
+func (o *Opsd) handleInterfaces() {
+	o.handle(http.MethodGet, "/interfaces", o.getInterfaces)
+	o.handle(http.MethodGet, "/interfaces/physicals", o.getPhysicalInterfaces)
+	o.handle(http.MethodGet, "/interfaces/physicals/{ifp_name}", o.getPhysicalInterface)
+	o.handle(http.MethodPost, "/interfaces/physicals/{ifp_name}/enable", o.setPhysicalInterfaceState(stateUp))
+	o.handle(http.MethodPost, "/interfaces/physicals/{ifp_name}/disable", o.setPhysicalInterfaceState(stateDown))
+	o.handle(http.MethodPost, "/interfaces/physicals/{ifp_name}/clearCounters", o.clearPhysicalInterfaceCounters)
+	o.handle(http.MethodPost, "/interfaces/physicals/{ifp_name}/startCapture", o.capturePhysicalInterface)
+	o.handle(http.MethodPost, "/interfaces/physicals/{ifp_name}/stopCapture", o.capturePhysicalInterface)
+	o.handle(http.MethodGet, "/interfaces/logicals/{ifl_name}", o.getLogicalInterface)
+	o.handle(http.MethodPost, "/interfaces/logicals/{ifl_name}/enable", o.setLogicalInterfaceState(stateUp))
+	o.handle(http.MethodPost, "/interfaces/logicals/{ifl_name}/disable", o.setLogicalInterfaceState(stateDown))
+	o.handle(http.MethodPost, "/interfaces/logicals/{ifl_name}/clearCounters", o.clearLogicalInterfaceCounters)
+	o.handle(http.MethodPost, "/interfaces/logicals/{ifl_name}/startCapture", o.captureLogicalInterface)
+	o.handle(http.MethodPost, "/interfaces/logicals/{ifl_name}/stopCapture", o.captureLogicalInterface)
+	o.handle(http.MethodPost, "/interfaces/{if_name}/enable", o.dispatchInterface(o.setPhysicalInterfaceState(stateUp), o.setLogicalInterfaceState(stateUp)))
+	o.handle(http.MethodPost, "/interfaces/{if_name}/disable", o.dispatchInterface(o.setPhysicalInterfaceState(stateDown), o.setLogicalInterfaceState(stateDown)))
+	o.handle(http.MethodPost, "/interfaces/{if_name}/clearCounters", o.dispatchInterface(o.clearPhysicalInterfaceCounters, o.clearLogicalInterfaceCounters))
+	o.handle(http.MethodPost, "/interfaces/{if_name}/startCapture", o.dispatchInterface(o.capturePhysicalInterface, o.captureLogicalInterface))
+	o.handle(http.MethodPost, "/interfaces/{if_name}/stopCapture", o.dispatchInterface(o.capturePhysicalInterface, o.captureLogicalInterface))
+	o.handle(http.MethodGet, "/interfaces/{ifp_name}", o.getInterface)
+}
+
+func (o *Opsd) sortedPhysicals() []state.PhysicalInterfaceDetail {
+	list := make([]state.PhysicalInterfaceDetail, 0, len(o.physicals))
+	for _, ifp := range o.physicals {
+		list = append(list, ifp)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].IfpName < list[j].IfpName
+	})
+	return list
+}
+
+// logicalsOf returns the logical interfaces of the given physical interface sorted by name.
+func (o *Opsd) logicalsOf(ifpName string) []state.LogicalInterface {
+	var list []state.LogicalInterface
+	for _, ifl := range o.logicals {
+		if ifl.IfpName == ifpName {
+			list = append(list, ifl)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].IflName < list[j].IflName
+	})
+	return list
+}
+
+func (o *Opsd) getInterfaces(r *request) (interface{}, error) {
+	list := make([]state.PhysicalInterfaceSummaryRef, 0, len(o.physicals))
+	for _, ifp := range o.sortedPhysicals() {
+		ref := state.PhysicalInterfaceSummaryRef{
+			IfpName:             ifp.IfpName,
+			IfpAlias:            ifp.IfpAlias,
+			IfpType:             ifp.IfpType,
+			MacAddress:          ifp.MacAddress,
+			Speed:               ifp.Speed,
+			Bandwidth:           ifp.Bandwidth,
+			DuplexMode:          ifp.DuplexMode,
+			MtuSize:             ifp.MtuSize,
+			L2Mtu:               ifp.L2Mtu,
+			L2Mru:               ifp.L2Mru,
+			OperationalState:    ifp.OperationalState,
+			AdministrativeState: ifp.AdministrativeState,
+			UpTime:              ifp.UpTime,
+			Lag:                 ifp.Lag,
+		}
+		// The generated model holds a single logical interface only
+		if ifls := o.logicalsOf(ifp.IfpName); len(ifls) > 0 {
+			ref.Logicals = &state.PhysicalInterfaceSummaryRefLogicals{
+				IflName:             ifls[0].IflName,
+				IflAlias:            ifls[0].IflAlias,
+				IflType:             ifls[0].IflType,
+				AdministrativeState: ifls[0].AdministrativeState,
+				OperationalState:    ifls[0].OperationalState,
+				Ipv4Addresses:       ifls[0].Ipv4Addresses,
+				Ipv6Addresses:       ifls[0].Ipv6Addresses,
+			}
+		}
+		list = append(list, ref)
+	}
+	return list, nil
+}
+
+func (o *Opsd) getInterface(r *request) (interface{}, error) {
+	ifp, ok := o.physicals[r.param("ifp_name")]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "physical interface %s not found", r.param("ifp_name"))
+	}
+	return state.PhysicalInterfaceSummary{
+		IfpName:             ifp.IfpName,
+		IfpAlias:            ifp.IfpAlias,
+		MacAddress:          ifp.MacAddress,
+		Speed:               ifp.Speed,
+		Bandwidth:           ifp.Bandwidth,
+		DuplexMode:          ifp.DuplexMode,
+		MtuSize:             ifp.MtuSize,
+		L2Mtu:               ifp.L2Mtu,
+		L2Mru:               ifp.L2Mru,
+		OperationalState:    ifp.OperationalState,
+		AdministrativeState: ifp.AdministrativeState,
+		UpTime:              ifp.UpTime,
+		Ifls:                o.logicalsOf(ifp.IfpName),
+		Lag:                 ifp.Lag,
+		IfpCounters:         ifp.IfpCounters,
+	}, nil
+}
+
+func (o *Opsd) getPhysicalInterfaces(r *request) (interface{}, error) {
+	counters := r.boolQuery("counters")
+	list := make([]state.PhysicalInterface, 0, len(o.physicals))
+	for _, ifp := range o.sortedPhysicals() {
+		item := state.PhysicalInterface{
+			IfpName:             ifp.IfpName,
+			IfpAlias:            ifp.IfpAlias,
+			IfpIndex:            ifp.IfpIndex,
+			IfpType:             ifp.IfpType,
+			MacAddress:          ifp.MacAddress,
+			Speed:               ifp.Speed,
+			Bandwidth:           ifp.Bandwidth,
+			DuplexMode:          ifp.DuplexMode,
+			MtuSize:             ifp.MtuSize,
+			L2Mtu:               ifp.L2Mtu,
+			L2Mru:               ifp.L2Mru,
+			OperationalState:    ifp.OperationalState,
+			AdministrativeState: ifp.AdministrativeState,
+			FlapCount:           ifp.FlapCount,
+			CarrierTransitions:  ifp.CarrierTransitions,
+			UpTime:              ifp.UpTime,
+			Lag:                 ifp.Lag,
+		}
+		if counters {
+			item.IfpCounters = ifp.IfpCounters
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+func (o *Opsd) getPhysicalInterface(r *request) (interface{}, error) {
+	ifp, ok := o.physicals[r.param("ifp_name")]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "physical interface %s not found", r.param("ifp_name"))
+	}
+	if !r.boolQuery("optics") {
+		ifp.Optics = nil
+	}
+	return ifp, nil
+}
+
+func (o *Opsd) getLogicalInterface(r *request) (interface{}, error) {
+	ifl, ok := o.logicals[r.param("ifl_name")]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "logical interface %s not found", r.param("ifl_name"))
+	}
+	return ifl, nil
+}
+
+// setPhysicalInterfaceState changes the administrative state of a physical interface. The operational state of
+// the physical interface and its logical interfaces follows the administrative state.
+func (o *Opsd) setPhysicalInterfaceState(adminState string) handlerFunc {
+	return func(r *request) (interface{}, error) {
+		name := r.param("ifp_name")
+		ifp, ok := o.physicals[name]
+		if !ok {
+			return nil, errorf(http.StatusNotFound, "physical interface %s not found", name)
+		}
+		ifp.AdministrativeState = adminState
+		ifp.OperationalState = adminState
+		o.physicals[name] = ifp
+		for _, ifl := range o.logicalsOf(name) {
+			if ifl.AdministrativeState != stateDown {
+				ifl.OperationalState = adminState
+				o.logicals[ifl.IflName] = ifl
+			}
+		}
+		return nil, nil
+	}
+}
+
+// setLogicalInterfaceState changes the administrative state of a logical interface. The operational state
+// follows the administrative state, unless the physical interface is down.
+func (o *Opsd) setLogicalInterfaceState(adminState string) handlerFunc {
+	return func(r *request) (interface{}, error) {
+		name := r.param("ifl_name")
+		ifl, ok := o.logicals[name]
+		if !ok {
+			return nil, errorf(http.StatusNotFound, "logical interface %s not found", name)
+		}
+		ifl.AdministrativeState = adminState
+		ifl.OperationalState = adminState
+		if ifp, ok := o.physicals[ifl.IfpName]; ok && ifp.OperationalState == stateDown {
+			ifl.OperationalState = stateDown
+		}
+		o.logicals[name] = ifl
+		return nil, nil
+	}
+}
+
+func (o *Opsd) clearPhysicalInterfaceCounters(r *request) (interface{}, error) {
+	name := r.param("ifp_name")
+	ifp, ok := o.physicals[name]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "physical interface %s not found", name)
+	}
+	if ifp.IfpCounters != nil {
+		ifp.IfpCounters = &state.PhysicalInterfaceCounters{
+			Tx: &state.PhysicalInterfaceCountersTx{},
+			Rx: &state.PhysicalInterfaceCountersRx{},
+		}
+		o.physicals[name] = ifp
+	}
+	return nil, nil
+}
+
+func (o *Opsd) clearLogicalInterfaceCounters(r *request) (interface{}, error) {
+	name := r.param("ifl_name")
+	ifl, ok := o.logicals[name]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "logical interface %s not found", name)
+	}
+	if ifl.IflCounters != nil {
+		ifl.IflCounters = &state.LogicalInterfaceIflCounters{
+			Tx: &state.LogicalInterfaceIflCountersTx{},
+			Rx: &state.LogicalInterfaceIflCountersRx{},
+		}
+		o.logicals[name] = ifl
+	}
+	return nil, nil
+}
+
+// capturePhysicalInterface accepts traffic capture requests for existing physical interfaces.
+func (o *Opsd) capturePhysicalInterface(r *request) (interface{}, error) {
+	if _, ok := o.physicals[r.param("ifp_name")]; !ok {
+		return nil, errorf(http.StatusNotFound, "physical interface %s not found", r.param("ifp_name"))
+	}
+	return nil, nil
+}
+
+// captureLogicalInterface accepts traffic capture requests for existing logical interfaces.
+func (o *Opsd) captureLogicalInterface(r *request) (interface{}, error) {
+	if _, ok := o.logicals[r.param("ifl_name")]; !ok {
+		return nil, errorf(http.StatusNotFound, "logical interface %s not found", r.param("ifl_name"))
+	}
+	return nil, nil
+}
+
+// dispatchInterface forwards requests addressing a physical or logical interface by name to the respective
+// handler.
+func (o *Opsd) dispatchInterface(physical, logical handlerFunc) handlerFunc {
+	return func(r *request) (interface{}, error) {
+		name := r.param("if_name")
+		if _, ok := o.physicals[name]; ok {
+			r.params["ifp_name"] = name
+			return physical(r)
+		}
+		if _, ok := o.logicals[name]; ok {
+			r.params["ifl_name"] = name
+			return logical(r)
+		}
+		return nil, errorf(http.StatusNotFound, "interface %s not found", name)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfstest/fixtures"
+)
+
+// Opsd is a fake opsd service backed by an in-memory model.
+//
+// Opsd implements the interface, subscriber, BGP, VLAN profile, L2BSA and A10NSP resources of the opsd API.
+// Read requests are answered from the model and write requests change the model, so that a test can populate
+// the model, run the code under test and inspect the resulting state. All other opsd operations are answered
+// with 501 Not Implemented, unless ServeSamples has been called.
+type Opsd struct {
+	router router
+
+	mu           sync.Mutex
+	physicals    map[string]state.PhysicalInterfaceDetail
+	logicals     map[string]state.LogicalInterface
+	subscribers  map[int]state.SubscriberSession
+	bgpInstances map[string]state.BgpInstance
+	bgpPeerings  map[string]map[string]state.BgpPeering
+	vlanProfiles []state.VlanProfile
+	l2bsa        []state.L2bsaServiceConfig
+	a10nsp       []state.A10nspConfig
+}
+
+// NewOpsd creates a new fake opsd service with an empty state.
+func NewOpsd() *Opsd {
+	o := &Opsd{
+		physicals:    make(map[string]state.PhysicalInterfaceDetail),
+		logicals:     make(map[string]state.LogicalInterface),
+		subscribers:  make(map[int]state.SubscriberSession),
+		bgpInstances: make(map[string]state.BgpInstance),
+		bgpPeerings:  make(map[string]map[string]state.BgpPeering),
+	}
+	o.handleInterfaces()
+	o.handleSubscribers()
+	o.handleBGP()
+	o.handleVLANProfiles()
+	o.handleL2BSA()
+	o.handleA10NSP()
+	return o
+}
+
+func (o *Opsd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.router.ServeHTTP(w, r)
+}
+
+// handle registers a handler, which is invoked with the model locked.
+func (o *Opsd) handle(method, template string, handler handlerFunc) {
+	o.router.handle(method, template, func(r *request) (interface{}, error) {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		return handler(r)
+	})
+}
+
+// ServeSamples answers all opsd operations not implemented by the model with the sample response of the
+// operation. The samples are provided by package fixtures.
+func (o *Opsd) ServeSamples() {
+	for _, op := range fixtures.Operations() {
+		response := statusResponse{status: op.Status}
+		if len(op.Response) > 0 {
+			response.body = json.RawMessage(op.Response)
+		}
+		o.router.handle(op.Method, op.Path, func(r *request) (interface{}, error) {
+			return response, nil
+		})
+	}
+}
@@ -0,0 +1,250 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package rbfstest provides an in-memory fake of CTRLD and the opsd service of the managed elements to test code
+// using the RBFS clients end to end without a running RBFS instance.
+//
+// A Server serves the CTRLD element and service resources and forwards the service proxy requests to the fake
+// services of the addressed element. Each element provides a stateful opsd model, which can be populated and
+// inspected by the test. Faults and latency can be injected into arbitrary requests.
+package rbfstest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
+)
+
+const (
+	ctrldElementsPath = "/api/v1/ctrld/elements"
+	rbfsElementsPath  = "/api/v1/rbfs/elements"
+)
+
+type (
+	// Server is a fake CTRLD serving the managed elements and their services.
+	Server struct {
+		server      *httptest.Server
+		accessToken string
+		samples     bool
+
+		mu       sync.Mutex
+		elements map[string]*Element
+		faults   []*fault
+	}
+
+	// Option applies an optional server setting.
+	Option func(*Server)
+)
+
+// AccessToken requires all requests to carry the given bearer token.
+// Requests without a valid token are rejected with 401 Unauthorized.
+func AccessToken(token string) Option {
+	return func(s *Server) {
+		s.accessToken = token
+	}
+}
+
+// Samples answers the opsd operations not implemented by the fake opsd with the sample responses of package
+// fixtures instead of 501 Not Implemented.
+func Samples() Option {
+	return func(s *Server) {
+		s.samples = true
+	}
+}
+
+// NewServer starts a new fake CTRLD without any elements.
+// The caller must close the server when done.
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		elements: make(map[string]*Element),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the CTRLD endpoint URL.
+func (s *Server) URL() *url.URL {
+	u, _ := url.Parse(s.server.URL)
+	return u
+}
+
+// Client returns an HTTP client to access the server.
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Context creates an RBFS context to access the given element through this server.
+// The context carries the access token, if the server requires one.
+func (s *Server) Context(ctx context.Context, elementName string, options ...rbfs.RbfsContextOption) rbfs.RbfsContext {
+	options = append([]rbfs.RbfsContextOption{rbfs.RbfsAccessToken(s.accessToken)}, options...)
+	rc, err := rbfs.NewRbfsContext(ctx, s.URL(), elementName, options...)
+	if err != nil {
+		panic(err)
+	}
+	return rc
+}
+
+// AddElement adds an element to the server, replacing any element with the same name.
+// The element container state defaults to RUNNING and the operational state defaults to UP.
+// The element provides an opsd service with an empty state.
+func (s *Server) AddElement(element elements.Element) *Element {
+	if element.ContainerName == "" {
+		element.ContainerName = element.ElementName
+	}
+	if element.ContainerState == "" {
+		element.ContainerState = elements.ContainerStateRunning
+	}
+	if element.OperationalState == "" {
+		element.OperationalState = elements.OperationalStateUp
+	}
+	e := newElement(element)
+	if s.samples {
+		e.Opsd.ServeSamples()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.elements[element.ElementName] = e
+	return e
+}
+
+// Element returns the element with the given name or nil, if the element does not exist.
+func (s *Server) Element(elementName string) *Element {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.elements[elementName]
+}
+
+// RemoveElement removes the element with the given name.
+func (s *Server) RemoveElement(elementName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.elements, elementName)
+}
+
+func (s *Server) listElements() []elements.Element {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]elements.Element, 0, len(s.elements))
+	for _, e := range s.elements {
+		list = append(list, e.Info())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ElementName < list[j].ElementName
+	})
+	return list
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.accessToken != "" && r.Header.Get("Authorization") != "Bearer "+s.accessToken {
+		writeError(w, http.StatusUnauthorized, "missing or invalid access token")
+		return
+	}
+
+	switch {
+	case r.URL.Path == ctrldElementsPath || strings.HasPrefix(r.URL.Path, ctrldElementsPath+"/"):
+		s.serveCtrld(w, r)
+	case strings.HasPrefix(r.URL.Path, rbfsElementsPath+"/"):
+		s.serveProxy(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "resource not found")
+	}
+}
+
+// serveCtrld serves the CTRLD element and service resources.
+func (s *Server) serveCtrld(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, ctrldElementsPath), "/")[1:]
+	elementName := ""
+	if len(segments) > 0 {
+		elementName = segments[0]
+	}
+	if !s.applyFaults(w, r, elementName, "", r.URL.Path) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if len(segments) == 0 {
+		writeJSON(w, http.StatusOK, s.listElements())
+		return
+	}
+	e := s.Element(elementName)
+	if e == nil || len(segments) > 2 || (len(segments) == 2 && segments[1] != "services") {
+		writeError(w, http.StatusNotFound, "resource not found")
+		return
+	}
+	if len(segments) == 1 {
+		writeJSON(w, http.StatusOK, e.Info())
+		return
+	}
+	writeJSON(w, http.StatusOK, e.Services())
+}
+
+// serveProxy forwards a service proxy request to the fake service of the addressed element.
+func (s *Server) serveProxy(w http.ResponseWriter, r *http.Request) {
+	// {element}/services/{service}/proxy/{path}
+	segments := strings.SplitN(strings.TrimPrefix(r.URL.Path, rbfsElementsPath+"/"), "/", 5)
+	if len(segments) < 4 || segments[1] != "services" || segments[3] != "proxy" {
+		writeError(w, http.StatusNotFound, "resource not found")
+		return
+	}
+	elementName, serviceName := segments[0], rbfs.ServiceName(segments[2])
+	servicePath := "/"
+	if len(segments) == 5 {
+		servicePath += segments[4]
+	}
+	if !s.applyFaults(w, r, elementName, serviceName, servicePath) {
+		return
+	}
+
+	e := s.Element(elementName)
+	if e == nil {
+		writeError(w, http.StatusNotFound, "element not found")
+		return
+	}
+	info := e.Info()
+	if info.ContainerState != elements.ContainerStateRunning || info.OperationalState != elements.OperationalStateUp {
+		writeError(w, http.StatusServiceUnavailable, "element is not running")
+		return
+	}
+	handler := e.handler(serviceName)
+	if handler == nil {
+		writeError(w, http.StatusNotFound, "service not found")
+		return
+	}
+
+	proxied := r.Clone(r.Context())
+	proxied.URL.Path = servicePath
+	proxied.URL.RawPath = ""
+	handler.ServeHTTP(w, proxied)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
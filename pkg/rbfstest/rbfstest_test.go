@@ -0,0 +1,298 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/antihax/optional"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/elements"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/services"
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+func newTestServer(t *testing.T, options ...Option) (*Server, *Element) {
+	s := NewServer(options...)
+	t.Cleanup(s.Close)
+	return s, s.AddElement(elements.Element{ElementName: "leaf1", PodName: "pod1"})
+}
+
+func opsdClient(t *testing.T, s *Server, ctx rbfs.RbfsContext) *state.APIClient {
+	endpoint, err := ctx.GetServiceEndpoint(rbfs.OpsdServiceName)
+	require.NoError(t, err)
+	return rbfs.GetAPIClient(s.Client(), endpoint)
+}
+
+func requireStatus(t *testing.T, status int, response *http.Response) {
+	require.NotNil(t, response)
+	require.Equal(t, status, response.StatusCode)
+}
+
+func TestServer_Ctrld(t *testing.T) {
+	s, leaf1 := newTestServer(t, AccessToken("secret"))
+	s.AddElement(elements.Element{ElementName: "spine1", ContainerState: elements.ContainerStateStopper})
+	ctx := s.Context(context.Background(), "leaf1")
+
+	list, err := elements.NewClient(s.Client()).ListElements(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "leaf1", list[0].ElementName)
+	require.Equal(t, elements.ContainerStateRunning, list[0].ContainerState)
+	require.Equal(t, elements.OperationalStateUp, list[0].OperationalState)
+	require.Equal(t, elements.ContainerStateStopper, list[1].ContainerState)
+
+	element, err := elements.NewClient(s.Client()).GetElement(ctx, "leaf1")
+	require.NoError(t, err)
+	require.Equal(t, leaf1.Info(), *element)
+
+	list2, err := services.NewClient(s.Client()).ListServices(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []services.Service{{ServiceName: "opsd", AdministrativeState: "UP", OperationalState: "UP"}}, list2)
+
+	// Missing access token
+	unauthorized, err := rbfs.NewRbfsContext(context.Background(), s.URL(), "leaf1")
+	require.NoError(t, err)
+	_, response, err := opsdClient(t, s, unauthorized).BGPApi.GetBGPInstances(unauthorized)
+	require.Error(t, err)
+	requireStatus(t, http.StatusUnauthorized, response)
+
+	// Stopped element
+	spine1 := s.Context(context.Background(), "spine1")
+	_, response, err = opsdClient(t, s, spine1).BGPApi.GetBGPInstances(spine1)
+	require.Error(t, err)
+	requireStatus(t, http.StatusServiceUnavailable, response)
+
+	// Unknown element
+	unknown := s.Context(context.Background(), "unknown")
+	_, response, err = opsdClient(t, s, unknown).BGPApi.GetBGPInstances(unknown)
+	require.Error(t, err)
+	requireStatus(t, http.StatusNotFound, response)
+}
+
+func TestOpsd_Interfaces(t *testing.T) {
+	s, leaf1 := newTestServer(t)
+	leaf1.Opsd.AddPhysicalInterface(state.PhysicalInterfaceDetail{
+		IfpName:             "ifp-0/0/1",
+		AdministrativeState: "UP",
+		OperationalState:    "UP",
+		IfpCounters: &state.PhysicalInterfaceCounters{
+			Rx: &state.PhysicalInterfaceCountersRx{PacketsReceived: 10},
+		},
+	})
+	leaf1.Opsd.AddLogicalInterface(state.LogicalInterface{
+		IflName:             "ifl-0/0/1/1",
+		IfpName:             "ifp-0/0/1",
+		InstanceName:        "default",
+		AdministrativeState: "UP",
+		OperationalState:    "UP",
+	})
+	ctx := s.Context(context.Background(), "leaf1")
+	api := opsdClient(t, s, ctx).InterfacesApi
+
+	refs, _, err := api.GetInterfaces(ctx)
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	require.Equal(t, "ifl-0/0/1/1", refs[0].Logicals.IflName)
+
+	summary, _, err := api.GetInterface(ctx, "ifp-0/0/1")
+	require.NoError(t, err)
+	require.Len(t, summary.Ifls, 1)
+
+	physicals, _, err := api.GetPhysicalInterfaces(ctx, &state.InterfacesApiGetPhysicalInterfacesOpts{Counters: optional.NewBool(true)})
+	require.NoError(t, err)
+	require.Equal(t, 10, physicals[0].IfpCounters.Rx.PacketsReceived)
+
+	_, err = api.DisablePhysicalInterface(ctx, "ifp-0/0/1")
+	require.NoError(t, err)
+	ifl, _, err := api.GetLogicalInterface(ctx, "ifl-0/0/1/1")
+	require.NoError(t, err)
+	require.Equal(t, "DOWN", ifl.OperationalState)
+
+	_, err = api.EnableInterface(ctx, "ifp-0/0/1")
+	require.NoError(t, err)
+	_, err = api.ClearInterfaceCounters(ctx, "ifp-0/0/1")
+	require.NoError(t, err)
+	ifp, ok := leaf1.Opsd.PhysicalInterface("ifp-0/0/1")
+	require.True(t, ok)
+	require.Equal(t, "UP", ifp.OperationalState)
+	require.Zero(t, ifp.IfpCounters.Rx.PacketsReceived)
+
+	_, response, err := api.GetLogicalInterface(ctx, "ifl-0/0/2/1")
+	require.Error(t, err)
+	requireStatus(t, http.StatusNotFound, response)
+}
+
+func TestOpsd_Subscribers(t *testing.T) {
+	s, leaf1 := newTestServer(t)
+	pppoe := state.PPPO_E_SubscriberAccessType
+	for id, name := range map[int]string{1: "alice@isp", 2: "bob@isp", 3: "carol@other"} {
+		leaf1.Opsd.AddSubscriber(state.SubscriberSession{
+			SubscriberId:       id,
+			SubscriberUserName: name,
+			IfpName:            "ifp-0/0/1",
+			OuterVlan:          100 + id,
+			AccessType:         &pppoe,
+		})
+	}
+	ctx := s.Context(context.Background(), "leaf1")
+	api := opsdClient(t, s, ctx).SubscriberApi
+
+	refs, _, err := api.GetSubscribers(ctx, &state.SubscriberApiGetSubscribersOpts{
+		SubscriberUserNameMatches: optional.NewString("@isp$"),
+	})
+	require.NoError(t, err)
+	require.Len(t, refs, 2)
+
+	refs, _, err = api.GetSubscribers(ctx, &state.SubscriberApiGetSubscribersOpts{OuterVlan: optional.NewInt(103)})
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	require.Equal(t, "carol@other", refs[0].SubscriberUserName)
+
+	session, _, err := api.GetSubscriber(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, "bob@isp", session.SubscriberUserName)
+
+	_, err = api.ClearSubscriber(ctx, 2, nil)
+	require.NoError(t, err)
+	_, response, err := api.GetSubscriber(ctx, 2)
+	require.Error(t, err)
+	requireStatus(t, http.StatusNotFound, response)
+
+	_, err = api.ClearSubscribers(ctx, &state.SubscriberApiClearSubscribersOpts{IfpName: optional.NewString("ifp-0/0/1")})
+	require.NoError(t, err)
+	require.Empty(t, leaf1.Opsd.Subscribers())
+}
+
+func TestOpsd_BGP(t *testing.T) {
+	s, leaf1 := newTestServer(t)
+	established := state.ESTABLISHED_BgpState
+	leaf1.Opsd.AddBGPInstance(state.BgpInstance{InstanceName: "default", Asn: 65000})
+	leaf1.Opsd.AddBGPPeering(state.BgpPeering{
+		InstanceName: "default",
+		BgpState:     &established,
+		Ipv4Address:  "192.0.2.1",
+		Peer:         &state.BgpPeer{Ipv4Address: "192.0.2.2", Asn: 65001},
+	})
+	ctx := s.Context(context.Background(), "leaf1")
+	api := opsdClient(t, s, ctx).BGPApi
+
+	instances, _, err := api.GetBGPInstances(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []state.BgpInstanceRef{{InstanceName: "default", Asn: 65000}}, instances)
+
+	instance, _, err := api.GetBGPInstance(ctx, "default")
+	require.NoError(t, err)
+	require.Equal(t, 1, instance.Peerings.EstablishedCount)
+
+	peering, _, err := api.GetBGPPeering(ctx, "default", "192.0.2.2", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(65001), peering.Peer.Asn)
+
+	_, err = api.ClearBGPPeering(ctx, "default", "192.0.2.2", nil)
+	require.NoError(t, err)
+	peerings, _, err := api.GetBGPPeerings(ctx)
+	require.NoError(t, err)
+	require.Len(t, peerings, 1)
+	require.Equal(t, 1, peerings[0].Peerings.IdleCount)
+	require.Equal(t, bgpResetReason, peerings[0].Peerings.Peerings[0].LastResetReason)
+}
+
+func TestOpsd_Access(t *testing.T) {
+	s, leaf1 := newTestServer(t)
+	ctx := s.Context(context.Background(), "leaf1")
+	client := opsdClient(t, s, ctx)
+
+	_, err := client.AccessApi.AddVLANProfile(ctx, state.VlanProfile{AccessProfileName: "pppoe"}, "ifp-0/0/1",
+		&state.AccessApiAddVLANProfileOpts{OuterVlanMin: optional.NewInt(100), OuterVlanMax: optional.NewInt(199)})
+	require.NoError(t, err)
+	_, err = client.AccessApi.ProcessVLANProfilesBatch(ctx, state.VlanProfileBatch{
+		Store: []state.VlanProfile{{IfpName: "ifp-0/0/2", OuterVlanMin: 1, OuterVlanMax: 10}},
+	})
+	require.NoError(t, err)
+	profiles, _, err := client.AccessApi.GetVLANProfiles(ctx, &state.AccessApiGetVLANProfilesOpts{IfpName: optional.NewString("ifp-0/0/1")})
+	require.NoError(t, err)
+	require.Equal(t, []state.VlanProfile{{IfpName: "ifp-0/0/1", OuterVlanMin: 100, OuterVlanMax: 199, AccessProfileName: "pppoe"}}, profiles)
+	_, err = client.AccessApi.FlushVLANProfiles(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, leaf1.Opsd.VLANProfiles())
+
+	stored, _, err := client.L2BSAApi.StoreL2BSAService(ctx, []state.L2bsaServiceConfig{{AnpVlan: 7}, {AnpVlan: 8}}, "ifp-0/0/1")
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	service, _, err := client.L2BSAApi.GetL2BSAServicesOfANP(ctx, "ifp-0/0/1", 8)
+	require.NoError(t, err)
+	require.Equal(t, state.L2bsaServiceConfig{IfpName: "ifp-0/0/1", AnpVlan: 8}, service)
+	_, err = client.L2BSAApi.RemoveL2BSAServicesForANP(ctx, "ifp-0/0/1", 7)
+	require.NoError(t, err)
+	require.Equal(t, []state.L2bsaServiceConfig{{IfpName: "ifp-0/0/1", AnpVlan: 8}}, leaf1.Opsd.L2BSAServices())
+
+	_, err = client.A10NSPApi.StoreA10NSPL2XEndpoint(ctx, state.A10nspConfig{AnpVlan: 7}, "lag-1", 100)
+	require.NoError(t, err)
+	_, err = client.A10NSPApi.StoreA10NSPL2XRangeEndpoint(ctx, state.A10nspConfig{}, "lag-1", 200, 299)
+	require.NoError(t, err)
+	endpoints, _, err := client.A10NSPApi.GetA10NSPL2XRangeEndpoint(ctx, "lag-1", 200, 299)
+	require.NoError(t, err)
+	require.Equal(t, []state.A10nspConfig{{LagInterfaceName: "lag-1", SVlanRangeLow: 200, SVlanRangeHigh: 299}}, endpoints)
+	_, err = client.A10NSPApi.RemoveA10NSPL2XEndpoint(ctx, "lag-1", 100)
+	require.NoError(t, err)
+	require.Len(t, leaf1.Opsd.A10NSPEndpoints(), 1)
+}
+
+func TestOpsd_NotImplemented(t *testing.T) {
+	s, _ := newTestServer(t)
+	ctx := s.Context(context.Background(), "leaf1")
+
+	_, response, err := opsdClient(t, s, ctx).ISISApi.GetISISInstances(ctx)
+	require.Error(t, err)
+	requireStatus(t, http.StatusNotImplemented, response)
+}
+
+func TestOpsd_ServeSamples(t *testing.T) {
+	s, _ := newTestServer(t, Samples())
+	ctx := s.Context(context.Background(), "leaf1")
+	client := opsdClient(t, s, ctx)
+
+	instances, _, err := client.ISISApi.GetISISInstances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	require.Equal(t, "default", instances[0].InstanceName)
+
+	response, err := client.ISISApi.ClearISISInstanceNeighbors(ctx, "default")
+	require.NoError(t, err)
+	requireStatus(t, http.StatusAccepted, response)
+
+	// Operations implemented by the model are not answered with samples
+	bgp, _, err := client.BGPApi.GetBGPInstances(ctx)
+	require.NoError(t, err)
+	require.Empty(t, bgp)
+}
+
+func TestServer_Inject(t *testing.T) {
+	s, _ := newTestServer(t)
+	ctx := s.Context(context.Background(), "leaf1")
+	api := opsdClient(t, s, ctx).BGPApi
+
+	remove := s.Inject(Fault{Element: "leaf1", Service: rbfs.OpsdServiceName, Path: "/bgp", StatusCode: http.StatusInternalServerError, Times: 1})
+	_, response, err := api.GetBGPInstances(ctx)
+	require.Error(t, err)
+	requireStatus(t, http.StatusInternalServerError, response)
+	_, _, err = api.GetBGPInstances(ctx)
+	require.NoError(t, err)
+	remove()
+
+	s.Inject(Fault{Path: "/bgp", Latency: time.Second})
+	timeout, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ctx = s.Context(timeout, "leaf1")
+	_, _, err = api.GetBGPInstances(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
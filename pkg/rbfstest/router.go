@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rsys-sk/go-rbfs-client/internal/openapi"
+)
+
+type (
+	// request holds an API request and its path parameters.
+	request struct {
+		*http.Request
+		params map[string]string
+	}
+
+	// handlerFunc handles an API request and returns the response body or an error.
+	// A nil body is answered with 204 No Content.
+	handlerFunc func(r *request) (interface{}, error)
+
+	route struct {
+		method   string
+		template *openapi.PathTemplate
+		handler  handlerFunc
+	}
+
+	// router dispatches API requests to the handler of the first route matching the request path.
+	router struct {
+		routes []route
+	}
+
+	// statusResponse is a response body answered with the given HTTP status code instead of 200 OK.
+	// A nil body is answered without content.
+	statusResponse struct {
+		status int
+		body   interface{}
+	}
+
+	// statusError is an error answered with the given HTTP status code.
+	statusError struct {
+		status  int
+		message string
+	}
+)
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, args ...interface{}) error {
+	return &statusError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// handle registers a handler for the given method and path template (e.g. /l2bsa/{ifp_name}/{anp}).
+func (rt *router) handle(method, template string, handler handlerFunc) {
+	rt.routes = append(rt.routes, route{
+		method:   method,
+		template: openapi.CompilePathTemplate(template),
+		handler:  handler,
+	})
+}
+
+func (rt *router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pathMatched := false
+	for _, route := range rt.routes {
+		params, ok := route.template.Match(r.URL.Path)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if route.method != r.Method {
+			continue
+		}
+
+		req := &request{Request: r, params: params}
+		body, err := route.handler(req)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if se, ok := err.(*statusError); ok {
+				status = se.status
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		if response, ok := body.(statusResponse); ok {
+			if response.body == nil {
+				w.WriteHeader(response.status)
+				return
+			}
+			writeJSON(w, response.status, response.body)
+			return
+		}
+		if body == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, body)
+		return
+	}
+
+	if pathMatched {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeError(w, http.StatusNotImplemented, fmt.Sprintf("%s %s is not implemented by rbfstest", r.Method, r.URL.Path))
+}
+
+func (r *request) param(name string) string {
+	return r.params[name]
+}
+
+func (r *request) intParam(name string) (int, error) {
+	value, err := strconv.Atoi(r.params[name])
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "invalid %s: %s", name, r.params[name])
+	}
+	return value, nil
+}
+
+// query returns the value of the given query parameter and whether the parameter is set.
+func (r *request) query(name string) (string, bool) {
+	values, ok := r.URL.Query()[name]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// intQuery returns the value of the given integer query parameter and whether the parameter is set.
+func (r *request) intQuery(name string) (int, bool, error) {
+	value, ok := r.query(name)
+	if !ok {
+		return 0, false, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false, errorf(http.StatusBadRequest, "invalid %s: %s", name, value)
+	}
+	return i, true, nil
+}
+
+// boolQuery reports whether the given boolean query parameter is set to true.
+func (r *request) boolQuery(name string) bool {
+	value, _ := r.query(name)
+	return strings.EqualFold(value, "true")
+}
+
+func (r *request) decode(v interface{}) error {
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	return nil
+}
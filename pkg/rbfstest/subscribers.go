@@ -0,0 +1,210 @@
+/*
+ * Copyright (C) 2021, RtBrick, Inc.
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package rbfstest
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/rsys-sk/go-rbfs-client/pkg/rbfs/state"
+)
+
+// subscriberFilter reports whether a subscriber session matches the filter query parameters of a request.
+type subscriberFilter func(state.SubscriberSession) bool
+
+// AddSubscriber adds a subscriber session to the model, replacing any session with the same subscriber ID.
+func (o *Opsd) AddSubscriber(session state.SubscriberSession) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.subscribers[session.SubscriberId] = session
+}
+
+// Subscriber returns the subscriber session with the given subscriber ID.
+func (o *Opsd) Subscriber(subscriberID int) (state.SubscriberSession, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	session, ok := o.subscribers[subscriberID]
+	return session, ok
+}
+
+// Subscribers returns all subscriber sessions sorted by subscriber ID.
+func (o *Opsd) Subscribers() []state.SubscriberSession {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.sortedSubscribers()
+}
+
+func (o *Opsd) handleSubscribers() {
+	o.handle(http.MethodGet, "/subscribers", o.getSubscribers)
+	o.handle(http.MethodPost, "/subscribers/terminate", o.terminateSubscribers)
+	o.handle(http.MethodGet, "/subscribers/{subscriber_id}", o.getSubscriber)
+	o.handle(http.MethodDelete, "/subscribers/{subscriber_id}", o.terminateSubscriber)
+}
+
+func (o *Opsd) sortedSubscribers() []state.SubscriberSession {
+	list := make([]state.SubscriberSession, 0, len(o.subscribers))
+	for _, session := range o.subscribers {
+		list = append(list, session)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].SubscriberId < list[j].SubscriberId
+	})
+	return list
+}
+
+func (o *Opsd) getSubscribers(r *request) (interface{}, error) {
+	filter, err := newSubscriberFilter(r)
+	if err != nil {
+		return nil, err
+	}
+	limit, hasLimit, err := r.intQuery("limit")
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]state.SubscriberSessionRef, 0)
+	for _, session := range o.sortedSubscribers() {
+		if hasLimit && len(list) >= limit {
+			break
+		}
+		if !filter(session) {
+			continue
+		}
+		ref := state.SubscriberSessionRef{
+			SubscriberId:       session.SubscriberId,
+			SubscriberIdStr:    session.SubscriberIdStr,
+			SubscriberUserName: session.SubscriberUserName,
+			SubscriberState:    session.SubscriberState,
+			AccessType:         session.AccessType,
+			IfpName:            session.IfpName,
+			InnerVlan:          session.InnerVlan,
+			OuterVlan:          session.OuterVlan,
+			ClientMac:          session.ClientMac,
+			AgentRemoteId:      session.AgentRemoteId,
+			AgentCircuitId:     session.AgentCircuitId,
+		}
+		if session.Accounting != nil {
+			ref.AccountingSessionId = session.Accounting.AccountingSessionId
+		}
+		list = append(list, ref)
+	}
+	return list, nil
+}
+
+func (o *Opsd) getSubscriber(r *request) (interface{}, error) {
+	id, err := r.intParam("subscriber_id")
+	if err != nil {
+		return nil, err
+	}
+	session, ok := o.subscribers[id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "subscriber %d not found", id)
+	}
+	return session, nil
+}
+
+func (o *Opsd) terminateSubscriber(r *request) (interface{}, error) {
+	id, err := r.intParam("subscriber_id")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := o.subscribers[id]; !ok {
+		return nil, errorf(http.StatusNotFound, "subscriber %d not found", id)
+	}
+	delete(o.subscribers, id)
+	return nil, nil
+}
+
+func (o *Opsd) terminateSubscribers(r *request) (interface{}, error) {
+	filter, err := newSubscriberFilter(r)
+	if err != nil {
+		return nil, err
+	}
+	for id, session := range o.subscribers {
+		if filter(session) {
+			delete(o.subscribers, id)
+		}
+	}
+	return nil, nil
+}
+
+// newSubscriberFilter creates a filter from the subscriber filter query parameters of the given request.
+func newSubscriberFilter(r *request) (subscriberFilter, error) {
+	if _, ok := r.query("anp_vlan"); ok {
+		return nil, errorf(http.StatusNotImplemented, "anp_vlan filter is not implemented by rbfstest")
+	}
+
+	var filters []subscriberFilter
+	stringFilters := map[string]func(state.SubscriberSession) string{
+		"ifp_name":             func(s state.SubscriberSession) string { return s.IfpName },
+		"ifl_name":             func(s state.SubscriberSession) string { return s.IflName },
+		"subscriber_user_name": func(s state.SubscriberSession) string { return s.SubscriberUserName },
+		"agent_remote_id":      func(s state.SubscriberSession) string { return s.AgentRemoteId },
+		"agent_circuit_id":     func(s state.SubscriberSession) string { return s.AgentCircuitId },
+		"access_type": func(s state.SubscriberSession) string {
+			if s.AccessType == nil {
+				return ""
+			}
+			return string(*s.AccessType)
+		},
+		"accounting_session_id": func(s state.SubscriberSession) string {
+			if s.Accounting == nil {
+				return ""
+			}
+			return s.Accounting.AccountingSessionId
+		},
+	}
+	for name, attribute := range stringFilters {
+		attribute := attribute
+		if value, ok := r.query(name); ok {
+			filters = append(filters, func(s state.SubscriberSession) bool {
+				return attribute(s) == value
+			})
+		}
+
+		// Only some attributes support regular expression filters, but the others are not expected to be set
+		pattern, ok := r.query(name + "_matches")
+		if !ok {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid %s_matches: %v", name, err)
+		}
+		filters = append(filters, func(s state.SubscriberSession) bool {
+			return re.MatchString(attribute(s))
+		})
+	}
+
+	intFilters := map[string]func(state.SubscriberSession) int{
+		"outer_vlan":    func(s state.SubscriberSession) int { return s.OuterVlan },
+		"inner_vlan":    func(s state.SubscriberSession) int { return s.InnerVlan },
+		"subscriber_id": func(s state.SubscriberSession) int { return s.SubscriberId },
+	}
+	for name, attribute := range intFilters {
+		value, ok, err := r.intQuery(name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		attribute := attribute
+		filters = append(filters, func(s state.SubscriberSession) bool {
+			return attribute(s) == value
+		})
+	}
+
+	return func(s state.SubscriberSession) bool {
+		for _, filter := range filters {
+			if !filter(s) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}